@@ -0,0 +1,193 @@
+// Package tsuniqid - Rate-adaptive counter width for bursty workloads
+package tsuniqid
+
+import (
+	"errors"
+	"sync/atomic"
+	"time"
+)
+
+// Bit allocation constants for adaptive IDs.
+//
+// Adaptive IDs use the regular machine/instance/timestamp layout, but reserve
+// the top counter bit as a burst flag:
+// - Normal mode (flag clear): 42-bit millisecond timestamp, 13-bit counter
+// - Burst mode (flag set): the low 3 timestamp bits are borrowed by the
+// counter, giving a 39-bit timestamp in 8ms windows and a 16-bit counter
+const (
+	// AdaptiveBurstFlag marks an adaptive ID generated in burst mode (bit 13)
+	AdaptiveBurstFlag = 1 << 13
+
+	// MaxAdaptiveCounter represents the maximum normal-mode counter value (13 bits)
+	MaxAdaptiveCounter = 0x1fff
+
+	// AdaptiveWindowBits is the number of timestamp bits borrowed in burst mode
+	AdaptiveWindowBits = 3
+
+	// MaxAdaptiveBurstCounter represents the maximum burst-mode counter value (16 bits)
+	MaxAdaptiveBurstCounter = 0xffff
+
+	// adaptiveWindowMask selects the borrowed timestamp bits
+	adaptiveWindowMask = 1<<AdaptiveWindowBits - 1
+)
+
+// ErrAdaptiveUnsupported is returned by GenerateAdaptive on generators whose
+// IDs do not use the plain default layout with millisecond timestamps, where
+// an adaptive ID would not match the bit allocation its decoders expect.
+var ErrAdaptiveUnsupported = errors.New("adaptive IDs need the default layout with millisecond timestamps and without parity, interleaving, insert spreading or environment/service tags")
+
+// GenerateAdaptive creates a unique uint64 identifier whose counter widens
+// during bursts.
+//
+// While idle, IDs keep full millisecond precision with a 13-bit counter. When
+// the counter saturates within a millisecond, the generator switches to burst
+// mode for the rest of the current 8ms window: the timestamp is coarsened to
+// the window and its 3 low bits extend the counter to 16 bits, allowing
+// 65536 IDs per window. The generator returns to normal mode as soon as the
+// clock enters the next window, and blocks if a burst window is exhausted.
+// Burst IDs carry AdaptiveBurstFlag, so they never collide with normal IDs.
+//
+// Adaptive IDs must be decoded with DecodeAdaptive, and they only sort
+// chronologically across windows, not within a burst window. They must not
+// be mixed with other uint64 IDs from the same generator, whose counters can
+// overlap.
+//
+// Only generators with the default layout, millisecond timestamps and no
+// parity bit, bit interleaving, insert spreading, environment tag or service
+// code can generate adaptive IDs.
+//
+// Returns:
+//   - uint64: A unique uint64 identifier
+//   - error: ErrAdaptiveUnsupported if the generator cannot generate
+//     adaptive IDs
+func (g *IDGenerator) GenerateAdaptive() (uint64, error) {
+	if g.layout != DefaultLayout || g.resolution != time.Millisecond ||
+		g.parity || g.interleave || g.spreadBits > 0 || g.tagBits() > 0 {
+		return 0, ErrAdaptiveUnsupported
+	}
+
+	g.ensureIdentity()
+	timestamp, counter, burst := g.nextAdaptive()
+
+	id := atomic.LoadUint64(&g.prefix) |
+		((timestamp & MaxTimestamp) << TimestampShift) |
+		counter
+	if burst {
+		id |= AdaptiveBurstFlag
+	}
+
+	g.observeGenerate()
+	return id, nil
+}
+
+// nextAdaptive returns the timestamp and counter fields for the next adaptive
+// ID, switching between normal and burst mode as the rate changes. If the
+// clock moves backward, the last timestamp is reused.
+//
+// Returns:
+//   - timestamp: The timestamp field for the next ID
+//   - counter: The counter field for the next ID, without the burst flag
+//   - burst: Whether the ID is generated in burst mode
+func (g *IDGenerator) nextAdaptive() (timestamp, counter uint64, burst bool) {
+	g.adaptMu.Lock()
+	defer g.adaptMu.Unlock()
+
+	for {
+		now := g.timestamp()
+		if now < g.adaptLast {
+			now = g.adaptLast
+		}
+
+		if g.adaptBurst {
+			if now>>AdaptiveWindowBits == g.adaptWindow {
+				if g.adaptSeq < MaxAdaptiveBurstCounter {
+					g.adaptSeq++
+					return g.burstFields()
+				}
+
+				// The burst window is exhausted; wait for the next one
+				g.observeOverflow()
+				g.waitAfter(g.adaptWindow<<AdaptiveWindowBits | adaptiveWindowMask)
+				continue
+			}
+
+			// The clock entered a new window; leave burst mode
+			g.adaptBurst = false
+		}
+
+		if now != g.adaptLast {
+			g.adaptLast = now
+			g.adaptSeq = 0
+			return now, 0, false
+		}
+
+		if g.adaptSeq < MaxAdaptiveCounter {
+			g.adaptSeq++
+			return now, g.adaptSeq, false
+		}
+
+		// The counter saturated; switch to burst mode for this window
+		g.adaptBurst = true
+		g.adaptWindow = now >> AdaptiveWindowBits
+		g.adaptSeq = 0
+		return g.burstFields()
+	}
+}
+
+// burstFields splits the burst counter between the borrowed timestamp bits
+// and the counter field. The caller must hold adaptMu.
+//
+// Returns:
+//   - timestamp: The timestamp field for the next ID
+//   - counter: The counter field for the next ID, without the burst flag
+//   - burst: Always true
+func (g *IDGenerator) burstFields() (timestamp, counter uint64, burst bool) {
+	timestamp = g.adaptWindow<<AdaptiveWindowBits | g.adaptSeq>>13
+	counter = g.adaptSeq & MaxAdaptiveCounter
+	return timestamp, counter, true
+}
+
+// IsAdaptiveBurst reports whether an adaptive ID was generated in burst mode.
+//
+// Parameters:
+//   - id: An ID created by GenerateAdaptive
+//
+// Returns: True if the ID carries AdaptiveBurstFlag
+func IsAdaptiveBurst(id uint64) bool {
+	return id&AdaptiveBurstFlag != 0
+}
+
+// DecodeAdaptive decomposes an ID created by GenerateAdaptive into an
+// IDComponents struct, assuming the timestamp is relative to the Unix epoch.
+//
+// For burst IDs, Timestamp is the start of the 8ms window and Counter is the
+// full 16-bit burst counter.
+//
+// Parameters:
+//   - id: An ID created by GenerateAdaptive
+//
+// Returns: The decoded components
+func DecodeAdaptive(id uint64) IDComponents {
+	components := DecodeUint64(id)
+	components.Counter = id & MaxAdaptiveCounter
+
+	if IsAdaptiveBurst(id) {
+		components.Counter |= (components.Timestamp & adaptiveWindowMask) << 13
+		components.Timestamp &^= adaptiveWindowMask
+	}
+	return components
+}
+
+// DecodeAdaptive decomposes an ID created by this generator's GenerateAdaptive
+// like the package function DecodeAdaptive, using the generator's epoch.
+//
+// Parameters:
+//   - id: An ID created by GenerateAdaptive
+//
+// Returns: The decoded components
+func (g *IDGenerator) DecodeAdaptive(id uint64) IDComponents {
+	components := DecodeAdaptive(id)
+	components.Epoch = time.UnixMilli(g.epoch)
+	components.Resolution = g.resolution
+	return components
+}
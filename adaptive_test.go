@@ -0,0 +1,111 @@
+package tsuniqid
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestIDGenerator_GenerateAdaptive tests that adaptive IDs stay unique under a
+// sustained burst that exceeds the normal counter capacity.
+func TestIDGenerator_GenerateAdaptive(t *testing.T) {
+	// About 9000 IDs per millisecond: enough to saturate the 13-bit counter
+	// every millisecond without exhausting a burst window
+	start := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	clock := newFakeClock(start, 110*time.Nanosecond)
+	gen := NewGenerator(WithClock(clock.Now))
+
+	const count = 200000
+	end := start.Add(count * 110 * time.Nanosecond)
+
+	seen := make(map[uint64]bool)
+	bursts := 0
+	for i := 0; i < count; i++ {
+		id, err := gen.GenerateAdaptive()
+		if err != nil {
+			t.Fatalf("GenerateAdaptive failed: %v", err)
+		}
+
+		if seen[id] {
+			t.Fatalf("Duplicate ID found: %d", id)
+		}
+		seen[id] = true
+
+		components := DecodeAdaptive(id)
+		if ts := components.Time(); ts.Before(start) || ts.After(end) {
+			t.Fatalf("Decoded timestamp %v out of range", ts)
+		}
+
+		if IsAdaptiveBurst(id) {
+			bursts++
+			if components.Timestamp&adaptiveWindowMask != 0 {
+				t.Errorf("Burst timestamp %d not aligned to a window", components.Timestamp)
+			}
+			if components.Counter > MaxAdaptiveBurstCounter {
+				t.Errorf("Burst counter %d out of range", components.Counter)
+			}
+		} else if components.Counter > MaxAdaptiveCounter {
+			t.Errorf("Normal counter %d out of range", components.Counter)
+		}
+	}
+
+	if bursts == 0 {
+		t.Errorf("Expected burst mode under sustained load")
+	}
+}
+
+// TestIDGenerator_GenerateAdaptive_Idle tests that adaptive IDs keep full
+// millisecond precision when the rate stays below the counter capacity.
+func TestIDGenerator_GenerateAdaptive_Idle(t *testing.T) {
+	start := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	clock := newFakeClock(start, time.Millisecond)
+	gen := NewGenerator(WithClock(clock.Now))
+
+	for i := 0; i < 100; i++ {
+		id, err := gen.GenerateAdaptive()
+		if err != nil {
+			t.Fatalf("GenerateAdaptive failed: %v", err)
+		}
+
+		if IsAdaptiveBurst(id) {
+			t.Fatalf("Unexpected burst ID while idle: %d", id)
+		}
+
+		want := start.Add(time.Duration(i) * time.Millisecond)
+		if ts := gen.DecodeAdaptive(id).Time(); !ts.Equal(want) {
+			t.Errorf("Timestamp mismatch: got %v, expected %v", ts, want)
+		}
+	}
+}
+
+// TestIDGenerator_GenerateAdaptive_Unsupported tests that generators whose
+// IDs do not match the adaptive bit allocation reject adaptive IDs instead of
+// composing corrupt ones.
+func TestIDGenerator_GenerateAdaptive_Unsupported(t *testing.T) {
+	layout := Layout{MachineBits: 6, InstanceBits: 6, TimestampBits: 40, CounterBits: 12}
+	custom, err := NewGeneratorWithLayout(layout)
+	if err != nil {
+		t.Fatalf("NewGeneratorWithLayout failed: %v", err)
+	}
+
+	testCases := []struct {
+		name string
+		gen  *IDGenerator
+	}{
+		{name: "Custom layout", gen: custom},
+		{name: "Parity", gen: NewGenerator(WithParity())},
+		{name: "Bit interleaving", gen: NewGenerator(WithBitInterleaving())},
+		{name: "Insert spreading", gen: NewGenerator(WithInsertSpreading(4))},
+		{name: "Microsecond resolution", gen: NewGenerator(WithTimestampResolution(time.Microsecond))},
+		{name: "Environment", gen: NewGenerator(WithEnvironment(EnvironmentStaging))},
+		{name: "Service code", gen: NewGenerator(WithServiceCode(3))},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := tc.gen.GenerateAdaptive(); !errors.Is(err, ErrAdaptiveUnsupported) {
+				t.Errorf("Expected ErrAdaptiveUnsupported, got %v", err)
+			}
+		})
+	}
+}
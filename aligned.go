@@ -0,0 +1,98 @@
+// Package tsuniqid - Second-aligned ID generation for batching systems
+package tsuniqid
+
+import (
+	"runtime"
+	"sync/atomic"
+	"time"
+)
+
+// GenerateAtNextSecond waits until the next whole second and then creates a
+// unique uint64 identifier whose timestamp is exactly that second boundary,
+// which suits systems that batch work by second. Every call waits for a
+// boundary of its own, so consecutive calls return IDs from different
+// seconds.
+//
+// The ID is unique among the generator's other IDs in every mode. With
+// WithMonotonic or WithOverflowWait it takes the next sequence number of the
+// boundary; if the generator has already moved past the boundary, or used up
+// its counter there, the call waits for the following second instead.
+//
+// The wait follows the generator's clock: with WithClock the clock is polled
+// rather than slept on, so a stepping test clock ends the wait without real
+// delay.
+//
+// Returns: A unique uint64 identifier aligned to a second boundary
+func (g *IDGenerator) GenerateAtNextSecond() uint64 {
+	boundary := g.clock().Truncate(time.Second)
+
+	for {
+		boundary = boundary.Add(time.Second)
+		g.waitUntil(boundary)
+
+		timestamp := g.timestampAt(boundary)
+		if g.monotonic || g.overflowWait {
+			if counter, ok := g.trySequenceAt(timestamp); ok {
+				return g.compose(timestamp, counter)
+			}
+			g.observeOverflow()
+			continue
+		}
+
+		// Spilling batches own their claimed timestamps outright
+		if timestamp < atomic.LoadUint64(&g.batchFloor) {
+			continue
+		}
+		counter := g.nextCounter()
+		g.trackWindow(timestamp, 1)
+		return g.compose(timestamp, counter)
+	}
+}
+
+// trySequenceAt takes the next counter of the sequenced modes for a given
+// timestamp, like trySequenceLocked does for the current one.
+//
+// Parameters:
+//   - timestamp: The timestamp for the next ID
+//
+// Returns:
+//   - counter: The counter for the next ID
+//   - ok: False if the sequence has moved past the timestamp or used up its
+//     counter there
+func (g *IDGenerator) trySequenceAt(timestamp uint64) (counter uint64, ok bool) {
+	g.seqMu.Lock()
+	defer g.seqMu.Unlock()
+
+	switch {
+	case timestamp > g.lastTimestamp:
+		g.lastTimestamp = timestamp
+		g.sequence = 0
+		return 0, true
+	case timestamp == g.lastTimestamp && g.sequence < g.maxCounter():
+		g.sequence++
+		return g.sequence, true
+	default:
+		return 0, false
+	}
+}
+
+// waitUntil blocks until the generator's clock reaches t. It sleeps on the
+// system clock, but polls a clock set by WithClock, which need not follow
+// real time.
+//
+// Parameters:
+//   - t: The time to wait for
+func (g *IDGenerator) waitUntil(t time.Time) {
+	for {
+		now := g.clock()
+		if !now.Before(t) {
+			return
+		}
+
+		if g.customClock {
+			runtime.Gosched()
+		} else {
+			time.Sleep(t.Sub(now))
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package tsuniqid
+
+import (
+	"testing"
+	"time"
+)
+
+// TestIDGenerator_GenerateAtNextSecond tests that the generated ID's
+// timestamp lies exactly on the next second boundary.
+func TestIDGenerator_GenerateAtNextSecond(t *testing.T) {
+	start := time.Date(2025, 6, 1, 12, 0, 0, 950*int(time.Millisecond), time.UTC)
+	clock := newFakeClock(start, 30*time.Millisecond)
+	gen := NewGenerator(WithClock(clock.Now))
+
+	id := gen.GenerateAtNextSecond()
+	components := DecodeUint64(id)
+
+	expected := time.Date(2025, 6, 1, 12, 0, 1, 0, time.UTC)
+	if !components.Time().Equal(expected) {
+		t.Errorf("Timestamp not aligned: got %v, expected %v", components.Time().UTC(), expected)
+	}
+	if components.Timestamp%1000 != 0 {
+		t.Errorf("Timestamp %d is not at a second boundary", components.Timestamp)
+	}
+	if clock.Now().Before(expected) {
+		t.Errorf("GenerateAtNextSecond returned before the boundary")
+	}
+}
+
+// TestIDGenerator_GenerateAtNextSecond_Clock tests that a stepping clock
+// ends the wait without sleeping for the simulated time.
+func TestIDGenerator_GenerateAtNextSecond_Clock(t *testing.T) {
+	start := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	clock := newFakeClock(start, time.Millisecond)
+	gen := NewGenerator(WithClock(clock.Now))
+
+	began := time.Now()
+	for i := 1; i <= 3; i++ {
+		id := gen.GenerateAtNextSecond()
+		expected := start.Add(time.Duration(i) * time.Second)
+		if got := DecodeUint64(id).Time(); !got.Equal(expected) {
+			t.Errorf("Call %d: got %v, expected %v", i, got.UTC(), expected)
+		}
+	}
+	if elapsed := time.Since(began); elapsed > time.Second {
+		t.Errorf("Waiting on a stepping clock took %v of real time", elapsed)
+	}
+}
+
+// TestIDGenerator_GenerateAtNextSecond_Sequenced tests that the monotonic
+// and overflow-wait modes sequence aligned IDs with their other IDs.
+func TestIDGenerator_GenerateAtNextSecond_Sequenced(t *testing.T) {
+	start := time.Date(2025, 6, 1, 12, 0, 1, 500*int(time.Millisecond), time.UTC)
+
+	// After a clock rollback the next boundary is behind the last ID, so a
+	// monotonic generator moves on to the following second
+	clock := newFakeClock(start, 0)
+	gen := NewGenerator(WithClock(clock.Now), WithMonotonic())
+	last := gen.GenerateUint64ID()
+	clock.Set(start.Add(-550*time.Millisecond), 30*time.Millisecond)
+
+	id := gen.GenerateAtNextSecond()
+	if id <= last {
+		t.Errorf("Aligned ID %d not above the last ID %d", id, last)
+	}
+	expected := time.Date(2025, 6, 1, 12, 0, 2, 0, time.UTC)
+	if got := DecodeUint64(id).Time(); !got.Equal(expected) {
+		t.Errorf("Got %v, expected %v", got.UTC(), expected)
+	}
+	if next := gen.GenerateUint64ID(); next <= id {
+		t.Errorf("Next ID %d not above the aligned ID %d", next, id)
+	}
+
+	// A boundary already in use continues its sequence, and one whose
+	// counter is used up gives way to the next second
+	clock = newFakeClock(start, time.Millisecond)
+	gen = NewGenerator(WithClock(clock.Now), WithOverflowWait())
+	boundary := gen.timestampAt(start.Truncate(time.Second).Add(time.Second))
+	gen.lastTimestamp, gen.sequence = boundary, 5
+
+	_, _, timestamp, counter := ParseUint64ID(gen.GenerateAtNextSecond())
+	if timestamp != boundary || counter != 6 {
+		t.Errorf("Expected counter 6 at %d, got %d at %d", boundary, counter, timestamp)
+	}
+
+	boundary += 1000
+	gen.lastTimestamp, gen.sequence = boundary, gen.maxCounter()
+	_, _, timestamp, counter = ParseUint64ID(gen.GenerateAtNextSecond())
+	if timestamp != boundary+1000 || counter != 0 {
+		t.Errorf("Expected counter 0 at %d, got %d at %d", boundary+1000, counter, timestamp)
+	}
+}
@@ -0,0 +1,221 @@
+// Package tsuniqid - Tracking of assigned machine/instance ID pairs
+package tsuniqid
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+// Assignment identifies a machine/instance ID pair in use by a generator.
+type Assignment struct {
+	MachineID  uint64 // machine identifier, 4 bits wide in the default layout
+	InstanceID uint64 // instance identifier, 4 bits wide in the default layout
+}
+
+// assignments records, per layout, every machine/instance pair known to be
+// taken, either by generators in this process or through ImportAssignments,
+// with the number of holders of each; a pair is free again once its count
+// drops to zero. Generators with different layouts produce different IDs
+// from the same pair, so they never take pairs from each other
+var (
+	assignmentsMu sync.Mutex
+	assignments   = make(map[Layout]map[Assignment]int)
+)
+
+// ExportAssignments returns every machine/instance ID pair known to be taken
+// by default-layout generators, including those created in this process and
+// those imported. Sharing the result with other processes lets them avoid
+// reusing the same IDs. Use Layout.ExportAssignments for generators created
+// with NewGeneratorWithLayout or NewSnowflakeGenerator.
+//
+// Returns: The taken assignments, sorted by machine ID and then instance ID
+func ExportAssignments() []Assignment {
+	return DefaultLayout.ExportAssignments()
+}
+
+// ExportAssignments is ExportAssignments for generators with this layout.
+//
+// Returns: The taken assignments, sorted by machine ID and then instance ID
+func (l Layout) ExportAssignments() []Assignment {
+	assignmentsMu.Lock()
+	taken := assignments[l]
+	result := make([]Assignment, 0, len(taken))
+	for a := range taken {
+		result = append(result, a)
+	}
+	assignmentsMu.Unlock()
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].MachineID != result[j].MachineID {
+			return result[i].MachineID < result[j].MachineID
+		}
+		return result[i].InstanceID < result[j].InstanceID
+	})
+
+	return result
+}
+
+// ImportAssignments marks machine/instance ID pairs as taken so that
+// default-layout generators created afterwards on the same machine ID skip
+// those instance IDs. Imported pairs stay taken for the life of the process.
+// IDs are masked to their 4-bit fields; use Layout.ImportAssignments for
+// generators created with NewGeneratorWithLayout or NewSnowflakeGenerator.
+//
+// Parameters:
+//   - taken: The assignments already in use elsewhere
+func ImportAssignments(taken []Assignment) {
+	DefaultLayout.ImportAssignments(taken)
+}
+
+// ImportAssignments is ImportAssignments for generators with this layout,
+// masking IDs to the layout's machine and instance fields.
+//
+// Parameters:
+//   - taken: The assignments already in use elsewhere
+func (l Layout) ImportAssignments(taken []Assignment) {
+	assignmentsMu.Lock()
+	defer assignmentsMu.Unlock()
+
+	held := l.takenLocked()
+	for _, a := range taken {
+		held[Assignment{
+			MachineID:  a.MachineID & l.maxMachineID(),
+			InstanceID: a.InstanceID & l.maxInstanceID(),
+		}]++
+	}
+}
+
+// AvailableInstanceIDs returns the instance IDs on a machine that no open
+// default-layout generator in this process holds and that were not imported
+// as taken.
+// Generators hold their instance ID until Close. An empty result means the
+// next generator created on the machine without an explicit instance ID
+// reuses one (see ErrInstanceIDExhausted). Use Layout.AvailableInstanceIDs
+// for generators created with NewGeneratorWithLayout or NewSnowflakeGenerator.
+//
+// Parameters:
+//   - machineID: The machine ID, masked to its 4-bit field
+//
+// Returns: The free instance IDs in ascending order
+func AvailableInstanceIDs(machineID uint64) []uint64 {
+	return DefaultLayout.AvailableInstanceIDs(machineID)
+}
+
+// AvailableInstanceIDs is AvailableInstanceIDs for generators with this
+// layout, covering every instance ID the layout can hold.
+//
+// Parameters:
+//   - machineID: The machine ID, masked to the layout's machine field
+//
+// Returns: The free instance IDs in ascending order
+func (l Layout) AvailableInstanceIDs(machineID uint64) []uint64 {
+	machineID &= l.maxMachineID()
+
+	assignmentsMu.Lock()
+	defer assignmentsMu.Unlock()
+
+	taken := assignments[l]
+	var result []uint64
+	for instanceID := uint64(0); instanceID <= l.maxInstanceID(); instanceID++ {
+		if _, ok := taken[Assignment{MachineID: machineID, InstanceID: instanceID}]; !ok {
+			result = append(result, instanceID)
+		}
+	}
+	return result
+}
+
+// takenLocked returns the pairs taken under this layout, creating the map on
+// first use. The caller must hold assignmentsMu.
+//
+// Returns: The layout's taken pairs with their holder counts
+func (l Layout) takenLocked() map[Assignment]int {
+	taken, ok := assignments[l]
+	if !ok {
+		taken = make(map[Assignment]int)
+		assignments[l] = taken
+	}
+	return taken
+}
+
+// assignInstanceID picks the next instance ID for a machine from the global
+// instance counter, skipping IDs already taken under the layout, and records
+// it. If every instance ID on the machine is taken, the counter's value is
+// used as-is and reported as reused.
+//
+// Parameters:
+//   - layout: The layout of the new generator
+//   - machineID: The machine ID of the new generator
+//   - nonce: The boot nonce mixed into the instance bits
+//
+// Returns:
+//   - instanceID: The assigned instance ID
+//   - reused: True if every instance ID was taken, so instanceID is shared
+func assignInstanceID(layout Layout, machineID, nonce uint64) (instanceID uint64, reused bool) {
+	assignmentsMu.Lock()
+	defer assignmentsMu.Unlock()
+
+	taken := layout.takenLocked()
+	maxInstanceID := layout.maxInstanceID()
+
+	next := atomic.AddUint64(&globalInstanceCounter, 1)
+
+	// Mix the boot nonce into the instance bits so the n-th generator on a
+	// reassigned machine ID differs from the previous node's n-th generator
+	instanceID, reused = (next^nonce)&maxInstanceID, true
+	for i := uint64(0); i <= maxInstanceID; i++ {
+		candidate := ((next + i) ^ nonce) & maxInstanceID
+		if _, ok := taken[Assignment{MachineID: machineID, InstanceID: candidate}]; !ok {
+			instanceID, reused = candidate, false
+			break
+		}
+	}
+
+	taken[Assignment{MachineID: machineID, InstanceID: instanceID}]++
+	return instanceID, reused
+}
+
+// pidNonce hashes a process ID into a nonce for assignInstanceID, so that
+// nearby process IDs differ in their low bits as much as distant ones.
+//
+// Parameters:
+//   - pid: The process ID
+//
+// Returns: The nonce derived from the process ID
+func pidNonce(pid int) uint64 {
+	return InterleaveBits(uint64(pid))
+}
+
+// claimInstanceID records an explicitly provided instance ID as taken under
+// a layout, so generators with that layout created afterwards on the same
+// machine skip it until releaseInstanceID.
+//
+// Parameters:
+//   - layout: The layout of the new generator
+//   - machineID: The machine ID of the new generator
+//   - instanceID: The instance ID provided for the new generator
+func claimInstanceID(layout Layout, machineID, instanceID uint64) {
+	assignmentsMu.Lock()
+	layout.takenLocked()[Assignment{MachineID: machineID, InstanceID: instanceID}]++
+	assignmentsMu.Unlock()
+}
+
+// releaseInstanceID drops one holder of a machine/instance pair recorded by
+// assignInstanceID or claimInstanceID, freeing the pair once no generator
+// holds it.
+//
+// Parameters:
+//   - layout: The layout of the closed generator
+//   - machineID: The machine ID of the closed generator
+//   - instanceID: The instance ID of the closed generator
+func releaseInstanceID(layout Layout, machineID, instanceID uint64) {
+	a := Assignment{MachineID: machineID, InstanceID: instanceID}
+
+	assignmentsMu.Lock()
+	if taken := assignments[layout]; taken != nil {
+		if taken[a]--; taken[a] <= 0 {
+			delete(taken, a)
+		}
+	}
+	assignmentsMu.Unlock()
+}
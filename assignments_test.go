@@ -0,0 +1,271 @@
+package tsuniqid
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestImportAssignments tests that new generators skip instance IDs that
+// were imported as taken and that new assignments are exported.
+func TestImportAssignments(t *testing.T) {
+	const machineID = 0x5
+
+	// Start from an empty registry so earlier tests cannot exhaust the machine
+	assignmentsMu.Lock()
+	assignments = make(map[Layout]map[Assignment]int)
+	assignmentsMu.Unlock()
+
+	// Take the next three instance IDs the global counter would hand out
+	next := atomic.LoadUint64(&globalInstanceCounter)
+	taken := []Assignment{
+		{MachineID: machineID, InstanceID: (next + 1) & MaxInstanceID},
+		{MachineID: machineID, InstanceID: (next + 2) & MaxInstanceID},
+		{MachineID: machineID, InstanceID: (next + 3) & MaxInstanceID},
+	}
+	ImportAssignments(taken)
+
+	gen := NewGenerator(WithMachineID(machineID))
+	for _, a := range taken {
+		if gen.instanceID == a.InstanceID {
+			t.Errorf("Generator reused taken instance ID %d", a.InstanceID)
+		}
+	}
+
+	// Both the imported and the new assignment must be exported
+	exported := make(map[Assignment]bool)
+	for _, a := range ExportAssignments() {
+		exported[a] = true
+	}
+	for _, a := range append(taken, Assignment{MachineID: machineID, InstanceID: gen.instanceID}) {
+		if !exported[a] {
+			t.Errorf("Assignment %+v missing from export", a)
+		}
+	}
+}
+
+// TestExportAssignments_Sorted tests that exported assignments are ordered.
+func TestExportAssignments_Sorted(t *testing.T) {
+	ImportAssignments([]Assignment{{MachineID: 0x2, InstanceID: 0x9}, {MachineID: 0x1, InstanceID: 0xc}})
+
+	exported := ExportAssignments()
+	for i := 1; i < len(exported); i++ {
+		prev, cur := exported[i-1], exported[i]
+		if prev.MachineID > cur.MachineID || (prev.MachineID == cur.MachineID && prev.InstanceID >= cur.InstanceID) {
+			t.Errorf("Assignments not sorted at %d: %+v before %+v", i, prev, cur)
+		}
+	}
+}
+
+// TestWithPIDInstanceID tests that processes starting from the same instance
+// counter value get different instance IDs when their process IDs hash
+// differently, and that process IDs spread over the instance ID space.
+func TestWithPIDInstanceID(t *testing.T) {
+	defer func(old func() int) { osGetpid = old }(osGetpid)
+
+	// Find two neighbouring process IDs whose hashes differ in the instance bits
+	pidA := 1000
+	for pidNonce(pidA)&MaxInstanceID == pidNonce(pidA+1)&MaxInstanceID {
+		pidA++
+	}
+
+	// Simulate each process with a fresh registry and the same counter value
+	const machineID = 0x9
+	start := atomic.LoadUint64(&globalInstanceCounter)
+	instances := make([]uint64, 2)
+	for i, pid := range []int{pidA, pidA + 1} {
+		assignmentsMu.Lock()
+		assignments = make(map[Layout]map[Assignment]int)
+		assignmentsMu.Unlock()
+		atomic.StoreUint64(&globalInstanceCounter, start)
+
+		osGetpid = func() int { return pid }
+		instances[i] = NewGenerator(WithMachineID(machineID), WithPIDInstanceID()).InstanceID()
+
+		if expected := ((start + 1) ^ pidNonce(pid)) & MaxInstanceID; instances[i] != expected {
+			t.Errorf("Instance ID for PID %d: got %d, expected %d", pid, instances[i], expected)
+		}
+	}
+	if instances[0] == instances[1] {
+		t.Errorf("Processes %d and %d share instance ID %d", pidA, pidA+1, instances[0])
+	}
+
+	// Consecutive process IDs cover every instance ID
+	seen := make(map[uint64]int)
+	for pid := 1; pid <= 1600; pid++ {
+		seen[pidNonce(pid)&MaxInstanceID]++
+	}
+	for instanceID := uint64(0); instanceID <= MaxInstanceID; instanceID++ {
+		if seen[instanceID] < 50 {
+			t.Errorf("Instance ID %d reached by only %d of 1600 PIDs", instanceID, seen[instanceID])
+		}
+	}
+}
+
+// TestInstanceIDExhaustion tests that the free instance IDs are enumerated
+// and that reusing one is reported once all are taken.
+func TestInstanceIDExhaustion(t *testing.T) {
+	const machineID = 0xb
+
+	assignmentsMu.Lock()
+	assignments = make(map[Layout]map[Assignment]int)
+	assignmentsMu.Unlock()
+
+	for i := 0; i <= MaxInstanceID; i++ {
+		if available := len(AvailableInstanceIDs(machineID)); available != MaxInstanceID+1-i {
+			t.Fatalf("After %d generators: %d instance IDs available, expected %d", i, available, MaxInstanceID+1-i)
+		}
+		if _, err := NewGeneratorE(WithMachineID(machineID)); err != nil {
+			t.Fatalf("Generator %d: unexpected error %v", i, err)
+		}
+	}
+	if available := AvailableInstanceIDs(machineID); len(available) != 0 {
+		t.Errorf("Expected no free instance IDs, got %v", available)
+	}
+
+	gen, err := NewGeneratorE(WithMachineID(machineID))
+	if !errors.Is(err, ErrInstanceIDExhausted) || gen == nil {
+		t.Errorf("Expected ErrInstanceIDExhausted with a usable generator, got %v", err)
+	}
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, nil))
+	NewGenerator(WithMachineID(machineID), WithLogger(logger))
+	if !strings.Contains(buf.String(), "level=WARN") || !strings.Contains(buf.String(), "instance IDs exhausted") {
+		t.Errorf("Expected an exhaustion warning, got %q", buf.String())
+	}
+
+	// An explicit instance ID is never reported
+	if _, err := NewGeneratorE(WithMachineID(machineID), WithInstanceID(1)); err != nil {
+		t.Errorf("Unexpected error with an explicit instance ID: %v", err)
+	}
+}
+
+// TestIDGenerator_Close_ReleasesInstanceID tests that Close frees the
+// generator's instance ID for later generators on the same machine.
+func TestIDGenerator_Close_ReleasesInstanceID(t *testing.T) {
+	const machineID = 0x3
+
+	assignmentsMu.Lock()
+	assignments = make(map[Layout]map[Assignment]int)
+	assignmentsMu.Unlock()
+
+	// Take every instance ID on the machine, then close the generators
+	gens := make([]*IDGenerator, MaxInstanceID+1)
+	for i := range gens {
+		gens[i] = NewGenerator(WithMachineID(machineID))
+	}
+	if free := AvailableInstanceIDs(machineID); len(free) != 0 {
+		t.Fatalf("AvailableInstanceIDs() = %v, want none while generators are open", free)
+	}
+	for _, gen := range gens {
+		gen.Close()
+	}
+	gens[0].Close() // a second Close must not release another holder's claim
+
+	if free := AvailableInstanceIDs(machineID); len(free) != MaxInstanceID+1 {
+		t.Errorf("AvailableInstanceIDs() = %v, want all %d after Close", free, MaxInstanceID+1)
+	}
+	gen, err := NewGeneratorE(WithMachineID(machineID))
+	if errors.Is(err, ErrInstanceIDExhausted) {
+		t.Errorf("NewGeneratorE() error = %v after Close, want instance IDs free", err)
+	}
+	gen.Close()
+}
+
+// TestIDGenerator_Close_SharedInstanceID tests that closing one of two
+// generators with the same explicit instance ID keeps the pair taken.
+func TestIDGenerator_Close_SharedInstanceID(t *testing.T) {
+	const machineID, instanceID = 0x4, 0x7
+
+	first := NewGenerator(WithMachineID(machineID), WithInstanceID(instanceID))
+	second := NewGenerator(WithMachineID(machineID), WithInstanceID(instanceID))
+	first.Close()
+
+	for _, id := range AvailableInstanceIDs(machineID) {
+		if id == instanceID {
+			t.Errorf("Instance ID %d freed while another generator holds it", instanceID)
+		}
+	}
+	second.Close()
+}
+
+// TestLayout_Assignments tests that importing and listing assignments for a
+// custom layout covers its full machine and instance fields.
+func TestLayout_Assignments(t *testing.T) {
+	layout := Layout{MachineBits: 6, InstanceBits: 6, TimestampBits: 40, CounterBits: 12}
+	const machineID = 0x2a // beyond the default 4-bit machine field
+
+	assignmentsMu.Lock()
+	assignments = make(map[Layout]map[Assignment]int)
+	assignmentsMu.Unlock()
+
+	layout.ImportAssignments([]Assignment{{MachineID: machineID, InstanceID: 0x30}})
+
+	free := layout.AvailableInstanceIDs(machineID)
+	if want := int(layout.maxInstanceID()); len(free) != want {
+		t.Fatalf("AvailableInstanceIDs() returned %d IDs, want %d", len(free), want)
+	}
+	for _, id := range free {
+		if id == 0x30 {
+			t.Errorf("Imported instance ID 0x30 listed as free")
+		}
+	}
+
+	// A generator with the layout must skip the imported pair
+	gen, err := NewGeneratorWithLayout(layout, WithMachineID(machineID))
+	if err != nil {
+		t.Fatalf("NewGeneratorWithLayout() error = %v", err)
+	}
+	defer gen.Close()
+	if gen.MachineID() != machineID {
+		t.Errorf("MachineID() = %#x, want %#x", gen.MachineID(), machineID)
+	}
+	if gen.InstanceID() == 0x30 {
+		t.Errorf("Generator took imported instance ID 0x30")
+	}
+	if n := len(layout.AvailableInstanceIDs(machineID)); n != len(free)-1 {
+		t.Errorf("AvailableInstanceIDs() returned %d IDs after NewGeneratorWithLayout, want %d", n, len(free)-1)
+	}
+}
+
+// TestAssignments_PerLayout tests that generators with other layouts, such as
+// Snowflake generators, do not take instance IDs from default-layout
+// generators with the same machine and instance IDs.
+func TestAssignments_PerLayout(t *testing.T) {
+	assignmentsMu.Lock()
+	assignments = make(map[Layout]map[Assignment]int)
+	assignmentsMu.Unlock()
+
+	// Worker ID 0x25 is machine ID 1, instance ID 5 in the Snowflake layout
+	snowflake, err := NewSnowflakeGenerator(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), 0x25)
+	if err != nil {
+		t.Fatalf("NewSnowflakeGenerator() error = %v", err)
+	}
+	defer snowflake.Close()
+	if snowflake.MachineID() != 1 || snowflake.InstanceID() != 5 {
+		t.Fatalf("Snowflake generator has machine %d, instance %d, want 1, 5", snowflake.MachineID(), snowflake.InstanceID())
+	}
+
+	if n := len(AvailableInstanceIDs(1)); n != MaxInstanceID+1 {
+		t.Errorf("AvailableInstanceIDs(1) returned %d IDs after a Snowflake claim, want %d", n, MaxInstanceID+1)
+	}
+	if taken := ExportAssignments(); len(taken) != 0 {
+		t.Errorf("ExportAssignments() = %v after a Snowflake claim, want none", taken)
+	}
+	if taken := snowflakeLayout.ExportAssignments(); len(taken) != 1 || taken[0] != (Assignment{MachineID: 1, InstanceID: 5}) {
+		t.Errorf("snowflakeLayout.ExportAssignments() = %v, want the Snowflake claim", taken)
+	}
+
+	gen := NewGenerator(WithMachineID(1), WithInstanceID(5))
+	defer gen.Close()
+	for _, id := range AvailableInstanceIDs(1) {
+		if id == 5 {
+			t.Errorf("Default-layout instance ID 5 listed as free while a default-layout generator holds it")
+		}
+	}
+}
@@ -0,0 +1,195 @@
+// Package tsuniqid - Batch generation of uint64 IDs
+package tsuniqid
+
+import (
+	"strconv"
+	"sync/atomic"
+)
+
+// GenerateUint64IDs creates n unique uint64 identifiers at once, for bulk
+// inserts. It reads the clock once and reserves all counter values with a
+// single atomic operation, which is much cheaper than n calls to
+// GenerateUint64ID.
+//
+// When the batch needs more counter values than are left in the current
+// millisecond, or than one shard holds with WithShardedCounter, it claims the
+// following milliseconds for itself: their IDs all come from the batch, and
+// other callers skip past them, waiting for the clock if necessary. The call
+// returns once the clock reaches the last claimed millisecond. A clock set by
+// WithClock need not advance, so with one neither waits; later IDs take the
+// first timestamp after the claim instead. In the sequenced modes enabled by
+// WithMonotonic and WithOverflowWait, the generator instead waits for the
+// clock to advance, exactly as GenerateUint64ID would.
+//
+// Parameters:
+//   - n: The number of IDs to generate
+//
+// Returns: A slice of n unique uint64 identifiers, or nil if n <= 0
+func (g *IDGenerator) GenerateUint64IDs(n int) []uint64 {
+	if n <= 0 {
+		return nil
+	}
+
+	ids := make([]uint64, n)
+	if g.monotonic || g.overflowWait {
+		g.fillSequenced(ids)
+		return ids
+	}
+
+	// A batch that fits behaves exactly like n calls to GenerateUint64ID. A
+	// sharded batch draws from a single shard, so it must also fit the
+	// shard's share of the counter
+	if uint64(n) <= g.remainingCapacity() && uint64(n) <= g.shardCapacity() {
+		last, base, capacity := g.reserveCounters(uint64(n))
+		first := last - uint64(n) + 1
+		timestamp := g.freeRunningTimestamp()
+
+		for i := range ids {
+			ids[i] = g.compose(timestamp, base|(first+uint64(i))%capacity)
+		}
+		g.trackWindow(timestamp, uint64(n))
+		return ids
+	}
+
+	last, base, capacity := g.reserveCounters(uint64(n))
+	first := last - uint64(n) + 1
+	g.observeOverflow()
+
+	// Fill each claimed timestamp with capacity IDs; no other caller uses
+	// them, so every counter value is free
+	timestamps := (uint64(n) + capacity - 1) / capacity
+	start := g.claimTimestamps(timestamps)
+	for i := range ids {
+		ids[i] = g.compose(start+uint64(i)/capacity, base|(first+uint64(i))%capacity)
+	}
+
+	end := start + timestamps - 1
+	g.trackWindow(end, uint64(n)-(timestamps-1)*capacity)
+	if !g.customClock {
+		g.waitAfter(end - 1)
+	}
+
+	return ids
+}
+
+// shardCapacity returns the number of counter values a single reservation
+// can take before its counter wraps: the whole counter, or one shard's share
+// of it with WithShardedCounter.
+//
+// Returns: The per-shard capacity
+func (g *IDGenerator) shardCapacity() uint64 {
+	if g.shards == nil {
+		return g.maxCounter() + 1
+	}
+	return 1 << g.shardedCounterBits()
+}
+
+// claimTimestamps reserves count consecutive future timestamps for a
+// spilling batch. Callers of freeRunningTimestamp skip past them.
+//
+// Parameters:
+//   - count: The number of timestamps to claim
+//
+// Returns: The first claimed timestamp
+func (g *IDGenerator) claimTimestamps(count uint64) uint64 {
+	for {
+		// Leave one timestamp for the clock to advance before the claim is
+		// published, so the check below rarely fails. The floor itself may
+		// already be in use by callers of freeRunningTimestamp, so a claim
+		// that follows an earlier one starts after it
+		floor := atomic.LoadUint64(&g.batchFloor)
+		start := g.timestamp() + 2
+		if start <= floor {
+			start = floor + 1
+		}
+		if !atomic.CompareAndSwapUint64(&g.batchFloor, floor, start+count) {
+			continue
+		}
+
+		// A caller that read the clock before the claim was published saw
+		// an earlier time than this, so if the clock is still before start,
+		// no caller can have used the claimed timestamps
+		if g.timestamp() < start {
+			return start
+		}
+	}
+}
+
+// freeRunningTimestamp returns the timestamp for an ID from the free-running
+// counter, waiting past any timestamps claimed by spilling batches. A clock
+// set by WithClock need not advance, so with one it returns the first
+// timestamp after the claims instead of waiting.
+//
+// Returns: The current timestamp, at or after the end of every batch claim
+func (g *IDGenerator) freeRunningTimestamp() uint64 {
+	for {
+		timestamp := g.timestamp()
+		floor := atomic.LoadUint64(&g.batchFloor)
+		if timestamp >= floor {
+			return timestamp
+		}
+		if g.customClock {
+			return floor
+		}
+		g.waitAfter(floor - 1)
+	}
+}
+
+// GenerateStringIDs creates n unique string identifiers at once, in the same
+// format as GenerateStringID. The uint64 parts come from GenerateUint64IDs,
+// and all random suffixes are drawn under a single acquisition of the random
+// number generator's mutex, reducing lock contention with other goroutines.
+//
+// Parameters:
+//   - n: The number of IDs to generate
+//
+// Returns: A slice of n unique string identifiers, or nil if n <= 0
+func (g *IDGenerator) GenerateStringIDs(n int) []string {
+	ids := g.GenerateUint64IDs(n)
+	if ids == nil {
+		return nil
+	}
+
+	length := g.suffixLength
+	suffixes := make([]byte, n*length)
+	charSetLen := len(g.charSet)
+
+	if !g.singleThreaded {
+		g.mu.Lock()
+	}
+	for i := range suffixes {
+		suffixes[i] = g.charSet[g.randIntn(charSetLen)]
+	}
+	if !g.singleThreaded {
+		g.mu.Unlock()
+	}
+
+	result := make([]string, n)
+	for i, id := range ids {
+		result[i] = g.withCheckChar(strconv.FormatUint(id, 16) + string(suffixes[i*length:(i+1)*length]))
+	}
+
+	return result
+}
+
+// fillSequenced fills ids in the sequenced modes, holding seqMu for the whole
+// batch and only reading the clock when the counter is exhausted.
+//
+// Parameters:
+//   - ids: The slice to fill
+func (g *IDGenerator) fillSequenced(ids []uint64) {
+	g.seqMu.Lock()
+	defer g.seqMu.Unlock()
+
+	maxCounter := g.maxCounter()
+	for i := range ids {
+		if i > 0 && g.sequence < maxCounter {
+			g.sequence++
+			ids[i] = g.compose(g.lastTimestamp, g.sequence)
+			continue
+		}
+
+		timestamp, counter := g.nextSequenceLocked()
+		ids[i] = g.compose(timestamp, counter)
+	}
+}
@@ -0,0 +1,252 @@
+package tsuniqid
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestIDGenerator_GenerateUint64IDs tests that batches are unique, including
+// batches that spill over into the next millisecond.
+func TestIDGenerator_GenerateUint64IDs(t *testing.T) {
+	testCases := []struct {
+		name string
+		opts []Option
+	}{
+		{name: "Default", opts: nil},
+		{name: "Environment", opts: []Option{WithEnvironment(EnvironmentStaging)}},
+		{name: "Monotonic", opts: []Option{WithMonotonic()}},
+		{name: "OverflowWait", opts: []Option{WithOverflowWait(), WithParity()}},
+		{name: "Sharded", opts: []Option{WithShardedCounter(4)}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			gen := NewGenerator(tc.opts...)
+			seen := make(map[uint64]bool)
+
+			// 5000 fits the whole counter but not one of four shards
+			for _, n := range []int{1, 100, 5000, 3 * (MaxCounter + 1)} {
+				ids := gen.GenerateUint64IDs(n)
+				if len(ids) != n {
+					t.Fatalf("Expected %d IDs, got %d", n, len(ids))
+				}
+
+				for _, id := range ids {
+					if seen[id] {
+						t.Fatalf("Duplicate ID found: %d", id)
+					}
+					seen[id] = true
+				}
+			}
+
+			// Single IDs must not collide with batched ones
+			for i := 0; i < 1000; i++ {
+				if id := gen.GenerateUint64ID(); seen[id] {
+					t.Fatalf("Single ID collides with batch: %d", id)
+				}
+			}
+		})
+	}
+
+	if ids := NewGenerator().GenerateUint64IDs(0); ids != nil {
+		t.Errorf("Expected nil for empty batch, got %v", ids)
+	}
+}
+
+// TestIDGenerator_GenerateUint64IDs_Spill tests that a batch that fits shares
+// one timestamp like single IDs, and that a larger batch fills whole
+// following milliseconds that later IDs skip past.
+func TestIDGenerator_GenerateUint64IDs_Spill(t *testing.T) {
+	pinned := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	clock := newFakeClock(pinned, 0)
+	gen := NewGenerator(WithClock(clock.Now))
+	gen.counter = MaxCounter - 1
+
+	ids := gen.GenerateUint64IDs(3)
+	for i, want := range []uint64{MaxCounter, 0, 1} {
+		_, _, timestamp, counter := ParseUint64ID(ids[i])
+		if timestamp != uint64(pinned.UnixMilli()) || counter != want {
+			t.Errorf("ID %d: got timestamp %d counter %d, expected %d and %d",
+				i, timestamp, counter, pinned.UnixMilli(), want)
+		}
+	}
+
+	clock.Set(pinned, time.Millisecond)
+	_, _, before, _ := ParseUint64ID(gen.GenerateUint64ID())
+
+	capacity := MaxCounter + 1
+	perTimestamp := make(map[uint64]int)
+	var first, last uint64
+	for i, id := range gen.GenerateUint64IDs(2*capacity + 5) {
+		_, _, timestamp, _ := ParseUint64ID(id)
+		perTimestamp[timestamp]++
+		if i == 0 {
+			first = timestamp
+		}
+		last = timestamp
+	}
+
+	if first <= before {
+		t.Errorf("Batch starts at timestamp %d, not after the earlier ID at %d", first, before)
+	}
+	if last != first+2 || perTimestamp[first] != capacity || perTimestamp[first+1] != capacity || perTimestamp[last] != 5 {
+		t.Errorf("Unexpected batch spread from %d to %d: %v", first, last, perTimestamp)
+	}
+	if _, _, after, _ := ParseUint64ID(gen.GenerateUint64ID()); after <= last {
+		t.Errorf("Later ID at timestamp %d reuses the batch's timestamps up to %d", after, last)
+	}
+}
+
+// TestIDGenerator_GenerateUint64IDs_FrozenClock tests that a spilling batch
+// and the IDs after it do not wait for a clock that never advances.
+func TestIDGenerator_GenerateUint64IDs_FrozenClock(t *testing.T) {
+	pinned := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	gen := NewGenerator(WithClock(newFakeClock(pinned, 0).Now))
+
+	done := make(chan []uint64)
+	go func() {
+		ids := append(gen.GenerateUint64IDs(20000), gen.GenerateUint64ID())
+		done <- append(ids, gen.GenerateUint64IDs(10)...)
+	}()
+
+	select {
+	case ids := <-done:
+		seen := make(map[uint64]bool)
+		for _, id := range ids {
+			if seen[id] {
+				t.Fatalf("Duplicate ID found: %d", id)
+			}
+			seen[id] = true
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Batch generation blocked on a frozen clock")
+	}
+}
+
+// TestIDGenerator_GenerateUint64IDs_Concurrent tests that spilling batches
+// never share IDs with single IDs generated concurrently.
+func TestIDGenerator_GenerateUint64IDs_Concurrent(t *testing.T) {
+	gen := NewGenerator()
+
+	var (
+		mu   sync.Mutex
+		seen = make(map[uint64]bool)
+		wg   sync.WaitGroup
+	)
+	record := func(ids ...uint64) {
+		mu.Lock()
+		defer mu.Unlock()
+		for _, id := range ids {
+			if seen[id] {
+				t.Errorf("Duplicate ID %x", id)
+			}
+			seen[id] = true
+		}
+	}
+
+	stop := make(chan struct{})
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+					record(gen.GenerateUint64ID())
+				}
+			}
+		}()
+	}
+
+	// A whole number of counter cycles brings the counter back to the batch's
+	// first value, so a single ID in the same millisecond would repeat it
+	for i := 0; i < 5; i++ {
+		record(gen.GenerateUint64IDs(2 * (MaxCounter + 1))...)
+	}
+	close(stop)
+	wg.Wait()
+}
+
+// TestIDGenerator_GenerateStringIDs tests that batched string IDs are unique
+// and have the same format as GenerateStringID.
+func TestIDGenerator_GenerateStringIDs(t *testing.T) {
+	for _, length := range []int{0, RandomSuffixLength, 12} {
+		gen := NewGenerator(WithSuffixLength(length))
+		seen := make(map[string]bool)
+
+		for _, id := range gen.GenerateStringIDs(1000) {
+			if seen[id] {
+				t.Fatalf("Duplicate ID found: %s", id)
+			}
+			seen[id] = true
+
+			hexPart := id[:len(id)-length]
+			if _, err := strconv.ParseUint(hexPart, 16, 64); err != nil {
+				t.Errorf("Invalid hex part in ID %s: %v", id, err)
+			}
+			for _, c := range id[len(hexPart):] {
+				if !strings.ContainsRune(CharSet, c) {
+					t.Errorf("Invalid suffix character %q in ID %s", c, id)
+				}
+			}
+		}
+	}
+
+	if ids := NewGenerator().GenerateStringIDs(0); ids != nil {
+		t.Errorf("Expected nil for empty batch, got %v", ids)
+	}
+}
+
+// BenchmarkIDGenerator_GenerateStringIDs benchmarks batch string generation
+// from several goroutines sharing a generator.
+func BenchmarkIDGenerator_GenerateStringIDs(b *testing.B) {
+	gen := NewGenerator()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			_ = gen.GenerateStringIDs(100)
+		}
+	})
+}
+
+// BenchmarkIDGenerator_GenerateStringID_Loop benchmarks generating the same
+// number of string IDs as BenchmarkIDGenerator_GenerateStringIDs one at a time.
+func BenchmarkIDGenerator_GenerateStringID_Loop(b *testing.B) {
+	gen := NewGenerator()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			ids := make([]string, 100)
+			for j := range ids {
+				ids[j] = gen.GenerateStringID()
+			}
+		}
+	})
+}
+
+// BenchmarkIDGenerator_GenerateUint64IDs benchmarks batch generation.
+func BenchmarkIDGenerator_GenerateUint64IDs(b *testing.B) {
+	gen := NewGenerator()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = gen.GenerateUint64IDs(1000)
+	}
+}
+
+// BenchmarkIDGenerator_GenerateUint64ID_Loop benchmarks generating the same
+// number of IDs as BenchmarkIDGenerator_GenerateUint64IDs one at a time.
+func BenchmarkIDGenerator_GenerateUint64ID_Loop(b *testing.B) {
+	gen := NewGenerator()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ids := make([]uint64, 1000)
+		for j := range ids {
+			ids[j] = gen.GenerateUint64ID()
+		}
+	}
+}
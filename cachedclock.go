@@ -0,0 +1,60 @@
+// Package tsuniqid - Cached clock for generation without a time.Now per ID
+package tsuniqid
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// startCachedClock stores the current time and starts the goroutine that
+// refreshes it every cachedClockInterval, then routes the generator's clock
+// through the stored time.
+func (g *IDGenerator) startCachedClock() {
+	g.clockSource = g.clock
+	g.clockStop = make(chan struct{})
+	g.clockDone = make(chan struct{})
+	atomic.StoreInt64(&g.cachedNow, g.clockSource().UnixNano())
+	g.clock = g.cachedClock
+
+	go func() {
+		defer close(g.clockDone)
+
+		ticker := time.NewTicker(g.cachedClockInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				atomic.StoreInt64(&g.cachedNow, g.clockSource().UnixNano())
+			case <-g.clockStop:
+				return
+			}
+		}
+	}()
+}
+
+// cachedClock returns the time last stored by the cached clock goroutine, or
+// reads the underlying clock once the goroutine has stopped.
+//
+// Returns: The current time, at most one refresh interval old
+func (g *IDGenerator) cachedClock() time.Time {
+	if now := atomic.LoadInt64(&g.cachedNow); now != 0 {
+		return time.Unix(0, now)
+	}
+	return g.clockSource()
+}
+
+// stopCachedClock stops the goroutine started by startCachedClock and waits
+// for it to exit, then routes cachedClock back to the underlying clock. It
+// does nothing for generators without a cached clock or after the first call.
+func (g *IDGenerator) stopCachedClock() {
+	if g.clockStop == nil {
+		return
+	}
+
+	g.closeOnce.Do(func() {
+		close(g.clockStop)
+		<-g.clockDone
+		atomic.StoreInt64(&g.cachedNow, 0)
+	})
+}
@@ -0,0 +1,82 @@
+package tsuniqid
+
+import (
+	"testing"
+	"time"
+)
+
+// TestWithCachedClock tests that IDs carry the cached time and that the
+// cache follows the clock.
+func TestWithCachedClock(t *testing.T) {
+	start := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	clock := newFakeClock(start, 0)
+
+	gen, err := NewGeneratorWithError(WithClock(clock.Now), WithCachedClock(time.Millisecond))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer gen.Close()
+
+	if ts := gen.TimeOf(gen.GenerateUint64ID()); !ts.Equal(start) {
+		t.Errorf("Timestamp mismatch: got %v, expected %v", ts, start)
+	}
+
+	// The background goroutine picks up the new time within a few intervals
+	later := start.Add(time.Hour)
+	clock.Set(later, 0)
+	deadline := time.Now().Add(time.Second)
+	for !gen.TimeOf(gen.GenerateUint64ID()).Equal(later) {
+		if time.Now().After(deadline) {
+			t.Fatalf("Cached clock never reached %v", later)
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	if _, err := NewGeneratorWithError(WithCachedClock(0)); err == nil {
+		t.Errorf("Expected error for a zero interval")
+	}
+}
+
+// TestWithCachedClock_InvalidOption tests that NewGenerator, which keeps a
+// generator despite invalid options, still starts its cached clock.
+func TestWithCachedClock_InvalidOption(t *testing.T) {
+	start := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	clock := newFakeClock(start, 0)
+
+	gen := NewGenerator(WithClock(clock.Now), WithCachedClock(time.Millisecond), WithSuffixLength(-1))
+	defer gen.Close()
+
+	if gen.clockDone == nil {
+		t.Fatalf("Cached clock goroutine not started")
+	}
+	later := start.Add(time.Hour)
+	clock.Set(later, 0)
+	deadline := time.Now().Add(time.Second)
+	for !gen.TimeOf(gen.GenerateUint64ID()).Equal(later) {
+		if time.Now().After(deadline) {
+			t.Fatalf("Cached clock never reached %v", later)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// BenchmarkGenerateUint64ID_SystemClock benchmarks generation reading
+// time.Now for every ID, as a baseline for the cached clock.
+func BenchmarkGenerateUint64ID_SystemClock(b *testing.B) {
+	gen := NewGenerator()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = gen.GenerateUint64ID()
+	}
+}
+
+// BenchmarkGenerateUint64ID_CachedClock benchmarks generation reading the
+// time cached by WithCachedClock.
+func BenchmarkGenerateUint64ID_CachedClock(b *testing.B) {
+	gen := NewGenerator(WithCachedClock(100 * time.Microsecond))
+	defer gen.Close()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = gen.GenerateUint64ID()
+	}
+}
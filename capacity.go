@@ -0,0 +1,297 @@
+// Package tsuniqid - Capacity planning and collision analysis helpers
+package tsuniqid
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync/atomic"
+	"time"
+)
+
+// SimulateFleet deterministically models ID generation across a planned
+// deployment of generators with the default layout and reports how many
+// collisions would occur. See Layout.SimulateFleet for fleets using
+// NewGeneratorWithLayout.
+//
+// Parameters:
+//   - machines: The number of machines in the fleet
+//   - instancesPerMachine: The number of generators per machine
+//   - idsPerMs: The number of IDs each generator produces per millisecond
+//   - durationMs: The number of milliseconds to simulate
+//
+// Returns: The number of generated IDs that duplicate an earlier ID
+func SimulateFleet(machines, instancesPerMachine, idsPerMs, durationMs int) (collisions int) {
+	return DefaultLayout.SimulateFleet(machines, instancesPerMachine, idsPerMs, durationMs)
+}
+
+// SimulateFleet deterministically models ID generation across a planned
+// deployment of generators with this layout and reports how many collisions
+// would occur.
+//
+// Machines and instances are numbered sequentially and masked to the
+// layout's machine and instance fields, so fleets larger than the ID space
+// wrap onto each other exactly as a real deployment with sequentially
+// assigned IDs would. Each instance generates idsPerMs IDs in each of
+// durationMs consecutive milliseconds using the same counter and watermark
+// behavior as IDGenerator, so collisions start once idsPerMs exceeds
+// 2^CounterBits.
+//
+// The simulation keeps every generated ID in memory, so the product of all
+// arguments should stay within a few million.
+//
+// Parameters:
+//   - machines: The number of machines in the fleet
+//   - instancesPerMachine: The number of generators per machine
+//   - idsPerMs: The number of IDs each generator produces per millisecond
+//   - durationMs: The number of milliseconds to simulate
+//
+// Returns: The number of generated IDs that duplicate an earlier ID, or 0
+// if the layout is invalid
+func (l Layout) SimulateFleet(machines, instancesPerMachine, idsPerMs, durationMs int) (collisions int) {
+	if machines <= 0 || instancesPerMachine <= 0 || idsPerMs <= 0 || durationMs <= 0 || l.Validate() != nil {
+		return 0
+	}
+
+	seen := make(map[uint64]struct{}, machines*instancesPerMachine*idsPerMs*durationMs)
+
+	for m := 0; m < machines; m++ {
+		machineID := uint64(m) & l.maxMachineID()
+
+		for i := 0; i < instancesPerMachine; i++ {
+			prefix := l.identityPrefix(machineID, uint64(i)&l.maxInstanceID())
+
+			// Each generator's counter and watermark keep running across
+			// milliseconds
+			var counter, watermark uint64
+			for ms := 0; ms < durationMs; ms++ {
+				timestamp := uint64(ms)
+
+				for n := 0; n < idsPerMs; n++ {
+					counter++
+
+					id := prefix |
+						((timestamp & l.maxTimestamp()) << l.timestampShift()) |
+						((counter & l.maxCounter()) << l.counterShift()) |
+						(watermark & l.maxWatermark())
+					watermark++
+
+					if _, ok := seen[id]; ok {
+						collisions++
+						continue
+					}
+					seen[id] = struct{}{}
+				}
+			}
+		}
+	}
+
+	return collisions
+}
+
+// MachineIDCollisionProbability returns the probability that at least two of
+// nodeCount nodes derive the same machine ID, assuming the hashed machine IDs
+// are spread uniformly over the MaxMachineID+1 possible values.
+//
+// This is the birthday bound over 16 slots: the risk passes 50% at 5 nodes
+// and 95% at 10, so fleets of more than a few nodes should assign machine IDs
+// explicitly with WithMachineID.
+//
+// Parameters:
+//   - nodeCount: The number of nodes in the fleet
+//
+// Returns: The collision probability between 0 and 1
+func MachineIDCollisionProbability(nodeCount int) float64 {
+	const slots = MaxMachineID + 1
+	if nodeCount <= 1 {
+		return 0
+	}
+	if nodeCount > slots {
+		return 1
+	}
+
+	distinct := 1.0
+	for k := 0; k < nodeCount; k++ {
+		distinct *= float64(slots-k) / slots
+	}
+	return 1 - distinct
+}
+
+// MaxIDsPerMillisecond returns the number of IDs a generator with the default
+// layout can produce in one millisecond before its counter is exhausted,
+// which is MaxCounter+1.
+//
+// Returns: The per-generator throughput ceiling per millisecond
+func MaxIDsPerMillisecond() int {
+	return MaxCounter + 1
+}
+
+// MaxIDsPerMillisecond returns the number of IDs this generator can produce
+// in one millisecond before its counter is exhausted. It accounts for the
+// generator's layout, for the counter bits given up to WithServiceCode,
+// WithEnvironment and WithParity, and for the finer timestamps of
+// WithTimestampResolution. Beyond this rate IDs collide, unless
+// WithOverflowWait or WithMonotonic is set, in which case they wait for the
+// next timestamp.
+//
+// Returns: The generator's throughput ceiling per millisecond
+func (g *IDGenerator) MaxIDsPerMillisecond() int {
+	return int(g.maxCounter()+1) * int(time.Millisecond/g.resolution)
+}
+
+// StringIDCollisionProbability returns the approximate probability that at
+// least two of count string IDs are identical, for IDs with suffixLen random
+// characters from CharSet.
+//
+// IDs from one generator never share a core, so the estimate models the
+// worst case: count IDs generated in the same millisecond by generators that
+// share a machine and instance ID, with counters landing uniformly at random.
+// The space of distinct IDs is then MaxCounter+1 cores times 36^suffixLen
+// suffixes, and the birthday bound gives 1 - exp(-count(count-1) / 2*space).
+// With the default 8-character suffix (RandomSuffixLength), a thousand such
+// IDs collide with a probability of about 1e-11, and the risk stays below
+// one in a million up to about 300,000 IDs.
+//
+// Parameters:
+//   - count: The number of IDs generated
+//   - suffixLen: The length of the random suffix
+//
+// Returns: The collision probability between 0 and 1
+func StringIDCollisionProbability(count int, suffixLen int) float64 {
+	if count <= 1 {
+		return 0
+	}
+	if suffixLen < 0 {
+		suffixLen = 0
+	}
+
+	space := float64(MaxCounter+1) * math.Pow(float64(len(CharSet)), float64(suffixLen))
+	pairs := float64(count) * float64(count-1) / 2
+	return -math.Expm1(-pairs / space)
+}
+
+// CanGenerate reports whether n more IDs fit in the counter capacity left in
+// the current timestamp, so rate-limited callers can pace bursts without
+// overflowing the counter. Capacity follows the generator's layout and the
+// counter bits given up to tags and parity (see MaxIDsPerMillisecond).
+//
+// The answer is a snapshot: other goroutines generating IDs may use the
+// capacity first. With WithShardedCounter, capacity is counted across all
+// shards, although a single shard can overflow sooner. The generator only
+// starts counting IDs per timestamp on the first call, so the first answer
+// can overstate what is left of the timestamp in progress.
+//
+// Parameters:
+//   - n: The number of IDs about to be generated
+//
+// Returns: True if n IDs can be generated without exhausting the counter
+func (g *IDGenerator) CanGenerate(n int) bool {
+	return n <= 0 || uint64(n) <= g.remainingCapacity()
+}
+
+// WaitForCapacity blocks until CanGenerate(n) holds, waiting for the clock to
+// move on to a new timestamp with a fresh counter.
+//
+// Parameters:
+//   - ctx: The context bounding the wait
+//   - n: The number of IDs about to be generated
+//
+// Returns: ctx.Err() if the context was done first, an error if n exceeds
+// the capacity of a whole timestamp, otherwise nil
+func (g *IDGenerator) WaitForCapacity(ctx context.Context, n int) error {
+	if capacity := g.maxCounter() + 1; n > 0 && uint64(n) > capacity {
+		return fmt.Errorf("%d IDs exceed the per-timestamp capacity of %d", n, capacity)
+	}
+
+	for !g.CanGenerate(n) {
+		if err := g.waitAfterContext(ctx, g.timestamp()); err != nil {
+			return err
+		}
+	}
+	return ctx.Err()
+}
+
+// remainingCapacity returns the number of counter values left in the
+// current timestamp.
+//
+// Returns: The remaining capacity, between 0 and maxCounter+1
+func (g *IDGenerator) remainingCapacity() uint64 {
+	capacity := g.maxCounter() + 1
+	now := g.timestamp()
+
+	if g.monotonic || g.overflowWait {
+		g.seqMu.Lock()
+		defer g.seqMu.Unlock()
+
+		// Mirror trySequenceLocked: a monotonic generator keeps using its
+		// last timestamp while the clock is behind it
+		if g.monotonic && now < g.lastTimestamp {
+			now = g.lastTimestamp
+		}
+		if now != g.lastTimestamp {
+			return capacity
+		}
+		return g.maxCounter() - g.sequence
+	}
+
+	// The free-running counter is only tracked once capacity is asked for
+	atomic.StoreInt32(&g.windowTracking, 1)
+
+	// Free-running IDs go after any timestamps claimed by spilling batches
+	if floor := atomic.LoadUint64(&g.batchFloor); now < floor {
+		now = floor
+	}
+	if atomic.LoadUint64(&g.windowTimestamp) != now {
+		return capacity
+	}
+	used := g.counterValue() - atomic.LoadUint64(&g.windowBase)
+	if used >= capacity {
+		return 0
+	}
+	return capacity - used
+}
+
+// trackWindow records the counter value at the start of each timestamp, so
+// remainingCapacity can tell how much of the current timestamp is used, and
+// reports the timestamp to the observer if its counter has wrapped.
+//
+// Tracking costs an atomic compare-and-swap per ID, so it only starts once
+// remainingCapacity is first called, by CanGenerate, WaitForCapacity or a
+// batch, or at construction when the generator has an observer. Until then
+// this is a single load; the timestamp in progress when tracking starts may report more
+// capacity than it has left.
+//
+// Parameters:
+//   - timestamp: The timestamp of the IDs just generated
+//   - n: The number of IDs just generated with that timestamp
+func (g *IDGenerator) trackWindow(timestamp, n uint64) {
+	if atomic.LoadInt32(&g.windowTracking) == 0 {
+		return
+	}
+	g.trackWindowSlow(timestamp, n)
+}
+
+// trackWindowSlow is the tracking part of trackWindow.
+//
+// Parameters:
+//   - timestamp: The timestamp of the IDs just generated
+//   - n: The number of IDs just generated with that timestamp
+func (g *IDGenerator) trackWindowSlow(timestamp, n uint64) {
+	for {
+		last := atomic.LoadUint64(&g.windowTimestamp)
+		if timestamp < last {
+			return
+		}
+		if timestamp == last {
+			break
+		}
+		if atomic.CompareAndSwapUint64(&g.windowTimestamp, last, timestamp) {
+			atomic.StoreUint64(&g.windowBase, g.counterValue()-n)
+			break
+		}
+	}
+
+	if g.observer != nil {
+		g.observeWindow(timestamp)
+	}
+}
@@ -0,0 +1,298 @@
+package tsuniqid
+
+import (
+	"context"
+	"math"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestSimulateFleet tests that within-capacity deployments report no
+// collisions while over-capacity deployments do.
+func TestSimulateFleet(t *testing.T) {
+	testCases := []struct {
+		name                string
+		machines            int
+		instancesPerMachine int
+		idsPerMs            int
+		durationMs          int
+		expectCollisions    bool
+	}{
+		{
+			name:                "Within capacity",
+			machines:            4,
+			instancesPerMachine: 4,
+			idsPerMs:            1000,
+			durationMs:          5,
+			expectCollisions:    false,
+		},
+		{
+			name:                "Full ID space at counter limit",
+			machines:            MaxMachineID + 1,
+			instancesPerMachine: 1,
+			idsPerMs:            MaxCounter + 1,
+			durationMs:          1,
+			expectCollisions:    false,
+		},
+		{
+			name:                "Counter overflow within a millisecond",
+			machines:            1,
+			instancesPerMachine: 1,
+			idsPerMs:            MaxCounter + 2,
+			durationMs:          1,
+			expectCollisions:    true,
+		},
+		{
+			name:                "More machines than machine IDs",
+			machines:            MaxMachineID + 2,
+			instancesPerMachine: 1,
+			idsPerMs:            10,
+			durationMs:          2,
+			expectCollisions:    true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			collisions := SimulateFleet(tc.machines, tc.instancesPerMachine, tc.idsPerMs, tc.durationMs)
+
+			if tc.expectCollisions && collisions == 0 {
+				t.Errorf("Expected collisions, got none")
+			}
+			if !tc.expectCollisions && collisions != 0 {
+				t.Errorf("Expected no collisions, got %d", collisions)
+			}
+		})
+	}
+
+	// The simulation must be deterministic
+	if a, b := SimulateFleet(17, 2, 100, 3), SimulateFleet(17, 2, 100, 3); a != b {
+		t.Errorf("Simulation not deterministic: %d vs %d", a, b)
+	}
+}
+
+// TestLayout_SimulateFleet tests that the simulation follows the layout's
+// field widths rather than the default layout's.
+func TestLayout_SimulateFleet(t *testing.T) {
+	if a, b := DefaultLayout.SimulateFleet(17, 2, 100, 3), SimulateFleet(17, 2, 100, 3); a != b {
+		t.Errorf("Default layout disagrees with SimulateFleet: %d vs %d", a, b)
+	}
+
+	// An 8-bit counter overflows at 257 IDs per millisecond, which the
+	// default 14-bit counter absorbs
+	narrow := Layout{MachineBits: 4, InstanceBits: 4, TimestampBits: 42, CounterBits: 8, ReservedBits: 6}
+	if c := narrow.SimulateFleet(1, 1, 257, 2); c == 0 {
+		t.Errorf("Expected collisions with an 8-bit counter")
+	}
+	if c := narrow.SimulateFleet(1, 1, 256, 2); c != 0 {
+		t.Errorf("Expected no collisions at the 8-bit counter limit, got %d", c)
+	}
+	if c := SimulateFleet(1, 1, 257, 2); c != 0 {
+		t.Errorf("Expected no collisions with the default layout, got %d", c)
+	}
+
+	// Wider machine fields fit fleets that wrap in the default layout
+	wide := Layout{MachineBits: 8, InstanceBits: 4, TimestampBits: 38, CounterBits: 14}
+	if c := wide.SimulateFleet(MaxMachineID+2, 1, 10, 2); c != 0 {
+		t.Errorf("Expected no collisions with 8 machine bits, got %d", c)
+	}
+	if c := SimulateFleet(MaxMachineID+2, 1, 10, 2); c == 0 {
+		t.Errorf("Expected collisions with 4 machine bits")
+	}
+
+	// The watermark wraps with the counter, so it adds no capacity
+	if c := WatermarkLayout.SimulateFleet(1, 1, 256, 2); c != 0 {
+		t.Errorf("Expected no collisions at the watermark layout's limit, got %d", c)
+	}
+	if c := WatermarkLayout.SimulateFleet(1, 1, 257, 1); c != 1 {
+		t.Errorf("Expected one collision past the watermark layout's limit, got %d", c)
+	}
+
+	if c := (Layout{}).SimulateFleet(1, 1, 10, 1); c != 0 {
+		t.Errorf("Expected 0 for an invalid layout, got %d", c)
+	}
+}
+
+// TestMachineIDCollisionProbability tests the birthday bound over the
+// machine ID space.
+func TestMachineIDCollisionProbability(t *testing.T) {
+	if p := MachineIDCollisionProbability(1); p != 0 {
+		t.Errorf("Expected 0 for a single node, got %f", p)
+	}
+	if p := MachineIDCollisionProbability(0); p != 0 {
+		t.Errorf("Expected 0 for no nodes, got %f", p)
+	}
+	if p := MachineIDCollisionProbability(2); math.Abs(p-1.0/16) > 1e-12 {
+		t.Errorf("Expected 1/16 for two nodes, got %f", p)
+	}
+
+	// The risk rises monotonically and is near certain well before 16 nodes
+	prev := 0.0
+	for n := 2; n <= MaxMachineID+2; n++ {
+		p := MachineIDCollisionProbability(n)
+		if p <= prev && prev < 1 {
+			t.Errorf("Probability not increasing at %d nodes: %f <= %f", n, p, prev)
+		}
+		prev = p
+	}
+	if p := MachineIDCollisionProbability(10); p < 0.95 {
+		t.Errorf("Expected > 95%% at 10 nodes, got %f", p)
+	}
+	if p := MachineIDCollisionProbability(MaxMachineID + 2); p != 1 {
+		t.Errorf("Expected 1 beyond the ID space, got %f", p)
+	}
+}
+
+// TestMaxIDsPerMillisecond tests the per-millisecond throughput ceiling for
+// the default and custom layouts.
+func TestMaxIDsPerMillisecond(t *testing.T) {
+	if n := MaxIDsPerMillisecond(); n != 16384 {
+		t.Errorf("Expected 16384 for the default layout, got %d", n)
+	}
+	if n := NewGenerator().MaxIDsPerMillisecond(); n != MaxIDsPerMillisecond() {
+		t.Errorf("Expected default generator to match package value, got %d", n)
+	}
+
+	gen, err := NewGeneratorWithLayout(WatermarkLayout)
+	if err != nil {
+		t.Fatalf("Failed to create generator: %v", err)
+	}
+	if n := gen.MaxIDsPerMillisecond(); n != 256 {
+		t.Errorf("Expected 256 for the watermark layout, got %d", n)
+	}
+
+	if n := NewGenerator(WithParity()).MaxIDsPerMillisecond(); n != 8192 {
+		t.Errorf("Expected 8192 with parity, got %d", n)
+	}
+}
+
+// TestStringIDCollisionProbability tests the birthday bound over the core and
+// suffix space.
+func TestStringIDCollisionProbability(t *testing.T) {
+	if p := StringIDCollisionProbability(1, RandomSuffixLength); p != 0 {
+		t.Errorf("Expected 0 for a single ID, got %g", p)
+	}
+
+	// Two IDs without a suffix collide when their counters match
+	if p := StringIDCollisionProbability(2, 0); math.Abs(p-1.0/16384) > 1e-8 {
+		t.Errorf("Expected about 1/16384 for two IDs without suffix, got %g", p)
+	}
+	if p := StringIDCollisionProbability(2, -1); p != StringIDCollisionProbability(2, 0) {
+		t.Errorf("Expected a negative suffix length to count as zero, got %g", p)
+	}
+
+	// Every suffix character lowers the risk, and more IDs raise it
+	prev := 1.0
+	for length := 0; length <= 12; length++ {
+		p := StringIDCollisionProbability(1000, length)
+		if p >= prev {
+			t.Errorf("Probability not decreasing at suffix length %d: %g >= %g", length, p, prev)
+		}
+		prev = p
+	}
+	if p := StringIDCollisionProbability(1000, RandomSuffixLength); math.Abs(p-1.08e-11) > 1e-13 {
+		t.Errorf("Expected about 1.08e-11 for 1000 IDs with the default suffix, got %g", p)
+	}
+	if p := StringIDCollisionProbability(300000, RandomSuffixLength); p >= 1e-6 {
+		t.Errorf("Expected < 1e-6 for 300000 IDs with the default suffix, got %g", p)
+	}
+	if p := StringIDCollisionProbability(100000, 0); p < 0.999 {
+		t.Errorf("Expected near certainty for 100000 IDs without suffix, got %g", p)
+	}
+}
+
+// TestIDGenerator_CanGenerate tests the remaining capacity of the current
+// timestamp in the free-running and sequenced counter modes.
+func TestIDGenerator_CanGenerate(t *testing.T) {
+	now := time.Now()
+	layout := Layout{MachineBits: 4, InstanceBits: 4, TimestampBits: 48, CounterBits: 8}
+
+	for _, tc := range []struct {
+		name string
+		opts []Option
+	}{
+		{"free-running", nil},
+		{"overflow wait", []Option{WithOverflowWait()}},
+		{"monotonic", []Option{WithMonotonic()}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			clock := newFakeClock(now, 0)
+			gen, err := NewGeneratorWithLayout(layout, append(tc.opts, WithClock(clock.Now))...)
+			if err != nil {
+				t.Fatalf("Failed to create generator: %v", err)
+			}
+
+			if !gen.CanGenerate(256) || gen.CanGenerate(257) {
+				t.Errorf("Expected a fresh timestamp to fit exactly 256 IDs")
+			}
+
+			gen.GenerateUint64IDs(200)
+			if !gen.CanGenerate(56) || gen.CanGenerate(57) {
+				t.Errorf("Expected 56 IDs left after 200")
+			}
+			if !gen.CanGenerate(0) {
+				t.Errorf("Expected zero IDs to always fit")
+			}
+
+			// The next timestamp restores the full capacity
+			clock.Set(now.Add(time.Millisecond), 0)
+			if !gen.CanGenerate(256) {
+				t.Errorf("Expected full capacity in a new timestamp")
+			}
+		})
+	}
+}
+
+// TestIDGenerator_CanGenerate_Lazy tests that a generator only tracks its
+// free-running counter per timestamp once capacity is first asked for.
+func TestIDGenerator_CanGenerate_Lazy(t *testing.T) {
+	clock := newFakeClock(time.Now(), 0)
+	gen := NewGenerator(WithClock(clock.Now))
+
+	for i := 0; i < 100; i++ {
+		gen.GenerateUint64ID()
+	}
+	if atomic.LoadUint64(&gen.windowTimestamp) != 0 {
+		t.Errorf("Expected no tracking before the first capacity call")
+	}
+
+	// The timestamp in progress is not known to be used yet
+	if !gen.CanGenerate(MaxCounter + 1) {
+		t.Errorf("Expected full capacity on the first capacity call")
+	}
+
+	for i := 0; i < 100; i++ {
+		gen.GenerateUint64ID()
+	}
+	if !gen.CanGenerate(MaxCounter+1-100) || gen.CanGenerate(MaxCounter+1-99) {
+		t.Errorf("Expected the IDs after the first capacity call to be counted")
+	}
+}
+
+// TestIDGenerator_WaitForCapacity tests waiting for the clock to free up
+// capacity and giving up with the context.
+func TestIDGenerator_WaitForCapacity(t *testing.T) {
+	gen := NewGenerator(WithOverflowWait())
+	gen.GenerateUint64IDs(MaxCounter)
+
+	if err := gen.WaitForCapacity(context.Background(), 100); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if !gen.CanGenerate(100) {
+		t.Errorf("Expected capacity after waiting")
+	}
+
+	if err := gen.WaitForCapacity(context.Background(), MaxCounter+2); err == nil {
+		t.Errorf("Expected error for more IDs than a timestamp can hold")
+	}
+
+	// A stuck clock never frees capacity
+	stuck := NewGenerator(WithOverflowWait(), WithClock(func() time.Time { return time.UnixMilli(1e12) }))
+	stuck.GenerateUint64IDs(MaxCounter + 1)
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := stuck.WaitForCapacity(ctx, 1); err != context.DeadlineExceeded {
+		t.Errorf("Expected deadline exceeded, got %v", err)
+	}
+}
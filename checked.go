@@ -0,0 +1,71 @@
+// Package tsuniqid - Check characters for string IDs
+package tsuniqid
+
+import (
+	"strings"
+)
+
+// checkChar computes the Luhn mod N check character of s over CharSet, which
+// detects every single-character substitution and most transpositions of
+// adjacent characters.
+//
+// Parameters:
+//   - s: The string to protect
+//
+// Returns:
+//   - byte: The check character from CharSet
+//   - bool: False if s contains a character outside CharSet
+func checkChar(s string) (byte, bool) {
+	n := len(CharSet)
+	factor, sum := 2, 0
+
+	// Double every other code point, starting from the rightmost one
+	for i := len(s) - 1; i >= 0; i-- {
+		codePoint := strings.IndexByte(CharSet, s[i])
+		if codePoint < 0 {
+			return 0, false
+		}
+
+		addend := factor * codePoint
+		sum += addend/n + addend%n
+		factor = 3 - factor
+	}
+
+	return CharSet[(n-sum%n)%n], true
+}
+
+// VerifyCheckedSuffix reports whether the last character of a string ID
+// generated with WithCheckedSuffix matches the check character computed over
+// the rest of the ID, so a mistyped character can be detected before lookup.
+//
+// Parameters:
+//   - id: The string ID to verify
+//
+// Returns: True if the check character is valid
+func VerifyCheckedSuffix(id string) bool {
+	if len(id) < 2 {
+		return false
+	}
+
+	check, ok := checkChar(id[:len(id)-1])
+	return ok && id[len(id)-1] == check
+}
+
+// withCheckChar replaces the last character of a string ID with its check
+// character when the generator was created with WithCheckedSuffix.
+//
+// Parameters:
+//   - id: The string ID, ending in its random suffix
+//
+// Returns: The string ID, with a check character if enabled
+func (g *IDGenerator) withCheckChar(id string) string {
+	if !g.checkedSuffix || id == "" {
+		return id
+	}
+
+	check, ok := checkChar(id[:len(id)-1])
+	if !ok {
+		return id
+	}
+	return id[:len(id)-1] + string(check)
+}
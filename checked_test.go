@@ -0,0 +1,74 @@
+package tsuniqid
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+// TestWithCheckedSuffix tests that checked string IDs verify and that every
+// single-character corruption is detected.
+func TestWithCheckedSuffix(t *testing.T) {
+	gen := NewGenerator(WithCheckedSuffix())
+
+	ids := append([]string{gen.GenerateStringID(), gen.GenerateFixedStringID()}, gen.GenerateStringIDs(10)...)
+	for _, id := range ids {
+		if !VerifyCheckedSuffix(id) {
+			t.Errorf("Valid ID failed verification: %s", id)
+		}
+		if !ValidStringID(id) {
+			t.Errorf("Checked ID has an invalid format: %s", id)
+		}
+	}
+
+	id := ids[0]
+	for i := 0; i < len(id); i++ {
+		for j := 0; j < len(CharSet); j++ {
+			if CharSet[j] == id[i] {
+				continue
+			}
+
+			corrupted := id[:i] + string(CharSet[j]) + id[i+1:]
+			if VerifyCheckedSuffix(corrupted) {
+				t.Errorf("Corruption at %d not detected: %s -> %s", i, id, corrupted)
+			}
+		}
+	}
+
+	for _, invalid := range []string{"", "a", id[:len(id)-1] + "-", "-" + id[1:]} {
+		if VerifyCheckedSuffix(invalid) {
+			t.Errorf("Expected %q to fail verification", invalid)
+		}
+	}
+
+	if _, err := NewGeneratorWithError(WithCheckedSuffix(), WithSuffixLength(0)); err == nil {
+		t.Errorf("Expected error for checked suffix without a suffix")
+	}
+}
+
+// TestWithCheckedSuffix_CustomCharSet tests that a checked suffix combined
+// with a custom character set is rejected by NewGeneratorWithError and
+// dropped by NewGenerator instead of producing an invalid last character.
+func TestWithCheckedSuffix_CustomCharSet(t *testing.T) {
+	const charset = "ABCDEFGH"
+	if _, err := NewGeneratorWithError(WithCharSet(charset), WithCheckedSuffix()); err == nil {
+		t.Errorf("Expected error for a checked suffix with a custom character set")
+	}
+
+	var logs bytes.Buffer
+	gen := NewGenerator(WithCharSet(charset), WithCheckedSuffix(), WithLogger(slog.New(slog.NewTextHandler(&logs, nil))))
+	if !strings.Contains(logs.String(), "checked suffix disabled") {
+		t.Errorf("Expected a warning, got %q", logs.String())
+	}
+
+	ids := []string{gen.GenerateStringID(), string(gen.AppendStringID(nil)), gen.GenerateFixedStringID()}
+	ids = append(ids, gen.GenerateStringIDs(10)...)
+	for _, id := range ids {
+		for _, c := range id[len(id)-gen.suffixLength:] {
+			if !strings.ContainsRune(charset, c) {
+				t.Errorf("ID %q has suffix character %q outside the character set", id, c)
+			}
+		}
+	}
+}
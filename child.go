@@ -0,0 +1,75 @@
+// Package tsuniqid - Correlated parent/child ID generation
+package tsuniqid
+
+import "errors"
+
+// Bit allocation constants for child IDs.
+//
+// A child ID uses the regular machine/instance/timestamp layout, but splits the
+// 14-bit counter field into a parent reference and a shorter counter:
+// - Bits 13-8 (6 bits): Parent reference (low bits of the parent ID)
+// - Bits 7-0 (8 bits): Child counter
+const (
+	// MaxParentRef represents the maximum parent reference value (6 bits)
+	MaxParentRef = 0x3f
+
+	// MaxChildCounter represents the maximum child counter value (8 bits)
+	MaxChildCounter = 0xff
+
+	// ParentRefShift is the number of bits to shift the parent reference
+	ParentRefShift = 8
+)
+
+// ErrChildUnsupported is returned by GenerateChild on generators whose IDs
+// do not use the plain default layout, where a child ID could not keep its
+// parent reference or would break the generator's other guarantees.
+var ErrChildUnsupported = errors.New("child IDs need the default layout without parity, interleaving, insert spreading, sharded counters or environment/service tags")
+
+// GenerateChild creates a unique uint64 identifier that carries a short
+// reference to its parent ID, so a child can be loosely associated with its
+// parent from the ID alone.
+//
+// The reference is only the low 6 bits of the parent, so it narrows down
+// candidates rather than identifying the parent exactly. Each child takes
+// the place of one regular ID: it replaces bits 13-8 of that ID's counter
+// field with the reference. It therefore stays unique among children and
+// regular IDs from the same generator as long as the generator issues at
+// most 256 IDs, children included, per millisecond. The limit is not
+// enforced: beyond it a child can repeat a regular ID or another child, and
+// the 257th child of the same parent within a millisecond repeats the first.
+//
+// Only generators with the default layout and no parity bit, bit
+// interleaving, insert spreading, sharded counter, environment tag or
+// service code can generate child IDs; the tags sit in the bits the parent
+// reference would overwrite. GenerateChild reports other generators with an
+// error instead of returning an ID that could repeat or lose its reference.
+//
+// Parameters:
+//   - parent: The parent ID to reference
+//
+// Returns:
+//   - uint64: A unique uint64 identifier embedding the parent reference
+//   - error: ErrChildUnsupported if the generator cannot generate child IDs
+func (g *IDGenerator) GenerateChild(parent uint64) (uint64, error) {
+	if g.layout != DefaultLayout || g.parity || g.interleave || g.spreadBits > 0 || g.shards != nil ||
+		g.tagBits() > 0 {
+		return 0, ErrChildUnsupported
+	}
+
+	id := g.GenerateUint64ID()
+	ref := parent & MaxParentRef
+
+	return (id &^ MaxCounter) |
+		(ref << ParentRefShift) |
+		(id & MaxChildCounter), nil
+}
+
+// ParentRefOf extracts the parent reference embedded in a child ID.
+//
+// Parameters:
+//   - child: A child ID created by GenerateChild
+//
+// Returns: The low 6 bits of the parent ID
+func ParentRefOf(child uint64) uint64 {
+	return (child >> ParentRefShift) & MaxParentRef
+}
@@ -0,0 +1,128 @@
+package tsuniqid
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestIDGenerator_GenerateChild tests that child IDs are unique and carry
+// the expected parent reference bits.
+func TestIDGenerator_GenerateChild(t *testing.T) {
+	gen := NewGenerator()
+	parents := []uint64{gen.GenerateUint64ID(), gen.GenerateUint64ID(), 0x3f, 0x40}
+
+	// Stay below the 256 child IDs a generator can issue per millisecond
+	const childrenPerParent = 50
+
+	seen := make(map[uint64]bool)
+	for _, parent := range parents {
+		for i := 0; i < childrenPerParent; i++ {
+			child, err := gen.GenerateChild(parent)
+			if err != nil {
+				t.Fatalf("GenerateChild failed: %v", err)
+			}
+
+			if seen[child] {
+				t.Errorf("Duplicate child ID: %d", child)
+			}
+			seen[child] = true
+
+			if ref := ParentRefOf(child); ref != parent&MaxParentRef {
+				t.Errorf("Parent reference mismatch: got %d, expected %d", ref, parent&MaxParentRef)
+			}
+
+			// The machine and instance bits must match the generator's regular IDs
+			if child>>InstanceIDShift != parents[0]>>InstanceIDShift {
+				t.Errorf("Child %x does not share generator bits with %x", child, parents[0])
+			}
+		}
+	}
+}
+
+// TestIDGenerator_GenerateChild_Mixed tests that children never repeat
+// regular IDs from the same generator within the documented rate of 256 IDs
+// per millisecond.
+func TestIDGenerator_GenerateChild_Mixed(t *testing.T) {
+	for _, opts := range [][]Option{nil, {WithMonotonic()}} {
+		clock := newFakeClock(time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC), 10*time.Microsecond)
+		gen := NewGenerator(append(opts, WithClock(clock.Now))...)
+
+		seen := make(map[uint64]bool)
+		record := func(id uint64) {
+			if seen[id] {
+				t.Fatalf("Duplicate ID %x among regular and child IDs", id)
+			}
+			seen[id] = true
+		}
+
+		for i := 0; i < 20000; i++ {
+			record(gen.GenerateUint64ID())
+
+			child, err := gen.GenerateChild(uint64(i))
+			if err != nil {
+				t.Fatalf("GenerateChild failed: %v", err)
+			}
+			record(child)
+		}
+	}
+}
+
+// TestIDGenerator_GenerateChild_Unsupported tests that generators whose IDs
+// leave no room for a parent reference reject child IDs.
+func TestIDGenerator_GenerateChild_Unsupported(t *testing.T) {
+	layout := Layout{MachineBits: 4, InstanceBits: 4, TimestampBits: 44, CounterBits: 12}
+	custom, err := NewGeneratorWithLayout(layout)
+	if err != nil {
+		t.Fatalf("NewGeneratorWithLayout failed: %v", err)
+	}
+	snowflake, err := NewSnowflakeGenerator(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), 1)
+	if err != nil {
+		t.Fatalf("NewSnowflakeGenerator failed: %v", err)
+	}
+
+	for _, gen := range []*IDGenerator{
+		custom,
+		NewGenerator(WithParity()),
+		NewGenerator(WithBitInterleaving()),
+		NewGenerator(WithInsertSpreading(4)),
+		NewGenerator(WithShardedCounter(4)),
+		NewGenerator(WithEnvironment(EnvironmentStaging)),
+		NewGenerator(WithServiceCode(3)),
+		snowflake,
+	} {
+		if _, err := gen.GenerateChild(1); !errors.Is(err, ErrChildUnsupported) {
+			t.Errorf("Expected ErrChildUnsupported from %v, got %v", gen, err)
+		}
+	}
+}
+
+// TestIDGenerator_GenerateChild_Ceiling tests the documented limit of 256
+// IDs per millisecond: within one millisecond, 256 children of a parent are
+// unique and the 257th repeats the first.
+func TestIDGenerator_GenerateChild_Ceiling(t *testing.T) {
+	clock := newFakeClock(time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC), 0)
+	gen := NewGenerator(WithClock(clock.Now))
+
+	children := make([]uint64, MaxChildCounter+2)
+	seen := make(map[uint64]bool)
+	for i := range children {
+		child, err := gen.GenerateChild(42)
+		if err != nil {
+			t.Fatalf("GenerateChild failed: %v", err)
+		}
+		children[i] = child
+
+		if i <= MaxChildCounter {
+			if seen[child] {
+				t.Fatalf("Duplicate child ID %x within the ceiling, at %d", child, i)
+			}
+			seen[child] = true
+		}
+	}
+
+	if children[MaxChildCounter+1] != children[0] {
+		t.Errorf("Expected child %d to repeat the first, got %x and %x",
+			MaxChildCounter+1, children[MaxChildCounter+1], children[0])
+	}
+}
@@ -0,0 +1,172 @@
+// Package tsuniqid - Context-aware ID generation for distributed tracing
+package tsuniqid
+
+import (
+	"context"
+	"crypto/sha1"
+	"runtime"
+	"strconv"
+	"time"
+)
+
+// TraceIDKey is the well-known context key read by GenerateForContext.
+// The associated value must be a string trace ID.
+type TraceIDKey struct{}
+
+// ContextWithTraceID returns a copy of ctx carrying the given trace ID under
+// TraceIDKey.
+//
+// Parameters:
+//   - ctx: The parent context
+//   - traceID: The trace ID to attach
+//
+// Returns: A context carrying the trace ID
+func ContextWithTraceID(ctx context.Context, traceID string) context.Context {
+	return context.WithValue(ctx, TraceIDKey{}, traceID)
+}
+
+// GenerateForContext creates a unique string identifier grouped by the trace
+// ID stored in ctx under TraceIDKey.
+//
+// When a trace ID is present, the random suffix is replaced by the
+// generator's TraceSuffix of the trace ID, so every ID generated for the same
+// trace shares its suffix while the hex core keeps IDs unique. The suffix
+// follows WithSuffixLength and WithCharSet, and with WithCheckedSuffix its
+// last character is the ID's check character, like GenerateStringID. Without
+// a trace ID this behaves like GenerateStringID.
+//
+// Parameters:
+//   - ctx: The context to read the trace ID from
+//
+// Returns: A unique string identifier
+func (g *IDGenerator) GenerateForContext(ctx context.Context) string {
+	traceID, ok := ctx.Value(TraceIDKey{}).(string)
+	if !ok || traceID == "" {
+		return g.GenerateStringID()
+	}
+
+	id := strconv.FormatUint(g.GenerateUint64ID(), 16) + g.TraceSuffix(traceID)
+	if g.suffixLength == 0 {
+		return id
+	}
+	return g.withCheckChar(id)
+}
+
+// TraceSuffix derives the deterministic suffix used by GenerateForContext
+// for a trace ID with the default suffix settings. It can be used to find
+// all IDs belonging to a trace.
+//
+// Parameters:
+//   - traceID: The trace ID to derive the suffix from
+//
+// Returns: A RandomSuffixLength-character string drawn from CharSet
+func TraceSuffix(traceID string) string {
+	return traceSuffix(traceID, CharSet, RandomSuffixLength)
+}
+
+// TraceSuffix derives the suffix this generator's GenerateForContext uses
+// for a trace ID, following WithSuffixLength and WithCharSet. With
+// WithCheckedSuffix, IDs replace its last character with their check
+// character, so match on all but the last character.
+//
+// Parameters:
+//   - traceID: The trace ID to derive the suffix from
+//
+// Returns: A suffix of the generator's suffix length drawn from its character set
+func (g *IDGenerator) TraceSuffix(traceID string) string {
+	return traceSuffix(traceID, g.charSet, g.suffixLength)
+}
+
+// traceSuffix maps the SHA-1 hash of a trace ID onto a character set. Suffixes
+// longer than one hash continue with the hash of the previous hash, so the
+// default-length suffix is a prefix of every longer one.
+//
+// Parameters:
+//   - traceID: The trace ID to derive the suffix from
+//   - charSet: The characters of the suffix
+//   - length: The number of characters
+//
+// Returns: The suffix
+func traceSuffix(traceID, charSet string, length int) string {
+	hash := sha1.Sum([]byte(traceID))
+
+	result := make([]byte, length)
+	for i := range result {
+		if i > 0 && i%len(hash) == 0 {
+			hash = sha1.Sum(hash[:])
+		}
+		result[i] = charSet[int(hash[i%len(hash)])%len(charSet)]
+	}
+
+	return string(result)
+}
+
+// GenerateUint64IDContext creates a unique uint64 identifier like
+// GenerateUint64ID, but gives up when ctx is done.
+//
+// In the sequenced modes enabled by WithMonotonic and WithOverflowWait, an
+// exhausted counter blocks until the clock reaches the next millisecond. If
+// the clock is stuck, GenerateUint64ID would block forever; this method
+// returns ctx.Err() instead, so request handlers stay responsive. Callers
+// waiting behind it for the same generator are released when it gives up.
+//
+// Parameters:
+//   - ctx: The context bounding the wait
+//
+// Returns:
+//   - uint64: A unique uint64 identifier
+//   - error: ctx.Err() if the context was done before an ID was available
+func (g *IDGenerator) GenerateUint64IDContext(ctx context.Context) (uint64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	if !g.monotonic && !g.overflowWait {
+		return g.GenerateUint64ID(), nil
+	}
+
+	g.seqMu.Lock()
+	defer g.seqMu.Unlock()
+
+	for {
+		if timestamp, counter, ok := g.trySequenceLocked(); ok {
+			return g.compose(timestamp, counter), nil
+		}
+		g.observeOverflow()
+		if err := g.waitAfterContext(ctx, g.lastTimestamp); err != nil {
+			return 0, err
+		}
+	}
+}
+
+// waitAfterContext is waitAfter, but returns early when ctx is done.
+//
+// Parameters:
+//   - ctx: The context bounding the wait
+//   - timestamp: The timestamp the clock must pass
+//
+// Returns: ctx.Err() if the context was done first, otherwise nil
+func (g *IDGenerator) waitAfterContext(ctx context.Context, timestamp uint64) error {
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		now := g.timestamp()
+		if now > timestamp {
+			return nil
+		}
+
+		if gap := timestamp - now; gap > 0 {
+			timer := time.NewTimer(time.Duration(gap) * g.resolution)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return ctx.Err()
+			case <-timer.C:
+			}
+		} else {
+			runtime.Gosched()
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package tsuniqid
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestIDGenerator_GenerateForContext tests that a trace ID in the context
+// appears in the generated ID's suffix and that IDs remain unique.
+func TestIDGenerator_GenerateForContext(t *testing.T) {
+	gen := NewGenerator()
+	ctx := ContextWithTraceID(context.Background(), "4bf92f3577b34da6a3ce929d0e0e4736")
+	expectedSuffix := TraceSuffix("4bf92f3577b34da6a3ce929d0e0e4736")
+
+	seen := make(map[string]bool)
+	for i := 0; i < 1000; i++ {
+		id := gen.GenerateForContext(ctx)
+
+		if seen[id] {
+			t.Errorf("Duplicate ID found: %s", id)
+		}
+		seen[id] = true
+
+		suffix := id[len(id)-RandomSuffixLength:]
+		if suffix != expectedSuffix {
+			t.Errorf("Trace suffix mismatch in %s: got %s, expected %s", id, suffix, expectedSuffix)
+		}
+
+		hexPart := id[:len(id)-RandomSuffixLength]
+		if _, err := strconv.ParseUint(hexPart, 16, 64); err != nil {
+			t.Errorf("Invalid hex part in ID %s: %v", id, err)
+		}
+	}
+
+	// Different traces produce different suffixes
+	if TraceSuffix("trace-a") == TraceSuffix("trace-b") {
+		t.Errorf("Different traces produced the same suffix")
+	}
+}
+
+// TestIDGenerator_GenerateForContext_SuffixOptions tests that trace
+// suffixes follow WithSuffixLength, WithCharSet and WithCheckedSuffix.
+func TestIDGenerator_GenerateForContext_SuffixOptions(t *testing.T) {
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	ctx := ContextWithTraceID(context.Background(), traceID)
+
+	tests := []struct {
+		name string
+		opts []Option
+	}{
+		{"long", []Option{WithSuffixLength(24)}},
+		{"charset", []Option{WithCharSet("xyz")}},
+		{"checked", []Option{WithCheckedSuffix()}},
+		{"none", []Option{WithSuffixLength(0)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gen, err := NewGeneratorWithError(tt.opts...)
+			if err != nil {
+				t.Fatalf("NewGeneratorWithError() error = %v", err)
+			}
+			suffix := gen.TraceSuffix(traceID)
+			if len(suffix) != gen.suffixLength {
+				t.Fatalf("TraceSuffix() = %q, want length %d", suffix, gen.suffixLength)
+			}
+
+			id := gen.GenerateForContext(ctx)
+			core, got := id[:len(id)-len(suffix)], id[len(id)-len(suffix):]
+			if _, err := strconv.ParseUint(core, 16, 64); err != nil {
+				t.Errorf("Invalid hex part in ID %s: %v", id, err)
+			}
+			for _, c := range got {
+				if !strings.ContainsRune(gen.charSet, c) {
+					t.Errorf("Suffix %q of %s has %q outside the character set", got, id, c)
+				}
+			}
+
+			if gen.checkedSuffix {
+				if !VerifyCheckedSuffix(id) {
+					t.Errorf("VerifyCheckedSuffix(%s) = false", id)
+				}
+				got, suffix = got[:len(got)-1], suffix[:len(suffix)-1]
+			}
+			if got != suffix {
+				t.Errorf("Trace suffix of %s = %q, want %q", id, got, suffix)
+			}
+		})
+	}
+
+	// The default-length suffix is a prefix of longer ones
+	if long := NewGenerator(WithSuffixLength(24)).TraceSuffix(traceID); long[:RandomSuffixLength] != TraceSuffix(traceID) {
+		t.Errorf("TraceSuffix() = %q, want prefix %q", long, TraceSuffix(traceID))
+	}
+}
+
+// TestIDGenerator_GenerateForContext_NoTrace tests the fallback to regular
+// string IDs when the context carries no trace ID.
+func TestIDGenerator_GenerateForContext_NoTrace(t *testing.T) {
+	gen := NewGenerator()
+
+	first := gen.GenerateForContext(context.Background())
+	second := gen.GenerateForContext(context.Background())
+
+	if first == second {
+		t.Errorf("Expected distinct IDs, got %s twice", first)
+	}
+	hexPart := first[:len(first)-RandomSuffixLength]
+	if _, err := strconv.ParseUint(hexPart, 16, 64); err != nil {
+		t.Errorf("Invalid hex part in ID %s: %v", first, err)
+	}
+}
+
+// TestIDGenerator_GenerateUint64IDContext tests that IDs are unique while the
+// clock advances and that an exhausted counter on a stuck clock returns the
+// context error instead of blocking.
+func TestIDGenerator_GenerateUint64IDContext(t *testing.T) {
+	clock := newFakeClock(time.Now(), 100*time.Microsecond)
+	gen := NewGenerator(WithClock(clock.Now), WithOverflowWait())
+
+	seen := make(map[uint64]bool)
+	for i := 0; i < 3*int(MaxCounter); i++ {
+		id, err := gen.GenerateUint64IDContext(context.Background())
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if seen[id] {
+			t.Fatalf("Duplicate ID found: %d", id)
+		}
+		seen[id] = true
+	}
+
+	// Stop the clock and exhaust the current millisecond
+	clock.Set(time.Now(), 0)
+	for i := 0; i <= int(MaxCounter); i++ {
+		if _, err := gen.GenerateUint64IDContext(context.Background()); err != nil {
+			t.Fatalf("Unexpected error before exhaustion: %v", err)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := gen.GenerateUint64IDContext(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected deadline exceeded, got %v", err)
+	}
+
+	// Once the clock moves again, generation resumes
+	clock.Set(time.Now().Add(time.Second), time.Millisecond)
+	if _, err := gen.GenerateUint64IDContext(context.Background()); err != nil {
+		t.Errorf("Unexpected error after clock resumed: %v", err)
+	}
+
+	// A context that is already done fails immediately in every mode
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := NewGenerator().GenerateUint64IDContext(canceled); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context canceled, got %v", err)
+	}
+}
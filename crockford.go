@@ -0,0 +1,95 @@
+// Package tsuniqid - Crockford base32 IDs for humans
+package tsuniqid
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Crockford base32 format constants
+const (
+	// CrockfordCharSet is the Crockford base32 alphabet, which omits I, L, O
+	// and U to avoid ambiguous glyphs; the same alphabet as ULIDCharSet
+	CrockfordCharSet = ULIDCharSet
+
+	// CrockfordIDLength is the width of zero-padded Crockford base32 IDs
+	// (the number of characters needed for the maximum uint64 value)
+	CrockfordIDLength = 13
+)
+
+// GenerateCrockfordID creates a unique identifier for humans to read aloud
+// or type, such as support ticket numbers.
+// Format: crockford32(uint64_id) + random_suffix
+//
+// The core is the uint64 ID in Crockford base32, zero-padded to
+// CrockfordIDLength characters, and the random suffix is drawn from the same
+// uppercase alphabet. DecodeCrockford accepts the common typing mistakes.
+//
+// Returns: A unique Crockford base32 identifier
+func (g *IDGenerator) GenerateCrockfordID() string {
+	id := g.GenerateUint64ID()
+	return encodeCrockford(id) + g.generateRandomSuffixFrom(CrockfordCharSet, g.suffixLength)
+}
+
+// DecodeCrockford recovers the uint64 ID from a string created by
+// GenerateCrockfordID, ignoring the random suffix, whatever its length.
+//
+// Input is normalized as the Crockford specification recommends: lowercase
+// letters are accepted, O is read as 0, I and L as 1, and hyphens added for
+// readability are ignored.
+//
+// Parameters:
+//   - s: The Crockford base32 ID
+//
+// Returns:
+//   - uint64: The decoded identifier
+//   - error: An error if s is not a valid Crockford base32 ID
+func DecodeCrockford(s string) (uint64, error) {
+	normalized := make([]byte, 0, len(s))
+	for i := 0; i < len(s); i++ {
+		c := upperASCII(s[i])
+		switch c {
+		case '-':
+			continue
+		case 'O':
+			c = '0'
+		case 'I', 'L':
+			c = '1'
+		}
+
+		if strings.IndexByte(CrockfordCharSet, c) < 0 {
+			return 0, fmt.Errorf("invalid base32 character %q in ID %s", s[i], s)
+		}
+		normalized = append(normalized, c)
+	}
+
+	if len(normalized) < CrockfordIDLength {
+		return 0, fmt.Errorf("invalid base32 ID length %d", len(normalized))
+	}
+
+	// 13 characters hold 65 bits, so the first character must not exceed F
+	if strings.IndexByte(CrockfordCharSet, normalized[0]) > 0xf {
+		return 0, fmt.Errorf("base32 ID %s overflows 64 bits", s)
+	}
+
+	var id uint64
+	for _, c := range normalized[:CrockfordIDLength] {
+		id = id<<5 | uint64(strings.IndexByte(CrockfordCharSet, c))
+	}
+	return id, nil
+}
+
+// encodeCrockford encodes an ID as zero-padded Crockford base32.
+//
+// Parameters:
+//   - id: The identifier to encode
+//
+// Returns: The CrockfordIDLength-character base32 representation
+func encodeCrockford(id uint64) string {
+	result := make([]byte, CrockfordIDLength)
+	for i := CrockfordIDLength - 1; i >= 0; i-- {
+		result[i] = CrockfordCharSet[id&0x1f]
+		id >>= 5
+	}
+	return string(result)
+}
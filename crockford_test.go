@@ -0,0 +1,70 @@
+package tsuniqid
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestIDGenerator_GenerateCrockfordID tests that Crockford IDs use only the
+// unambiguous alphabet and decode back to their uint64 core.
+func TestIDGenerator_GenerateCrockfordID(t *testing.T) {
+	gen := NewGenerator()
+
+	for i := 0; i < 1000; i++ {
+		id := gen.GenerateCrockfordID()
+		if len(id) != CrockfordIDLength+RandomSuffixLength {
+			t.Fatalf("Crockford ID has wrong length: %s", id)
+		}
+		if strings.ContainsAny(id, "ILOUilou") {
+			t.Errorf("Crockford ID contains an ambiguous character: %s", id)
+		}
+
+		value, err := DecodeCrockford(id)
+		if err != nil {
+			t.Fatalf("Failed to decode %s: %v", id, err)
+		}
+		if encodeCrockford(value) != id[:CrockfordIDLength] {
+			t.Errorf("Round-trip mismatch for %s: got %d", id, value)
+		}
+	}
+}
+
+// TestDecodeCrockford tests normalization of ambiguous input and rejection
+// of invalid IDs.
+func TestDecodeCrockford(t *testing.T) {
+	for _, value := range []uint64{0, 1, 0x123456789abcdef0, ^uint64(0)} {
+		encoded := encodeCrockford(value)
+		if decoded, err := DecodeCrockford(encoded); err != nil || decoded != value {
+			t.Errorf("Round-trip of %d via %s: got %d, %v", value, encoded, decoded, err)
+		}
+	}
+	if encoded := encodeCrockford(^uint64(0)); encoded != "FZZZZZZZZZZZZ" {
+		t.Errorf("Unexpected encoding of the maximum value: %s", encoded)
+	}
+
+	testCases := []struct {
+		name     string
+		id       string
+		expected uint64
+	}{
+		{name: "Lowercase", id: "000000000000z", expected: 31},
+		{name: "O as zero", id: "OOOOOOOOOOOO1", expected: 1},
+		{name: "I and L as one", id: "00000000000iL", expected: 33},
+		{name: "Hyphens", id: "0000-0000-0001-0-ABCD", expected: 32},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			decoded, err := DecodeCrockford(tc.id)
+			if err != nil || decoded != tc.expected {
+				t.Errorf("DecodeCrockford(%q) = %d, %v, expected %d", tc.id, decoded, err, tc.expected)
+			}
+		})
+	}
+
+	for _, bad := range []string{"", "000000000000", "00000000000U0", "G000000000000", "0000000000000!"} {
+		if _, err := DecodeCrockford(bad); err == nil {
+			t.Errorf("Expected error for %q", bad)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+// Package tsuniqid - crypto/rand backed random suffixes
+package tsuniqid
+
+import (
+	crand "crypto/rand"
+)
+
+// cryptoBufferSize is the number of crypto/rand bytes fetched per refill
+const cryptoBufferSize = 256
+
+// cryptoRead fills a buffer from the system entropy source; replaced in tests
+var cryptoRead = crand.Read
+
+// cryptoIntn returns a uniformly distributed integer in [0, n) drawn from the
+// buffered crypto/rand bytes, using rejection sampling to avoid modulo bias.
+// The caller must hold mu unless the generator is single-threaded.
+//
+// Parameters:
+//   - n: The exclusive upper bound, at most 256
+//
+// Returns:
+//   - int: A random integer in [0, n)
+//   - bool: False if the entropy source failed
+func (g *IDGenerator) cryptoIntn(n int) (int, bool) {
+	return cryptoIntnFrom(g.cryptoBuf, &g.cryptoPos, n)
+}
+
+// cryptoIntnFrom is cryptoIntn for any crypto/rand buffer and its read
+// position, such as those of a GeneratorPool source.
+//
+// Parameters:
+//   - buf: The buffered crypto/rand bytes, refilled when exhausted
+//   - pos: The next unread position in buf
+//   - n: The exclusive upper bound, at most 256
+//
+// Returns:
+//   - int: A random integer in [0, n)
+//   - bool: False if the entropy source failed
+func cryptoIntnFrom(buf []byte, pos *int, n int) (int, bool) {
+	limit := 256 - 256%n
+
+	for {
+		if *pos >= len(buf) {
+			if _, err := cryptoRead(buf); err != nil {
+				return 0, false
+			}
+			*pos = 0
+		}
+
+		b := int(buf[*pos])
+		*pos++
+
+		if b < limit {
+			return b % n, true
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package tsuniqid
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// TestWithCryptoRandomSuffix tests that crypto/rand suffixes are valid and
+// diverse.
+func TestWithCryptoRandomSuffix(t *testing.T) {
+	gen := NewGenerator(WithCryptoRandomSuffix())
+	suffixes := make(map[string]bool)
+
+	for i := 0; i < 1000; i++ {
+		id := gen.GenerateStringID()
+		suffix := id[len(id)-RandomSuffixLength:]
+
+		for _, c := range suffix {
+			if !strings.ContainsRune(CharSet, c) {
+				t.Errorf("Suffix %s contains invalid character %q", suffix, c)
+			}
+		}
+		suffixes[suffix] = true
+	}
+
+	if len(suffixes) < 990 {
+		t.Errorf("Insufficient suffix variety: got %d unique suffixes out of 1000", len(suffixes))
+	}
+}
+
+// TestWithCryptoRandomSuffix_Fallback tests that a failing entropy source
+// falls back to math/rand instead of failing.
+func TestWithCryptoRandomSuffix_Fallback(t *testing.T) {
+	original := cryptoRead
+	cryptoRead = func(b []byte) (int, error) {
+		return 0, errors.New("entropy source unavailable")
+	}
+	defer func() { cryptoRead = original }()
+
+	gen := NewGenerator(WithCryptoRandomSuffix())
+	id := gen.GenerateStringID()
+
+	suffix := id[len(id)-RandomSuffixLength:]
+	for _, c := range suffix {
+		if !strings.ContainsRune(CharSet, c) {
+			t.Errorf("Fallback suffix %s contains invalid character %q", suffix, c)
+		}
+	}
+}
@@ -0,0 +1,148 @@
+// Package tsuniqid - Decoding uint64 IDs into their components
+package tsuniqid
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// IDComponents holds the decoded fields of a uint64 ID.
+type IDComponents struct {
+	MachineID  uint64    // 4-bit machine identifier
+	InstanceID uint64    // 4-bit instance identifier
+	Timestamp  uint64    // milliseconds since Epoch
+	Counter    uint64    // 14-bit counter value
+	Watermark  uint64    // generation sequence number; only set by IDGenerator.Decode
+	Epoch      time.Time // epoch of the timestamp; the zero value means the Unix epoch
+
+	// Resolution is the unit of Timestamp; zero means time.Millisecond.
+	// Only IDGenerator.Decode sets it, from WithTimestampResolution.
+	Resolution time.Duration
+}
+
+// ParseUint64ID decomposes a uint64 ID into its bit fields.
+//
+// Parameters:
+//   - id: The identifier to decompose
+//
+// Returns:
+//   - machineID: The machine ID (bits 63-60)
+//   - instanceID: The instance ID (bits 59-56)
+//   - timestamp: The timestamp in milliseconds (bits 55-14)
+//   - counter: The counter (bits 13-0)
+func ParseUint64ID(id uint64) (machineID, instanceID, timestamp, counter uint64) {
+	return DefaultLayout.ParseUint64ID(id)
+}
+
+// DecodeUint64 decomposes a uint64 ID into an IDComponents struct, assuming
+// the timestamp is relative to the Unix epoch.
+//
+// Parameters:
+//   - id: The identifier to decompose
+//
+// Returns: The decoded components
+func DecodeUint64(id uint64) IDComponents {
+	machineID, instanceID, timestamp, counter := ParseUint64ID(id)
+	return IDComponents{
+		MachineID:  machineID,
+		InstanceID: instanceID,
+		Timestamp:  timestamp,
+		Counter:    counter,
+	}
+}
+
+// DecodeUint64WithEpoch decomposes a uint64 ID generated with a custom epoch
+// (see WithEpoch) into an IDComponents struct.
+//
+// Parameters:
+//   - id: The identifier to decompose
+//   - epoch: The epoch the generator was configured with
+//
+// Returns: The decoded components
+func DecodeUint64WithEpoch(id uint64, epoch time.Time) IDComponents {
+	components := DecodeUint64(id)
+	components.Epoch = epoch
+	return components
+}
+
+// TimeFromStringID recovers the creation time of a string ID produced by
+// GenerateStringID or GenerateFixedStringID with the default suffix length,
+// assuming the timestamp is relative to the Unix epoch.
+//
+// Parameters:
+//   - s: The string ID
+//
+// Returns:
+//   - time.Time: The time at which the ID was generated, with millisecond precision
+//   - error: An error if s is not a well-formed string ID
+func TimeFromStringID(s string) (time.Time, error) {
+	if len(s) <= RandomSuffixLength || len(s) > HexIDLength+RandomSuffixLength {
+		return time.Time{}, fmt.Errorf("invalid string ID length %d", len(s))
+	}
+
+	hexPart := s[:len(s)-RandomSuffixLength]
+	id, err := strconv.ParseUint(hexPart, 16, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid hex core %q in string ID: %w", hexPart, err)
+	}
+
+	return DecodeUint64(id).Time(), nil
+}
+
+// Decode decomposes a uint64 ID generated by this generator, using the
+// generator's layout and epoch to reconstruct the watermark and absolute time.
+// IDs from a generator created with WithBitInterleaving are deinterleaved
+// first, and those from one created with WithInsertSpreading are unspread.
+//
+// Parameters:
+//   - id: The identifier to decompose
+//
+// Returns: The decoded components
+func (g *IDGenerator) Decode(id uint64) IDComponents {
+	return g.format().decode(id)
+}
+
+// OrdinalInMillisecond returns the position of an ID from this generator
+// within its timestamp (a millisecond by default), which is the value of its
+// counter without the bits taken by WithEnvironment, WithServiceCode and
+// WithParity. It decodes the ID with the generator's layout and format, like
+// Decode.
+//
+// The counter is only an ordinal in the sequenced modes enabled by
+// WithMonotonic and WithOverflowWait, where it restarts at zero each
+// timestamp. Other generators count freely across timestamps, so ok is false
+// for them.
+//
+// Parameters:
+//   - id: The identifier to inspect
+//
+// Returns:
+//   - ordinal: The position of the ID within its timestamp, starting at 0
+//   - ok: False if the generator's counter is not reset each timestamp
+func (g *IDGenerator) OrdinalInMillisecond(id uint64) (ordinal uint64, ok bool) {
+	if !g.monotonic && !g.overflowWait {
+		return 0, false
+	}
+
+	counter := g.Decode(id).Counter
+	if g.parity {
+		counter >>= ParityCounterShift
+	}
+	return counter & g.maxCounter(), true
+}
+
+// Time returns the timestamp component as a time.Time.
+//
+// Returns: The time at which the ID was generated, with the precision of Resolution
+func (c IDComponents) Time() time.Time {
+	unit := c.Resolution
+	if unit == 0 {
+		unit = time.Millisecond
+	}
+
+	if c.Epoch.IsZero() {
+		return time.UnixMicro(int64(c.Timestamp) * int64(unit/time.Microsecond))
+	}
+	return c.Epoch.Add(time.Duration(c.Timestamp) * unit)
+}
@@ -0,0 +1,148 @@
+package tsuniqid
+
+import (
+	"testing"
+	"time"
+)
+
+// TestParseUint64ID tests that hand-built IDs decompose into their fields.
+func TestParseUint64ID(t *testing.T) {
+	const (
+		machine   = 0xa
+		instance  = 0x3
+		timestamp = 1700000000123
+		counter   = 0x1abc
+	)
+	id := uint64(machine)<<MachineIDShift |
+		uint64(instance)<<InstanceIDShift |
+		uint64(timestamp)<<TimestampShift |
+		uint64(counter)
+
+	m, i, ts, c := ParseUint64ID(id)
+	if m != machine || i != instance || ts != timestamp || c != counter {
+		t.Errorf("ParseUint64ID mismatch: got (%d, %d, %d, %d), expected (%d, %d, %d, %d)",
+			m, i, ts, c, machine, instance, timestamp, counter)
+	}
+
+	expected := IDComponents{MachineID: machine, InstanceID: instance, Timestamp: timestamp, Counter: counter}
+	if got := DecodeUint64(id); got != expected {
+		t.Errorf("DecodeUint64 mismatch: got %+v, expected %+v", got, expected)
+	}
+
+	if got := DecodeUint64(id).Time(); !got.Equal(time.UnixMilli(timestamp)) {
+		t.Errorf("Time mismatch: got %v, expected %v", got, time.UnixMilli(timestamp))
+	}
+}
+
+// TestDecodeUint64_GeneratedID tests decoding IDs from a live generator.
+func TestDecodeUint64_GeneratedID(t *testing.T) {
+	gen := NewGenerator(WithMachineID(0x7))
+
+	before := time.Now().Truncate(time.Millisecond)
+	components := DecodeUint64(gen.GenerateUint64ID())
+	after := time.Now()
+
+	if components.MachineID != 0x7 {
+		t.Errorf("Machine ID mismatch: got %d, expected %d", components.MachineID, 0x7)
+	}
+	if components.InstanceID != gen.instanceID {
+		t.Errorf("Instance ID mismatch: got %d, expected %d", components.InstanceID, gen.instanceID)
+	}
+	if ts := components.Time(); ts.Before(before) || ts.After(after) {
+		t.Errorf("Timestamp %v outside [%v, %v]", ts, before, after)
+	}
+}
+
+// TestTimeFromStringID tests that string IDs yield their creation time and
+// that malformed IDs are rejected.
+func TestTimeFromStringID(t *testing.T) {
+	gen := NewGenerator()
+
+	before := time.Now().Truncate(time.Millisecond)
+	ids := []string{gen.GenerateStringID(), gen.GenerateFixedStringID()}
+	after := time.Now()
+
+	for _, id := range ids {
+		ts, err := TimeFromStringID(id)
+		if err != nil {
+			t.Fatalf("Unexpected error for %s: %v", id, err)
+		}
+		if ts.Before(before) || ts.After(after) {
+			t.Errorf("Timestamp %v of %s outside [%v, %v]", ts, id, before, after)
+		}
+	}
+
+	for _, id := range []string{"", "abcdefgh", "xyzabcdefgh", "00000000000000000abcdefgh"} {
+		if _, err := TimeFromStringID(id); err == nil {
+			t.Errorf("Expected error for malformed ID %q", id)
+		}
+	}
+}
+
+// TestWithEpoch tests that custom-epoch IDs store the offset from the epoch
+// and decode back to absolute time.
+func TestWithEpoch(t *testing.T) {
+	epoch := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	gen := NewGenerator(WithEpoch(epoch))
+
+	before := time.Now().Truncate(time.Millisecond)
+	id := gen.GenerateUint64ID()
+	after := time.Now()
+
+	components := gen.Decode(id)
+	if expected := uint64(before.Sub(epoch).Milliseconds()); components.Timestamp < expected {
+		t.Errorf("Timestamp %d not relative to epoch (expected at least %d)", components.Timestamp, expected)
+	}
+	if ts := components.Time(); ts.Before(before) || ts.After(after) {
+		t.Errorf("Decoded time %v outside [%v, %v]", ts, before, after)
+	}
+
+	if got := DecodeUint64WithEpoch(id, epoch); got.Timestamp != components.Timestamp || !got.Time().Equal(components.Time()) {
+		t.Errorf("DecodeUint64WithEpoch mismatch: got %+v, expected %+v", got, components)
+	}
+
+	if _, err := NewGeneratorWithError(WithEpoch(time.Now().Add(time.Hour))); err == nil {
+		t.Errorf("Expected error for future epoch")
+	}
+}
+
+// TestIDGenerator_OrdinalInMillisecond tests that IDs generated within a
+// single millisecond have ordinals increasing from zero, with the counter
+// bits of the generator's layout and options.
+func TestIDGenerator_OrdinalInMillisecond(t *testing.T) {
+	layout := Layout{MachineBits: 4, InstanceBits: 4, TimestampBits: 44, CounterBits: 12}
+
+	testCases := []struct {
+		name   string
+		layout Layout
+		opts   []Option
+	}{
+		{name: "Monotonic", layout: DefaultLayout, opts: []Option{WithMonotonic()}},
+		{name: "OverflowWait", layout: DefaultLayout, opts: []Option{WithOverflowWait(), WithEnvironment(EnvironmentStaging)}},
+		{name: "Parity", layout: DefaultLayout, opts: []Option{WithMonotonic(), WithParity(), WithServiceCode(3)}},
+		{name: "Layout", layout: layout, opts: []Option{WithMonotonic()}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			clock := newFakeClock(time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC), 0)
+			gen, err := NewGeneratorWithLayout(tc.layout, append(tc.opts, WithClock(clock.Now))...)
+			if err != nil {
+				t.Fatalf("NewGeneratorWithLayout failed: %v", err)
+			}
+
+			for i := uint64(0); i < 10; i++ {
+				ordinal, ok := gen.OrdinalInMillisecond(gen.GenerateUint64ID())
+				if !ok || ordinal != i {
+					t.Errorf("Ordinal mismatch: got %d (ok %v), expected %d", ordinal, ok, i)
+				}
+			}
+		})
+	}
+
+	// The free-running counter does not restart each millisecond
+	gen := NewGenerator()
+	if _, ok := gen.OrdinalInMillisecond(gen.GenerateUint64ID()); ok {
+		t.Errorf("Expected no ordinal from a free-running generator")
+	}
+}
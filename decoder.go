@@ -0,0 +1,64 @@
+// Package tsuniqid - Decoding IDs with a known layout and epoch
+package tsuniqid
+
+import (
+	"time"
+)
+
+// Decoder decomposes uint64 IDs generated with a particular layout and
+// epoch, so services that persist IDs can decode them later without the
+// generator. The zero value decodes the default layout relative to the Unix
+// epoch.
+type Decoder struct {
+	format idFormat
+}
+
+// NewDecoder creates a Decoder for IDs generated with layout l and epoch.
+//
+// Parameters:
+//   - l: The bit layout the IDs were generated with
+//   - epoch: The epoch set by WithEpoch; the zero value means the Unix epoch
+//
+// Returns:
+//   - Decoder: The decoder
+//   - error: An error if the layout is invalid
+func NewDecoder(l Layout, epoch time.Time) (Decoder, error) {
+	if err := l.Validate(); err != nil {
+		return Decoder{}, err
+	}
+
+	var ms int64
+	if !epoch.IsZero() {
+		ms = epoch.UnixMilli()
+	}
+	return Decoder{format: idFormat{layout: l, epoch: ms, resolution: time.Millisecond}}, nil
+}
+
+// Decoder returns a Decoder for this generator's IDs. Unlike one built with
+// NewDecoder from Layout, it also carries the generator's bit interleaving,
+// insert spreading, parity and timestamp resolution.
+//
+// Returns: A decoder matching the generator
+func (g *IDGenerator) Decoder() Decoder {
+	return Decoder{format: g.format()}
+}
+
+// Layout returns the bit layout of this generator's uint64 IDs.
+//
+// Returns: The layout, DefaultLayout unless set by NewGeneratorWithLayout
+func (g *IDGenerator) Layout() Layout {
+	return g.layout
+}
+
+// Decode decomposes a uint64 ID into its components.
+//
+// Parameters:
+//   - id: The identifier to decompose
+//
+// Returns: The decoded components
+func (d Decoder) Decode(id uint64) IDComponents {
+	if d.format.layout == (Layout{}) {
+		d.format.layout = DefaultLayout
+	}
+	return d.format.decode(id)
+}
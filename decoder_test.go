@@ -0,0 +1,71 @@
+package tsuniqid
+
+import (
+	"testing"
+	"time"
+)
+
+// TestDecoder tests decoding IDs from a generator with a non-default layout
+// and epoch using only the persisted configuration.
+func TestDecoder(t *testing.T) {
+	epoch := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	layout := Layout{MachineBits: 2, InstanceBits: 6, TimestampBits: 40, CounterBits: 16}
+
+	gen, err := NewGeneratorWithLayout(layout, WithEpoch(epoch), WithMachineID(3), WithInstanceID(42))
+	if err != nil {
+		t.Fatalf("Failed to create generator: %v", err)
+	}
+	if gen.Layout() != layout {
+		t.Errorf("Expected layout %+v, got %+v", layout, gen.Layout())
+	}
+
+	decoder, err := NewDecoder(gen.Layout(), epoch)
+	if err != nil {
+		t.Fatalf("Failed to create decoder: %v", err)
+	}
+
+	before := time.Now().Truncate(time.Millisecond)
+	id := gen.GenerateUint64ID()
+	components := decoder.Decode(id)
+	if components.MachineID != 3 || components.InstanceID != 42 {
+		t.Errorf("Expected machine 3 and instance 42, got %+v", components)
+	}
+	if got := components.Time(); got.Before(before) || got.After(time.Now()) {
+		t.Errorf("Decoded time %v outside generation window", got)
+	}
+	if components != gen.Decode(id) {
+		t.Errorf("Decoder disagrees with generator: %+v vs %+v", components, gen.Decode(id))
+	}
+
+	// The default layout cannot decode these IDs
+	if DecodeUint64(id).InstanceID == 42 {
+		t.Errorf("Default layout unexpectedly decoded the custom instance ID")
+	}
+
+	if _, err := NewDecoder(Layout{MachineBits: 4}, epoch); err == nil {
+		t.Errorf("Expected error for an invalid layout")
+	}
+}
+
+// TestDecoder_ZeroValue tests that the zero Decoder matches DecodeUint64.
+func TestDecoder_ZeroValue(t *testing.T) {
+	id := NewGenerator().GenerateUint64ID()
+
+	var decoder Decoder
+	got, want := decoder.Decode(id), DecodeUint64(id)
+	if got.MachineID != want.MachineID || got.InstanceID != want.InstanceID ||
+		got.Timestamp != want.Timestamp || got.Counter != want.Counter || !got.Time().Equal(want.Time()) {
+		t.Errorf("Zero decoder decoded %+v, expected %+v", got, want)
+	}
+}
+
+// TestIDGenerator_Decoder tests that a generator's decoder undoes its bit
+// interleaving.
+func TestIDGenerator_Decoder(t *testing.T) {
+	gen := NewGenerator(WithBitInterleaving(), WithMachineID(5))
+	id := gen.GenerateUint64ID()
+
+	if got := gen.Decoder().Decode(id); got != gen.Decode(id) || got.MachineID != 5 {
+		t.Errorf("Generator decoder decoded %+v, expected %+v", got, gen.Decode(id))
+	}
+}
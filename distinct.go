@@ -0,0 +1,32 @@
+// Package tsuniqid - Generation of IDs distinct from a known set
+package tsuniqid
+
+import (
+	"fmt"
+)
+
+// DistinctRetryLimit is the maximum number of IDs GenerateDistinctFrom
+// generates before giving up
+const DistinctRetryLimit = 100
+
+// GenerateDistinctFrom creates a unique uint64 identifier that is not in a
+// set of recently used IDs, for idempotency keys in retried operations. IDs
+// that are in the set are discarded and generation continues, advancing the
+// counter and clock, for at most DistinctRetryLimit attempts.
+//
+// Parameters:
+//   - recent: The IDs that must not be returned
+//
+// Returns:
+//   - uint64: A unique identifier not in recent
+//   - error: An error if every attempt produced an ID in recent
+func (g *IDGenerator) GenerateDistinctFrom(recent map[uint64]struct{}) (uint64, error) {
+	for i := 0; i < DistinctRetryLimit; i++ {
+		id := g.GenerateUint64ID()
+		if _, ok := recent[id]; !ok {
+			return id, nil
+		}
+	}
+
+	return 0, fmt.Errorf("no distinct ID found after %d attempts", DistinctRetryLimit)
+}
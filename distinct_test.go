@@ -0,0 +1,44 @@
+package tsuniqid
+
+import (
+	"testing"
+	"time"
+)
+
+// TestIDGenerator_GenerateDistinctFrom tests that an ID already in the recent
+// set is skipped.
+func TestIDGenerator_GenerateDistinctFrom(t *testing.T) {
+	pinned := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	gen := NewGenerator(WithClock(func() time.Time { return pinned }))
+
+	// The counter is incremented before use, so the next ID uses counter 1
+	next := gen.compose(gen.timestamp(), 1)
+	recent := map[uint64]struct{}{next: {}}
+
+	id, err := gen.GenerateDistinctFrom(recent)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if id == next {
+		t.Errorf("Returned ID %d is in the recent set", id)
+	}
+	if _, _, _, counter := ParseUint64ID(id); counter != 2 {
+		t.Errorf("Expected the following counter value 2, got %d", counter)
+	}
+}
+
+// TestIDGenerator_GenerateDistinctFrom_Limit tests that an error is returned
+// when every attempt is in the recent set.
+func TestIDGenerator_GenerateDistinctFrom_Limit(t *testing.T) {
+	pinned := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	gen := NewGenerator(WithClock(func() time.Time { return pinned }))
+
+	recent := make(map[uint64]struct{})
+	for counter := uint64(1); counter <= DistinctRetryLimit; counter++ {
+		recent[gen.compose(gen.timestamp(), counter)] = struct{}{}
+	}
+
+	if id, err := gen.GenerateDistinctFrom(recent); err == nil {
+		t.Errorf("Expected error after %d attempts, got ID %d", DistinctRetryLimit, id)
+	}
+}
@@ -0,0 +1,338 @@
+// Package tsuniqid - Alternative string encodings for uint64 IDs
+package tsuniqid
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+// Encoding constants for alternative string forms
+const (
+	// HexIDLength is the width of zero-padded hex IDs (the number of hex
+	// digits in the maximum uint64 value)
+	HexIDLength = 16
+
+	// DecimalIDLength is the width of zero-padded decimal IDs (the number of
+	// digits in the maximum uint64 value)
+	DecimalIDLength = 20
+
+	// Base62CharSet contains the base62 digits in ascending order
+	Base62CharSet = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+	// Base62IDLength is the width of zero-padded base62 IDs (the number of
+	// digits in the maximum uint64 value)
+	Base62IDLength = 11
+
+	// DefaultSlugGroupSize is the default number of characters per slug group
+	DefaultSlugGroupSize = 3
+
+	// SlugSeparator separates character groups in slugs
+	SlugSeparator = '-'
+)
+
+// GenerateFixedStringID creates a unique string identifier like
+// GenerateStringID, but zero-pads the hex portion to HexIDLength characters
+// so every ID from a generator has the same length: HexIDLength plus the
+// suffix length (RandomSuffixLength by default).
+//
+// Returns: A fixed-length unique string identifier
+func (g *IDGenerator) GenerateFixedStringID() string {
+	id := g.GenerateUint64ID()
+	suffix := g.generateRandomSuffix(g.suffixLength)
+	return g.withCheckChar(formatHexID(id) + suffix)
+}
+
+// GenerateSortableStringID creates a unique string identifier whose string
+// order matches the numeric order of its uint64 core, so IDs can be sorted
+// or range-scanned as strings. It is GenerateFixedStringID under a name that
+// states the guarantee: the zero-padded hex core keeps every ID the same
+// length, and the random suffix only breaks ties between equal cores.
+//
+// The uint64 core sorts by time across milliseconds; use WithMonotonic for
+// a strictly increasing stream from one generator.
+//
+// Returns: A fixed-length, lexicographically sortable string identifier
+func (g *IDGenerator) GenerateSortableStringID() string {
+	return g.GenerateFixedStringID()
+}
+
+// ValidStringID reports whether s has the format produced by
+// GenerateStringID and GenerateFixedStringID with the default suffix length:
+// a hex uint64 of at most HexIDLength digits followed by RandomSuffixLength
+// characters from CharSet. It only checks the format, not whether the ID was
+// actually generated.
+//
+// IDs from GenerateStringIDWithPrefix are accepted too: a non-empty prefix
+// and PrefixSeparator are stripped and the rest is checked as above.
+//
+// Parameters:
+//   - s: The string to check
+//
+// Returns: True if s is a well-formed string ID
+func ValidStringID(s string) bool {
+	if prefix, id := StripPrefix(s); id != s {
+		if prefix == "" {
+			return false
+		}
+		s = id
+	}
+
+	if len(s) <= RandomSuffixLength || len(s) > HexIDLength+RandomSuffixLength {
+		return false
+	}
+
+	hexPart, suffix := s[:len(s)-RandomSuffixLength], s[len(s)-RandomSuffixLength:]
+	if _, err := strconv.ParseUint(hexPart, 16, 64); err != nil {
+		return false
+	}
+
+	for i := 0; i < len(suffix); i++ {
+		if strings.IndexByte(CharSet, suffix[i]) < 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// GenerateDecimalID creates a unique uint64 identifier encoded as a decimal
+// string zero-padded to DecimalIDLength characters, suitable for fixed-column
+// flat-file formats.
+//
+// Returns: A 20-character decimal identifier
+func (g *IDGenerator) GenerateDecimalID() string {
+	return formatDecimalID(g.GenerateUint64ID())
+}
+
+// ParseDecimalID parses a decimal ID back into its uint64 value.
+// Leading zero padding is accepted, so both padded and unpadded forms parse.
+//
+// Parameters:
+//   - s: The decimal ID string
+//
+// Returns:
+//   - uint64: The decoded identifier
+//   - error: An error if the string is not a valid decimal ID
+func ParseDecimalID(s string) (uint64, error) {
+	if s == "" || len(s) > DecimalIDLength {
+		return 0, fmt.Errorf("invalid decimal ID length %d", len(s))
+	}
+
+	id, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid decimal ID %q: %w", s, err)
+	}
+
+	return id, nil
+}
+
+// formatHexID encodes an ID as a zero-padded hex string.
+//
+// Parameters:
+//   - id: The identifier to encode
+//
+// Returns: The hex string padded to HexIDLength characters
+func formatHexID(id uint64) string {
+	s := strconv.FormatUint(id, 16)
+	return strings.Repeat("0", HexIDLength-len(s)) + s
+}
+
+// formatDecimalID encodes an ID as a zero-padded decimal string.
+//
+// Parameters:
+//   - id: The identifier to encode
+//
+// Returns: The decimal string padded to DecimalIDLength characters
+func formatDecimalID(id uint64) string {
+	s := strconv.FormatUint(id, 10)
+	return strings.Repeat("0", DecimalIDLength-len(s)) + s
+}
+
+// GenerateBase62ID creates a unique, URL-safe string identifier.
+// Format: base62(uint64_id) + random_suffix
+//
+// The base62 core uses [0-9A-Za-z] and is zero-padded to Base62IDLength
+// characters, making it shorter than the hex form of GenerateStringID.
+//
+// Returns: A unique base62 string identifier
+func (g *IDGenerator) GenerateBase62ID() string {
+	id := g.GenerateUint64ID()
+	suffix := g.generateRandomSuffix(g.suffixLength)
+	return encodeBase62(id) + suffix
+}
+
+// DecodeBase62 recovers the uint64 ID from a string created by
+// GenerateBase62ID, ignoring the random suffix, whatever its length. The result can be passed to
+// DecodeUint64 to recover the embedded timestamp.
+//
+// Parameters:
+//   - s: The base62 string identifier
+//
+// Returns:
+//   - uint64: The decoded identifier
+//   - error: An error if the string is not a valid base62 ID
+func DecodeBase62(s string) (uint64, error) {
+	if len(s) < Base62IDLength {
+		return 0, fmt.Errorf("invalid base62 ID length %d", len(s))
+	}
+	return decodeBase62(s[:Base62IDLength])
+}
+
+// GenerateSlug creates a unique, human-readable identifier for user-facing
+// short links. The uint64 ID is encoded in base62 and split into hyphenated
+// groups whose size is set by WithSlugGroupSize, e.g. "0Ab-3xY-9fk-2l".
+//
+// Returns: A unique slug
+func (g *IDGenerator) GenerateSlug() string {
+	return groupSlug(encodeBase62(g.GenerateUint64ID()), g.slugGroupSize)
+}
+
+// ParseSlug strips the group separators from a slug and decodes the base62
+// ID it contains.
+//
+// Parameters:
+//   - slug: The slug to parse
+//
+// Returns:
+//   - uint64: The decoded identifier
+//   - error: An error if the slug is not valid base62
+func ParseSlug(slug string) (uint64, error) {
+	return decodeBase62(strings.ReplaceAll(slug, string(SlugSeparator), ""))
+}
+
+// groupSlug inserts SlugSeparator between groups of size characters.
+//
+// Parameters:
+//   - s: The string to group
+//   - size: The number of characters per group
+//
+// Returns: The grouped string
+func groupSlug(s string, size int) string {
+	if size <= 0 || size >= len(s) {
+		return s
+	}
+
+	var b strings.Builder
+	b.Grow(len(s) + len(s)/size)
+	for i := 0; i < len(s); i += size {
+		if i > 0 {
+			b.WriteByte(SlugSeparator)
+		}
+		end := i + size
+		if end > len(s) {
+			end = len(s)
+		}
+		b.WriteString(s[i:end])
+	}
+
+	return b.String()
+}
+
+// EstimateStorage approximates the total number of bytes needed to store
+// count IDs from this generator in each supported encoding.
+//
+// Sizes are the longest string each encoding produces with this generator's
+// settings: "hex" is GenerateStringID, whose unpadded core is bounded by the
+// generator's machine and instance IDs, "base62" is GenerateBase62ID,
+// "base32" is GenerateCrockfordID and "decimal" is GenerateDecimalID, each
+// with its random suffix of the configured length where the encoding has
+// one. The check character of WithCheckedSuffix replaces a suffix character,
+// so it adds nothing. "binary" is the 8-byte uint64. Prefixes passed to
+// GenerateStringIDWithPrefix and any delimiters are not included.
+//
+// Parameters:
+//   - count: The number of IDs to store
+//
+// Returns: A map from encoding name to the estimated total size in bytes
+func (g *IDGenerator) EstimateStorage(count int) map[string]int {
+	if count < 0 {
+		count = 0
+	}
+
+	return map[string]int{
+		"hex":     count * (encodedLength(g.maxID(), 16) + g.suffixLength),
+		"base62":  count * (Base62IDLength + g.suffixLength),
+		"base32":  count * (CrockfordIDLength + g.suffixLength),
+		"decimal": count * DecimalIDLength,
+		"binary":  count * 8,
+	}
+}
+
+// maxID returns an upper bound on the uint64 IDs of this generator: its
+// identity bits with every lower bit set.
+//
+// Returns: The largest ID the generator could produce
+func (g *IDGenerator) maxID() uint64 {
+	// Mixed and reordered IDs may set any of the 64 bits
+	if g.interleave || g.spreadBits > 0 || g.snowflake {
+		return math.MaxUint64
+	}
+	g.ensureIdentity()
+	return atomic.LoadUint64(&g.prefix) | bitMask(g.layout.instanceShift())
+}
+
+// encodedLength returns the number of digits needed to write a value in
+// the given base.
+//
+// Parameters:
+//   - value: The value to encode
+//   - base: The numeric base of the encoding
+//
+// Returns: The number of digits in the encoded value
+func encodedLength(value uint64, base uint64) int {
+	length := 1
+	for value >= base {
+		value /= base
+		length++
+	}
+	return length
+}
+
+// encodeBase62 encodes an ID in base62, zero-padded to Base62IDLength
+// characters so that leading zeros round-trip.
+//
+// Parameters:
+//   - id: The identifier to encode
+//
+// Returns: The base62 string
+func encodeBase62(id uint64) string {
+	result := make([]byte, Base62IDLength)
+	for i := Base62IDLength - 1; i >= 0; i-- {
+		result[i] = Base62CharSet[id%62]
+		id /= 62
+	}
+	return string(result)
+}
+
+// decodeBase62 decodes a base62 string produced by encodeBase62. Shorter,
+// unpadded strings are also accepted.
+//
+// Parameters:
+//   - s: The base62 string
+//
+// Returns:
+//   - uint64: The decoded identifier
+//   - error: An error if the string is empty, contains invalid characters or overflows uint64
+func decodeBase62(s string) (uint64, error) {
+	if s == "" || len(s) > Base62IDLength {
+		return 0, fmt.Errorf("invalid base62 ID length %d", len(s))
+	}
+
+	var id uint64
+	for i := 0; i < len(s); i++ {
+		digit := strings.IndexByte(Base62CharSet, s[i])
+		if digit < 0 {
+			return 0, fmt.Errorf("invalid base62 character %q in %q", s[i], s)
+		}
+
+		if id > (math.MaxUint64-uint64(digit))/62 {
+			return 0, fmt.Errorf("base62 ID %q overflows uint64", s)
+		}
+		id = id*62 + uint64(digit)
+	}
+
+	return id, nil
+}
@@ -0,0 +1,300 @@
+package tsuniqid
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestIDGenerator_GenerateFixedStringID tests that fixed string IDs always
+// have the same length and a valid hex portion.
+func TestIDGenerator_GenerateFixedStringID(t *testing.T) {
+	gen := NewGenerator()
+
+	for i := 0; i < 10000; i++ {
+		id := gen.GenerateFixedStringID()
+
+		if len(id) != HexIDLength+RandomSuffixLength {
+			t.Fatalf("Fixed string ID has wrong length: %s (length: %d)", id, len(id))
+		}
+
+		if _, err := strconv.ParseUint(id[:HexIDLength], 16, 64); err != nil {
+			t.Errorf("Invalid hex part in ID %s: %v", id, err)
+		}
+	}
+
+	// Small values are padded rather than shortened
+	if s := formatHexID(0xabc); s != "0000000000000abc" {
+		t.Errorf("Unexpected padding: %s", s)
+	}
+}
+
+// TestIDGenerator_GenerateDecimalID tests that decimal IDs are always
+// TestIDGenerator_GenerateSortableStringID tests that sorting IDs as strings
+// orders them by their uint64 values.
+func TestIDGenerator_GenerateSortableStringID(t *testing.T) {
+	// Different machine IDs make the cores span the full range of hex lengths
+	generators := []*IDGenerator{NewGenerator(WithMachineID(0)), NewGenerator(WithMachineID(0xf))}
+
+	ids := make([]string, 1000)
+	for i := range ids {
+		ids[i] = generators[i%2].GenerateSortableStringID()
+	}
+	sort.Strings(ids)
+
+	var prev uint64
+	for i, id := range ids {
+		value, err := strconv.ParseUint(id[:HexIDLength], 16, 64)
+		if err != nil {
+			t.Fatalf("Invalid hex core in %s: %v", id, err)
+		}
+		if i > 0 && value < prev {
+			t.Errorf("String order differs from numeric order at %d: %x < %x", i, value, prev)
+		}
+		prev = value
+	}
+}
+
+// TestValidStringID tests format validation of string IDs.
+func TestValidStringID(t *testing.T) {
+	gen := NewGenerator()
+	for i := 0; i < 100; i++ {
+		for _, id := range []string{gen.GenerateStringID(), gen.GenerateFixedStringID()} {
+			if !ValidStringID(id) {
+				t.Errorf("Generated ID reported invalid: %s", id)
+			}
+		}
+	}
+
+	testCases := []struct {
+		name     string
+		id       string
+		expected bool
+	}{
+		{name: "Minimal", id: "0abcdefgh", expected: true},
+		{name: "Empty", id: "", expected: false},
+		{name: "Suffix only", id: "abcdefgh", expected: false},
+		{name: "Too long", id: "00000000000000000abcdefgh", expected: false},
+		{name: "Invalid hex", id: "xyzabcdefgh", expected: false},
+		{name: "Signed hex", id: "+1abcdefgh", expected: false},
+		{name: "Invalid suffix", id: "1abc-efgh", expected: false},
+		{name: "Prefixed", id: "cus_0abcdefgh", expected: true},
+		{name: "Empty prefix", id: "_0abcdefgh", expected: false},
+		{name: "Prefix only", id: "cus_", expected: false},
+		{name: "Invalid core", id: "cus_xyzabcdefgh", expected: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := ValidStringID(tc.id); got != tc.expected {
+				t.Errorf("ValidStringID(%q) = %v, expected %v", tc.id, got, tc.expected)
+			}
+		})
+	}
+}
+
+// padded to a fixed width and parse back to the original value.
+func TestIDGenerator_GenerateDecimalID(t *testing.T) {
+	gen := NewGenerator()
+
+	for i := 0; i < 1000; i++ {
+		id := gen.GenerateDecimalID()
+
+		if len(id) != DecimalIDLength {
+			t.Errorf("Decimal ID has wrong length: %s (length: %d)", id, len(id))
+		}
+
+		if _, err := ParseDecimalID(id); err != nil {
+			t.Errorf("Failed to parse decimal ID %s: %v", id, err)
+		}
+	}
+
+	// Small and maximal values must round-trip through the padding
+	for _, want := range []uint64{0, 1, 42, math.MaxUint64} {
+		s := formatDecimalID(want)
+		if len(s) != DecimalIDLength {
+			t.Errorf("Decimal ID for %d has wrong length: %s", want, s)
+		}
+
+		got, err := ParseDecimalID(s)
+		if err != nil {
+			t.Errorf("Failed to parse decimal ID %s: %v", s, err)
+		} else if got != want {
+			t.Errorf("Decimal ID round-trip mismatch: got %d, expected %d", got, want)
+		}
+	}
+}
+
+// TestParseDecimalID_Invalid tests that malformed decimal IDs are rejected.
+func TestParseDecimalID_Invalid(t *testing.T) {
+	for _, s := range []string{"", "abc", "-1", "000000000000000000001", "18446744073709551616"} {
+		if _, err := ParseDecimalID(s); err == nil {
+			t.Errorf("Expected error for invalid decimal ID %q", s)
+		}
+	}
+}
+
+// TestIDGenerator_EstimateStorage tests the storage estimates for each
+// encoding against the generator's settings and its actual string IDs.
+func TestIDGenerator_EstimateStorage(t *testing.T) {
+	const count = 1000
+
+	testCases := []struct {
+		name     string
+		opts     []Option
+		expected map[string]int
+	}{
+		{
+			name: "Default suffix",
+			opts: []Option{WithMachineID(0xA)},
+			expected: map[string]int{
+				"hex":     (16 + RandomSuffixLength) * count,
+				"base62":  (11 + RandomSuffixLength) * count,
+				"base32":  (13 + RandomSuffixLength) * count,
+				"decimal": DecimalIDLength * count,
+				"binary":  8 * count,
+			},
+		},
+		{
+			// A zero machine ID leaves the top hex digit unused
+			name: "Short core",
+			opts: []Option{WithMachineID(0), WithInstanceID(1), WithSuffixLength(12), WithCheckedSuffix()},
+			expected: map[string]int{
+				"hex":     (15 + 12) * count,
+				"base62":  (11 + 12) * count,
+				"base32":  (13 + 12) * count,
+				"decimal": DecimalIDLength * count,
+				"binary":  8 * count,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			gen := NewGenerator(tc.opts...)
+			estimates := gen.EstimateStorage(count)
+
+			for encoding, want := range tc.expected {
+				if got := estimates[encoding]; got != want {
+					t.Errorf("Estimate for %s: got %d, expected %d", encoding, got, want)
+				}
+			}
+
+			// Generated IDs must never exceed the estimated per-ID size
+			for i := 0; i < 100; i++ {
+				for encoding, id := range map[string]string{
+					"hex":     gen.GenerateStringID(),
+					"base62":  gen.GenerateBase62ID(),
+					"base32":  gen.GenerateCrockfordID(),
+					"decimal": gen.GenerateDecimalID(),
+				} {
+					if len(id)*count > estimates[encoding] {
+						t.Errorf("%s ID %s exceeds estimate %d", encoding, id, estimates[encoding]/count)
+					}
+				}
+			}
+		})
+	}
+}
+
+// TestIDGenerator_GenerateBase62ID tests that base62 IDs are unique, use
+// only base62 characters and decode back to a valid uint64 ID.
+func TestIDGenerator_GenerateBase62ID(t *testing.T) {
+	gen := NewGenerator()
+	seen := make(map[string]bool)
+
+	before := time.Now().Truncate(time.Millisecond)
+	for i := 0; i < 1000; i++ {
+		id := gen.GenerateBase62ID()
+
+		if seen[id] {
+			t.Errorf("Duplicate ID found: %s", id)
+		}
+		seen[id] = true
+
+		if len(id) != Base62IDLength+RandomSuffixLength {
+			t.Errorf("Base62 ID has wrong length: %s (length: %d)", id, len(id))
+		}
+		for _, c := range id {
+			if !strings.ContainsRune(Base62CharSet, c) {
+				t.Errorf("Base62 ID %s contains invalid character %q", id, c)
+			}
+		}
+
+		decoded, err := DecodeBase62(id)
+		if err != nil {
+			t.Errorf("Failed to decode %s: %v", id, err)
+			continue
+		}
+		if ts := DecodeUint64(decoded).Time(); ts.Before(before) || ts.After(time.Now()) {
+			t.Errorf("Decoded timestamp %v out of range", ts)
+		}
+	}
+
+	if id := UniqBase62(); len(id) != Base62IDLength+RandomSuffixLength {
+		t.Errorf("UniqBase62 returned wrong length: %s", id)
+	}
+
+	if _, err := DecodeBase62("short"); err == nil {
+		t.Errorf("Expected error decoding short string")
+	}
+}
+
+// TestIDGenerator_GenerateSlug tests that slugs are grouped correctly and
+// parse back to the original uint64.
+func TestIDGenerator_GenerateSlug(t *testing.T) {
+	for _, size := range []int{1, DefaultSlugGroupSize, 4, Base62IDLength} {
+		gen := NewGenerator(WithSlugGroupSize(size))
+
+		for i := 0; i < 100; i++ {
+			slug := gen.GenerateSlug()
+
+			groups := strings.Split(slug, string(SlugSeparator))
+			for j, group := range groups {
+				last := j == len(groups)-1
+				if (!last && len(group) != size) || (last && (len(group) == 0 || len(group) > size)) {
+					t.Errorf("Slug %s has bad group %q for size %d", slug, group, size)
+				}
+			}
+
+			id, err := ParseSlug(slug)
+			if err != nil {
+				t.Errorf("Failed to parse slug %s: %v", slug, err)
+				continue
+			}
+			if got := groupSlug(encodeBase62(id), size); got != slug {
+				t.Errorf("Slug round-trip mismatch: got %s, expected %s", got, slug)
+			}
+		}
+	}
+
+	if _, err := NewGeneratorWithError(WithSlugGroupSize(0)); err == nil {
+		t.Errorf("Expected error for zero slug group size")
+	}
+}
+
+// TestBase62_RoundTrip tests base62 encoding of boundary values.
+func TestBase62_RoundTrip(t *testing.T) {
+	for _, want := range []uint64{0, 1, 61, 62, math.MaxUint64} {
+		s := encodeBase62(want)
+		if len(s) != Base62IDLength {
+			t.Errorf("Base62 for %d has wrong length: %s", want, s)
+		}
+
+		got, err := decodeBase62(s)
+		if err != nil {
+			t.Errorf("Failed to decode %s: %v", s, err)
+		} else if got != want {
+			t.Errorf("Base62 round-trip mismatch: got %d, expected %d", got, want)
+		}
+	}
+
+	for _, s := range []string{"", "abc-", "zzzzzzzzzzz", "000000000000"} {
+		if _, err := decodeBase62(s); err == nil {
+			t.Errorf("Expected error decoding %q", s)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+// Package tsuniqid - Environment tags embedded in uint64 IDs
+package tsuniqid
+
+// Environment identifies the deployment environment an ID was generated in.
+type Environment uint64
+
+// Environment tags for WithEnvironment. The zero value means untagged.
+const (
+	// EnvironmentProduction tags IDs generated in production
+	EnvironmentProduction Environment = 1
+
+	// EnvironmentStaging tags IDs generated in staging
+	EnvironmentStaging Environment = 2
+
+	// EnvironmentDevelopment tags IDs generated in development
+	EnvironmentDevelopment Environment = 3
+)
+
+// Bit layout constants for IDs generated with WithEnvironment.
+//
+// The regular layout is kept, except that the counter field is split:
+// - Bits 13-12 (2 bits): Environment tag
+// - Bits 11-0 (12 bits): Counter
+//
+// With a custom Layout, the tag occupies the top 2 bits of the counter field.
+const (
+	// MaxEnvironment represents the maximum environment tag value (2 bits)
+	MaxEnvironment = 0x3
+
+	// MaxEnvironmentCounter represents the maximum counter value with an environment tag (12 bits)
+	MaxEnvironmentCounter = 0xfff
+
+	// EnvironmentShift is the number of bits to shift the environment tag
+	EnvironmentShift = 12
+
+	// environmentBits is the width of the environment tag
+	environmentBits = 2
+)
+
+// String returns the name of the environment.
+//
+// Returns: "production", "staging", "development" or "unknown"
+func (e Environment) String() string {
+	switch e {
+	case EnvironmentProduction:
+		return "production"
+	case EnvironmentStaging:
+		return "staging"
+	case EnvironmentDevelopment:
+		return "development"
+	default:
+		return "unknown"
+	}
+}
+
+// EnvironmentOf extracts the environment tag from an ID generated with
+// WithEnvironment. The result is meaningless for untagged IDs.
+//
+// Parameters:
+//   - id: The identifier to inspect
+//
+// Returns: The environment tag of the ID
+func EnvironmentOf(id uint64) Environment {
+	return Environment((id >> EnvironmentShift) & MaxEnvironment)
+}
@@ -0,0 +1,55 @@
+package tsuniqid
+
+import (
+	"testing"
+)
+
+// TestWithEnvironment tests that IDs carry the environment they were
+// generated in and that IDs from different environments differ in the tag.
+func TestWithEnvironment(t *testing.T) {
+	environments := []Environment{EnvironmentProduction, EnvironmentStaging, EnvironmentDevelopment}
+
+	generators := make(map[Environment]*IDGenerator)
+	for _, env := range environments {
+		generators[env] = NewGenerator(WithMachineID(0x1), WithEnvironment(env))
+	}
+
+	seen := make(map[uint64]Environment)
+	for i := 0; i < 1000; i++ {
+		for _, env := range environments {
+			id := generators[env].GenerateUint64ID()
+
+			if got := EnvironmentOf(id); got != env {
+				t.Errorf("Environment mismatch for %x: got %s, expected %s", id, got, env)
+			}
+
+			if other, ok := seen[id]; ok {
+				t.Errorf("ID %x generated in both %s and %s", id, other, env)
+			}
+			seen[id] = env
+		}
+	}
+
+	for _, env := range []Environment{0, MaxEnvironment + 1} {
+		if _, err := NewGeneratorWithError(WithEnvironment(env)); err == nil {
+			t.Errorf("Expected error for invalid environment %d", env)
+		}
+	}
+}
+
+// TestWithEnvironment_Parity tests that the environment tag and parity bit
+// can be combined.
+func TestWithEnvironment_Parity(t *testing.T) {
+	gen := NewGenerator(WithEnvironment(EnvironmentStaging), WithParity())
+
+	for i := 0; i < 100; i++ {
+		id := gen.GenerateUint64ID()
+
+		if EnvironmentOf(id) != EnvironmentStaging {
+			t.Errorf("Environment mismatch for %x: got %s", id, EnvironmentOf(id))
+		}
+		if !CheckParity(id) {
+			t.Errorf("Generated ID %x fails parity check", id)
+		}
+	}
+}
@@ -2,7 +2,7 @@ package main
 
 import (
 	"fmt"
-	"strconv"
+	"sort"
 	"sync"
 	"time"
 
@@ -104,7 +104,7 @@ func multipleGenerators() {
 	fmt.Printf("   创建 %d 个独立的生成器实例:\n", numGenerators)
 	for i := 0; i < numGenerators; i++ {
 		generators[i] = tsuniqid.NewGenerator()
-		fmt.Printf("     生成器 %d: 创建成功\n", i+1)
+		fmt.Printf("     生成器 %d: %s\n", i+1, generators[i])
 	}
 
 	fmt.Println()
@@ -119,6 +119,19 @@ func multipleGenerators() {
 		}
 		fmt.Println()
 	}
+
+	// 原始 uint64 比较先按机器 ID 排序，CompareByTime 按生成时间排序
+	var ids []uint64
+	for _, gen := range generators {
+		ids = append(ids, gen.GenerateUint64ID())
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		return tsuniqid.CompareByTime(ids[i], ids[j]) < 0
+	})
+	fmt.Println("   按时间排序的 ID (tsuniqid.CompareByTime):")
+	for _, id := range ids {
+		fmt.Printf("     0x%016x\n", id)
+	}
 }
 
 // bitLayoutAnalysis 分析 uint64 ID 的位布局
@@ -135,11 +148,8 @@ func bitLayoutAnalysis() {
 	for i := 0; i < 5; i++ {
 		id := generator.GenerateUint64ID()
 
-		// 根据 uniqid.go 中的位布局提取组件
-		machineID := (id >> 60) & 0xF           // 前4位
-		instanceID := (id >> 56) & 0xF          // 接下来4位
-		timestamp := (id >> 14) & 0x3FFFFFFFFFF // 接下来42位
-		counter := id & 0x3FFF                  // 最后14位
+		// 使用 ParseUint64ID 提取各组件
+		machineID, instanceID, timestamp, counter := tsuniqid.ParseUint64ID(id)
 
 		fmt.Printf("   ID %d: %d (0x%016x)\n", i+1, id, id)
 		fmt.Printf("     机器ID:  %d (二进制: %04b)\n", machineID, machineID)
@@ -267,19 +277,11 @@ func idFormatValidation() {
 		fmt.Printf("     ID: %s\n", id)
 		fmt.Printf("       长度: %d 字符\n", len(id))
 
-		// 验证前缀是否为有效的十六进制
-		suffixLength := 8 // RandomSuffixLength 常量值
-		if len(id) >= suffixLength {
-			hexPart := id[:len(id)-suffixLength]
-			suffix := id[len(id)-suffixLength:]
-
-			if _, err := strconv.ParseUint(hexPart, 16, 64); err != nil {
-				fmt.Printf("       ❌ 十六进制部分无效: %s\n", hexPart)
-			} else {
-				fmt.Printf("       ✅ 十六进制部分有效: %s\n", hexPart)
-			}
-
-			fmt.Printf("       随机后缀: %s\n", suffix)
+		// 验证格式: 十六进制前缀 + 随机后缀
+		if tsuniqid.ValidStringID(id) {
+			fmt.Printf("       ✅ 格式有效\n")
+		} else {
+			fmt.Printf("       ❌ 格式无效\n")
 		}
 		fmt.Println()
 	}
@@ -290,10 +292,11 @@ func idFormatValidation() {
 
 	for i := 0; i < 5; i++ {
 		id := generator.GenerateUint64ID()
-		timestamp := (id >> 14) & 0x3FFFFFFFFFF
+		components := tsuniqid.DecodeUint64(id)
+		timestamp := components.Timestamp
 
 		timeDiff := int64(timestamp) - now
-		timeObj := time.UnixMilli(int64(timestamp))
+		timeObj := components.Time()
 
 		fmt.Printf("     ID: %d\n", id)
 		fmt.Printf("       时间戳: %d\n", timestamp)
@@ -0,0 +1,53 @@
+// Package tsuniqid - Cross-platform filename safety for string IDs
+package tsuniqid
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Filename safety constants for WithFilenameSafe
+const (
+	// FilenameSafeCharSet contains the characters allowed in filename-safe
+	// string IDs. It excludes path separators, characters reserved on
+	// Windows, and dots and spaces, which Windows strips from filename ends.
+	FilenameSafeCharSet = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz_-"
+
+	// MaxFilenameLength is the longest filename accepted by all major file
+	// systems, in bytes
+	MaxFilenameLength = 254
+)
+
+// checkFilenameSafe verifies that strings drawn from a character set and
+// up to a maximum length are valid filenames on all major platforms.
+//
+// Because Windows and macOS file systems are case-insensitive by default,
+// the character set must not contain both cases of the same letter;
+// otherwise two distinct IDs could name the same file.
+//
+// Parameters:
+//   - charset: The characters the strings are drawn from
+//   - maxLength: The maximum length of the strings
+//
+// Returns: An error describing why the strings are not filename-safe
+func checkFilenameSafe(charset string, maxLength int) error {
+	if maxLength > MaxFilenameLength {
+		return fmt.Errorf("string IDs of length %d exceed the filename limit of %d", maxLength, MaxFilenameLength)
+	}
+
+	seen := make(map[rune]bool)
+	for _, c := range charset {
+		if !strings.ContainsRune(FilenameSafeCharSet, c) {
+			return fmt.Errorf("character %q is not filename-safe", c)
+		}
+
+		folded := unicode.ToLower(c)
+		if seen[folded] {
+			return fmt.Errorf("character %q collides with another character on case-insensitive file systems", c)
+		}
+		seen[folded] = true
+	}
+
+	return nil
+}
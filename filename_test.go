@@ -0,0 +1,75 @@
+package tsuniqid
+
+import (
+	"strings"
+	"testing"
+)
+
+// isValidFilename reports whether a name is valid on Windows, macOS and Linux.
+func isValidFilename(name string) bool {
+	if name == "" || len(name) > 255 || name == "." || name == ".." {
+		return false
+	}
+	if strings.ContainsAny(name, `<>:"/\|?*`) || strings.HasSuffix(name, ".") || strings.HasSuffix(name, " ") {
+		return false
+	}
+	for _, c := range name {
+		if c < 0x20 {
+			return false
+		}
+	}
+
+	// Windows reserves device names regardless of extension
+	base := strings.ToUpper(strings.SplitN(name, ".", 2)[0])
+	switch base {
+	case "CON", "PRN", "AUX", "NUL":
+		return false
+	}
+	if len(base) == 4 && (strings.HasPrefix(base, "COM") || strings.HasPrefix(base, "LPT")) && base[3] >= '1' && base[3] <= '9' {
+		return false
+	}
+
+	return true
+}
+
+// TestWithFilenameSafe tests that filename-safe string IDs pass a
+// cross-platform filename validity check.
+func TestWithFilenameSafe(t *testing.T) {
+	gen, err := NewGeneratorWithError(WithFilenameSafe())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	for i := 0; i < 1000; i++ {
+		for _, id := range []string{gen.GenerateStringID(), gen.GenerateFixedStringID()} {
+			if !isValidFilename(id) {
+				t.Errorf("ID %s is not a valid filename", id)
+			}
+		}
+	}
+}
+
+// TestCheckFilenameSafe tests rejection of unsafe character sets and lengths.
+func TestCheckFilenameSafe(t *testing.T) {
+	testCases := []struct {
+		name      string
+		charset   string
+		maxLength int
+		wantErr   bool
+	}{
+		{name: "Default charset", charset: CharSet, maxLength: 24, wantErr: false},
+		{name: "Path separator", charset: "abc/", maxLength: 24, wantErr: true},
+		{name: "Dot", charset: "abc.", maxLength: 24, wantErr: true},
+		{name: "Mixed case", charset: "abcABC", maxLength: 24, wantErr: true},
+		{name: "Too long", charset: CharSet, maxLength: 300, wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := checkFilenameSafe(tc.charset, tc.maxLength)
+			if (err != nil) != tc.wantErr {
+				t.Errorf("Got error %v, wantErr %v", err, tc.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,189 @@
+// Package tsuniqid - ID type with text serialization
+package tsuniqid
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ID is a uint64 identifier that serializes as a string: a quoted decimal
+// in JSON and a hex string in text formats such as YAML.
+//
+// Encoding IDs as strings keeps them intact in JSON and YAML consumers that
+// store numbers as 64-bit floats, such as JavaScript, which would otherwise
+// lose precision above 2^53.
+//
+// ID also implements driver.Valuer and sql.Scanner. It is stored as a signed
+// 64-bit integer, which fits BIGINT columns compactly and indexes well, but
+// IDs with the top machine bit set are stored as negative numbers and so do
+// not sort chronologically in SQL. TEXT columns holding the hex form of
+// MarshalText avoid that at the cost of larger rows; Scan accepts both, as
+// well as BIGINT values returned as decimal text.
+type ID uint64
+
+// GenerateID creates a unique identifier as a TypedID, which can be decoded
+// and compared without the generator.
+//
+// Returns: A unique ID carrying the generator's format
+func (g *IDGenerator) GenerateID() TypedID {
+	return TypedID{ID: ID(g.GenerateUint64ID()), format: g.sharedFormat}
+}
+
+// String returns the ID as a zero-padded 16-character hex string.
+//
+// Returns: The hex form of the ID
+func (id ID) String() string {
+	return formatHexID(uint64(id))
+}
+
+// MarshalText implements encoding.TextMarshaler.
+//
+// Returns:
+//   - []byte: The ID as a zero-padded 16-character hex string
+//   - error: Always nil
+func (id ID) MarshalText() ([]byte, error) {
+	return []byte(formatHexID(uint64(id))), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+//
+// Parameters:
+//   - text: A hex string of at most 16 characters
+//
+// Returns: An error if the text is not a valid hex ID
+func (id *ID) UnmarshalText(text []byte) error {
+	if len(text) == 0 || len(text) > HexIDLength {
+		return fmt.Errorf("invalid hex ID length %d", len(text))
+	}
+
+	value, err := strconv.ParseUint(string(text), 16, 64)
+	if err != nil {
+		return fmt.Errorf("invalid hex ID %q: %w", text, err)
+	}
+
+	*id = ID(value)
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler.
+//
+// Returns:
+//   - []byte: The ID as a quoted decimal string
+//   - error: Always nil
+func (id ID) MarshalJSON() ([]byte, error) {
+	return strconv.AppendQuote(nil, strconv.FormatUint(uint64(id), 10)), nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler. It accepts quoted decimal
+// strings as well as bare numbers, and leaves the ID unchanged for null.
+//
+// Parameters:
+//   - data: The JSON value
+//
+// Returns: An error if the value is not a valid decimal ID
+func (id *ID) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+
+	text := string(data)
+	if len(text) >= 2 && text[0] == '"' && text[len(text)-1] == '"' {
+		text = text[1 : len(text)-1]
+	}
+
+	value, err := strconv.ParseUint(text, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid JSON ID %s: %w", data, err)
+	}
+
+	*id = ID(value)
+	return nil
+}
+
+// Value implements driver.Valuer, storing the ID as an int64 with the same
+// bit pattern.
+//
+// Returns:
+//   - driver.Value: The ID as an int64
+//   - error: Always nil
+func (id ID) Value() (driver.Value, error) {
+	return int64(id), nil
+}
+
+// Scan implements sql.Scanner. It accepts int64 values from BIGINT columns,
+// strings or bytes from TEXT columns, and NULL, which yields 0.
+//
+// Text of exactly HexIDLength characters is read as the zero-padded
+// lowercase hex form written by MarshalText, so IDs stored as TEXT scan back
+// unchanged. Other text is read as a decimal BIGINT, as drivers using a text
+// protocol (such as MySQL's, or pgx in some modes) return those columns;
+// negative numbers give the ID with the same bit pattern, as for int64.
+// Decimal text of exactly 16 digits would be read as hex, so BIGINT columns
+// holding IDs below 10^16 should be scanned as int64.
+//
+// Parameters:
+//   - src: The database value
+//
+// Returns: An error if the value has an unsupported type or is not valid
+// hex or decimal
+func (id *ID) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*id = 0
+		return nil
+	case int64:
+		*id = ID(v)
+		return nil
+	case []byte:
+		return id.scanText(string(v))
+	case string:
+		return id.scanText(v)
+	default:
+		return fmt.Errorf("cannot scan %T into ID", src)
+	}
+}
+
+// scanText parses a TEXT column value for Scan, as the hex form of
+// MarshalText or as a decimal BIGINT depending on its length.
+//
+// Parameters:
+//   - text: The column value
+//
+// Returns: An error if the text is not valid in the format its length implies
+func (id *ID) scanText(text string) error {
+	if len(text) == HexIDLength {
+		value, err := strconv.ParseUint(text, 16, 64)
+		if err != nil || formatHexID(value) != text {
+			return fmt.Errorf("invalid hex ID %q: want %d lowercase hex characters", text, HexIDLength)
+		}
+		*id = ID(value)
+		return nil
+	}
+	return id.scanDecimal(text)
+}
+
+// scanDecimal parses a BIGINT returned as decimal text for Scan.
+//
+// Parameters:
+//   - text: The column value
+//
+// Returns: An error if the text is not a valid decimal int64 or uint64
+func (id *ID) scanDecimal(text string) error {
+	if strings.HasPrefix(text, "-") {
+		value, err := strconv.ParseInt(text, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid decimal ID %q: %w", text, err)
+		}
+		*id = ID(value)
+		return nil
+	}
+
+	value, err := strconv.ParseUint(text, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid decimal ID %q: %w", text, err)
+	}
+	*id = ID(value)
+	return nil
+}
@@ -0,0 +1,84 @@
+// Package tsuniqid - 128-bit identifiers that need no machine ID assignment
+package tsuniqid
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"sync/atomic"
+	"time"
+)
+
+// 128-bit ID format constants.
+//
+// An ID128 holds, from the most significant byte down, a 48-bit Unix
+// timestamp in milliseconds, a 48-bit random node value drawn once per
+// generator and a 32-bit counter starting at a random value. The node value
+// replaces the assigned machine and instance IDs of uint64 IDs: with 2^48
+// possible values, even a million generators are unlikely to share one.
+const (
+	// ID128HexLength is the length of the hex form of an ID128
+	ID128HexLength = 32
+
+	// id128NodeBytes is the length of the ID128 node value in bytes
+	id128NodeBytes = 6
+)
+
+// ID128 is a 128-bit identifier from Generate128. Because the timestamp comes
+// first and is big-endian, IDs compare by time both as byte arrays and in
+// their hex form.
+type ID128 [16]byte
+
+// Generate128 creates a 128-bit identifier for distributed generation
+// without coordinated machine IDs. The timestamp comes from the generator's
+// clock, ignoring any custom epoch. The counter only repeats after 2^32 IDs
+// from one generator in the same millisecond, so IDs from one generator are
+// always unique.
+//
+// The node value and counter start are drawn from crypto/rand on first use,
+// falling back to the generator's math/rand source if the system entropy
+// source fails.
+//
+// Returns: A unique 128-bit identifier
+func (g *IDGenerator) Generate128() ID128 {
+	g.id128Once.Do(g.initID128)
+
+	var id ID128
+	var timestamp [8]byte
+	binary.BigEndian.PutUint64(timestamp[:], uint64(g.clock().UnixMilli()))
+	copy(id[:6], timestamp[2:])
+	copy(id[6:12], g.id128Node[:])
+	binary.BigEndian.PutUint32(id[12:], atomic.AddUint32(&g.id128Counter, 1))
+
+	return id
+}
+
+// initID128 draws the random node value and counter start for Generate128.
+func (g *IDGenerator) initID128() {
+	var seed [id128NodeBytes + 4]byte
+	if _, err := cryptoRead(seed[:]); err != nil {
+		if !g.singleThreaded {
+			g.mu.Lock()
+			defer g.mu.Unlock()
+		}
+		g.rng.Read(seed[:])
+	}
+
+	copy(g.id128Node[:], seed[:id128NodeBytes])
+	g.id128Counter = binary.BigEndian.Uint32(seed[id128NodeBytes:])
+}
+
+// String returns the ID as a 32-character lowercase hex string.
+//
+// Returns: The hex form of the ID
+func (id ID128) String() string {
+	return hex.EncodeToString(id[:])
+}
+
+// Time returns the timestamp embedded in the ID.
+//
+// Returns: The time at which the ID was generated, with millisecond precision
+func (id ID128) Time() time.Time {
+	var timestamp [8]byte
+	copy(timestamp[2:], id[:6])
+	return time.UnixMilli(int64(binary.BigEndian.Uint64(timestamp[:])))
+}
@@ -0,0 +1,90 @@
+package tsuniqid
+
+import (
+	"bytes"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestIDGenerator_Generate128 tests the layout, uniqueness and ordering of
+// 128-bit IDs.
+func TestIDGenerator_Generate128(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	gen := NewGenerator(WithClock(func() time.Time { return now }), WithEpoch(now.Add(-time.Hour)))
+
+	first := gen.Generate128()
+	if !first.Time().Equal(now) {
+		t.Errorf("Expected time %v, got %v", now, first.Time())
+	}
+	if s := first.String(); len(s) != ID128HexLength {
+		t.Errorf("Expected %d hex characters, got %s", ID128HexLength, s)
+	}
+
+	// IDs from one generator share the node and differ by one in the counter
+	second := gen.Generate128()
+	if !bytes.Equal(first[6:12], second[6:12]) {
+		t.Errorf("Node changed between IDs: %s, %s", first, second)
+	}
+	if second[15] != first[15]+1 {
+		t.Errorf("Counter did not increase by one: %s, %s", first, second)
+	}
+
+	// Other generators draw a different node
+	if other := NewGenerator().Generate128(); bytes.Equal(first[6:12], other[6:12]) {
+		t.Errorf("Two generators drew the same node: %s, %s", first, other)
+	}
+
+	// Later IDs sort after earlier ones, as bytes and as strings
+	now = now.Add(time.Millisecond)
+	later := gen.Generate128()
+	if bytes.Compare(later[:], second[:]) <= 0 || later.String() <= second.String() {
+		t.Errorf("Later ID %s does not sort after %s", later, second)
+	}
+}
+
+// TestIDGenerator_Generate128Concurrent tests that concurrent calls never
+// produce duplicates.
+func TestIDGenerator_Generate128Concurrent(t *testing.T) {
+	const goroutines, perGoroutine = 8, 1000
+	gen := NewGenerator()
+
+	var mu sync.Mutex
+	seen := make(map[ID128]struct{}, goroutines*perGoroutine)
+	var wg sync.WaitGroup
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ids := make([]ID128, perGoroutine)
+			for j := range ids {
+				ids[j] = gen.Generate128()
+			}
+			mu.Lock()
+			for _, id := range ids {
+				seen[id] = struct{}{}
+			}
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	if len(seen) != goroutines*perGoroutine {
+		t.Errorf("Expected %d unique IDs, got %d", goroutines*perGoroutine, len(seen))
+	}
+}
+
+// TestIDGenerator_Generate128Fallback tests that a failing entropy source
+// falls back to the math/rand source.
+func TestIDGenerator_Generate128Fallback(t *testing.T) {
+	defer func(old func([]byte) (int, error)) { cryptoRead = old }(cryptoRead)
+	cryptoRead = func([]byte) (int, error) { return 0, errors.New("entropy unavailable") }
+
+	genA := NewGenerator(WithSeed(1))
+	genB := NewGenerator(WithSeed(1))
+	a, b := genA.Generate128(), genB.Generate128()
+	if !bytes.Equal(a[6:], b[6:]) {
+		t.Errorf("Expected identical seeded node and counter, got %s and %s", a, b)
+	}
+}
@@ -0,0 +1,164 @@
+package tsuniqid
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// TestID_TextRoundTrip tests that IDs survive MarshalText/UnmarshalText.
+func TestID_TextRoundTrip(t *testing.T) {
+	gen := NewGenerator()
+
+	for _, want := range []ID{0, 1, gen.GenerateID().ID, ID(1<<63 | 0xabc)} {
+		text, err := want.MarshalText()
+		if err != nil {
+			t.Fatalf("MarshalText failed: %v", err)
+		}
+		if len(text) != HexIDLength {
+			t.Errorf("Text form has wrong length: %s", text)
+		}
+
+		var got ID
+		if err := got.UnmarshalText(text); err != nil {
+			t.Errorf("UnmarshalText(%s) failed: %v", text, err)
+		} else if got != want {
+			t.Errorf("Round-trip mismatch: got %d, expected %d", got, want)
+		}
+	}
+
+	var id ID
+	for _, bad := range []string{"", "xyz", "12345678901234567"} {
+		if err := id.UnmarshalText([]byte(bad)); err == nil {
+			t.Errorf("Expected error for %q", bad)
+		}
+	}
+}
+
+// TestID_StructEmbedding tests that IDs embedded in structs serialize as
+// decimal strings through encoding/json.
+func TestID_StructEmbedding(t *testing.T) {
+	type record struct {
+		ID ID `json:"id"`
+	}
+
+	in := record{ID: ID(0x1fffffffffffff1)}
+	data, err := json.Marshal(in)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if string(data) != `{"id":"144115188075855857"}` {
+		t.Errorf("Unexpected JSON: %s", data)
+	}
+
+	var out record
+	if err := json.Unmarshal(data, &out); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if out != in {
+		t.Errorf("JSON round-trip mismatch: got %+v, expected %+v", out, in)
+	}
+}
+
+// TestID_JSON tests JSON round-trips, including values beyond the precision
+// of JavaScript numbers, and that bare numbers are accepted.
+func TestID_JSON(t *testing.T) {
+	gen := NewGenerator()
+	sixtyBit := ID(1<<60 | 0x123456789abcdef)
+
+	for _, want := range []ID{0, 1, sixtyBit, gen.GenerateID().ID, ID(^uint64(0))} {
+		data, err := json.Marshal(want)
+		if err != nil {
+			t.Fatalf("Marshal failed: %v", err)
+		}
+		if data[0] != '"' {
+			t.Errorf("ID not marshaled as a string: %s", data)
+		}
+
+		var got ID
+		if err := json.Unmarshal(data, &got); err != nil {
+			t.Errorf("Unmarshal(%s) failed: %v", data, err)
+		} else if got != want {
+			t.Errorf("Round-trip mismatch: got %d, expected %d", got, want)
+		}
+	}
+
+	var got ID
+	if err := json.Unmarshal([]byte("1234907033823333871"), &got); err != nil || got != sixtyBit {
+		t.Errorf("Bare number: got %d, %v, expected %d", got, err, sixtyBit)
+	}
+	if err := json.Unmarshal([]byte("null"), &got); err != nil || got != sixtyBit {
+		t.Errorf("Null changed the ID: got %d, %v", got, err)
+	}
+
+	for _, bad := range []string{`""`, `"xyz"`, `"-1"`, `1.5`, `"18446744073709551616"`, `true`} {
+		if err := json.Unmarshal([]byte(bad), &got); err == nil {
+			t.Errorf("Expected error for %s", bad)
+		}
+	}
+}
+
+// TestID_Value tests that IDs are stored as int64 with the same bits.
+func TestID_Value(t *testing.T) {
+	for _, id := range []ID{0, 42, ID(1<<63 | 0x1234)} {
+		value, err := id.Value()
+		if err != nil {
+			t.Fatalf("Value failed: %v", err)
+		}
+
+		v, ok := value.(int64)
+		if !ok {
+			t.Fatalf("Value returned %T, expected int64", value)
+		}
+		if ID(v) != id {
+			t.Errorf("Value mismatch: got %d, expected %d", ID(v), id)
+		}
+	}
+}
+
+// TestID_Scan tests scanning IDs from supported database value types.
+func TestID_Scan(t *testing.T) {
+	testCases := []struct {
+		name     string
+		src      interface{}
+		expected ID
+		wantErr  bool
+	}{
+		{name: "Nil", src: nil, expected: 0},
+		{name: "Int64", src: int64(123456789), expected: 123456789},
+		{name: "Negative int64", src: int64(-1), expected: ID(1<<64 - 1)},
+		{name: "Decimal bytes", src: []byte("1234567890123"), expected: 1234567890123},
+		{name: "20-digit decimal bytes", src: []byte("18446744073709551615"), expected: ID(1<<64 - 1)},
+		{name: "Negative decimal bytes", src: []byte("-1"), expected: ID(1<<64 - 1)},
+		{name: "Decimal string", src: "255", expected: 255},
+		{name: "Hex string", src: "00000000000000ff", expected: 0xff},
+		{name: "All-digit hex string", src: "0123456789012345", expected: 0x0123456789012345},
+		{name: "Hex bytes", src: []byte("18f3a2c0b4e5d6a7"), expected: 0x18f3a2c0b4e5d6a7},
+		{name: "Uppercase hex string", src: "00000000000000FF", wantErr: true},
+		{name: "15-digit decimal string", src: "123456789012345", expected: 123456789012345},
+		{name: "17-digit decimal bytes", src: []byte("12345678901234567"), expected: 12345678901234567},
+		{name: "Unpadded hex bytes", src: []byte("abc"), wantErr: true},
+		{name: "Invalid string", src: "not-hex", wantErr: true},
+		{name: "Invalid decimal", src: []byte("12.5"), wantErr: true},
+		{name: "Unsupported type", src: 3.14, wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			id := ID(999)
+			err := id.Scan(tc.src)
+
+			if tc.wantErr {
+				if err == nil {
+					t.Errorf("Expected error, got ID %d", id)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Scan failed: %v", err)
+			}
+			if id != tc.expected {
+				t.Errorf("Scan mismatch: got %d, expected %d", id, tc.expected)
+			}
+		})
+	}
+}
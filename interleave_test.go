@@ -0,0 +1,89 @@
+package tsuniqid
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+// TestInterleaveBits tests that interleaving is reversible.
+func TestInterleaveBits(t *testing.T) {
+	gen := NewGenerator()
+	values := []uint64{0, 1, math.MaxUint64, 1 << 63}
+	for i := 0; i < 10000; i++ {
+		values = append(values, gen.GenerateUint64ID())
+	}
+
+	for _, id := range values {
+		if got := DeinterleaveBits(InterleaveBits(id)); got != id {
+			t.Errorf("Round-trip mismatch: got %x, expected %x", got, id)
+		}
+	}
+}
+
+// TestWithBitInterleaving tests that interleaved IDs decode correctly and
+// spread evenly over buckets chosen by their low bits.
+func TestWithBitInterleaving(t *testing.T) {
+	const (
+		count   = 64000
+		buckets = 64
+	)
+
+	// At low traffic in monotonic mode every counter is zero, so the raw low
+	// bits never change
+	start := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	plain := NewGenerator(WithClock(newFakeClock(start, time.Millisecond).Now), WithMonotonic())
+	mixed := NewGenerator(WithClock(newFakeClock(start, time.Millisecond).Now), WithMonotonic(), WithBitInterleaving(),
+		WithEnvironment(EnvironmentStaging))
+
+	var plainCounts, mixedCounts [buckets]int
+	for i := 0; i < count; i++ {
+		plainCounts[plain.GenerateUint64ID()&(buckets-1)]++
+
+		id := mixed.GenerateUint64ID()
+		mixedCounts[id&(buckets-1)]++
+
+		want := start.Add(time.Duration(i) * time.Millisecond)
+		if ts := mixed.Decode(id).Time(); !ts.Equal(want) {
+			t.Fatalf("Decoded time mismatch: got %v, expected %v", ts, want)
+		}
+		if env := EnvironmentOf(DeinterleaveBits(id)); env != EnvironmentStaging {
+			t.Fatalf("Environment mismatch: got %v", env)
+		}
+	}
+
+	// Every bucket must be within 20% of the ideal share once mixed
+	ideal := count / buckets
+	for b, n := range mixedCounts {
+		if n < ideal*8/10 || n > ideal*12/10 {
+			t.Errorf("Mixed bucket %d has %d IDs, ideal %d", b, n, ideal)
+		}
+	}
+
+	if chiSquare(mixedCounts[:], ideal) >= chiSquare(plainCounts[:], ideal) {
+		t.Errorf("Interleaving did not improve the bucket distribution")
+	}
+}
+
+// TestWithBitInterleaving_Parity tests that interleaving is rejected together
+// with the parity bit, which it would overwrite.
+func TestWithBitInterleaving_Parity(t *testing.T) {
+	for _, opts := range [][]Option{
+		{WithParity(), WithBitInterleaving()},
+		{WithBitInterleaving(), WithParity()},
+	} {
+		if _, err := NewGeneratorWithError(opts...); err == nil {
+			t.Errorf("Expected error for parity with bit interleaving")
+		}
+	}
+}
+
+// chiSquare measures how far bucket counts deviate from an ideal count.
+func chiSquare(counts []int, ideal int) float64 {
+	var sum float64
+	for _, n := range counts {
+		d := float64(n - ideal)
+		sum += d * d / float64(ideal)
+	}
+	return sum
+}
@@ -0,0 +1,109 @@
+// Package tsuniqid - KSUID-compatible identifiers
+package tsuniqid
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+)
+
+// KSUID format constants.
+//
+// A KSUID is 20 bytes: a 32-bit big-endian timestamp in seconds since
+// KSUIDEpoch followed by a 128-bit random payload, encoded as a 27-character
+// zero-padded base62 string using Base62CharSet.
+const (
+	// KSUIDEpoch is the KSUID epoch in Unix seconds (2014-05-13 16:53:20 UTC)
+	KSUIDEpoch = 1400000000
+
+	// KSUIDLength is the length of a KSUID string
+	KSUIDLength = 27
+
+	// ksuidTimestampBytes is the length of the KSUID timestamp in bytes
+	ksuidTimestampBytes = 4
+
+	// ksuidPayloadBytes is the length of the KSUID payload in bytes
+	ksuidPayloadBytes = 16
+)
+
+// GenerateKSUID creates an identifier in the 27-character KSUID format, for
+// interoperating with services that emit KSUIDs. The timestamp comes from the
+// generator's clock, ignoring any custom epoch, and the payload from
+// crypto/rand. If the system entropy source fails, the payload falls back to
+// the generator's math/rand source.
+//
+// Returns: A 27-character base62 KSUID
+func (g *IDGenerator) GenerateKSUID() string {
+	var raw [ksuidTimestampBytes + ksuidPayloadBytes]byte
+
+	timestamp := g.clock().Unix() - KSUIDEpoch
+	binary.BigEndian.PutUint32(raw[:ksuidTimestampBytes], uint32(timestamp))
+
+	payload := raw[ksuidTimestampBytes:]
+	if _, err := cryptoRead(payload); err != nil {
+		if !g.singleThreaded {
+			g.mu.Lock()
+			defer g.mu.Unlock()
+		}
+		g.rng.Read(payload)
+	}
+
+	return encodeKSUID(raw[:])
+}
+
+// DecodeKSUID extracts the timestamp from a KSUID string.
+//
+// Parameters:
+//   - s: A 27-character base62 KSUID
+//
+// Returns:
+//   - time.Time: The time embedded in the KSUID, with second precision
+//   - error: An error if s is not a valid KSUID
+func DecodeKSUID(s string) (time.Time, error) {
+	if len(s) != KSUIDLength {
+		return time.Time{}, fmt.Errorf("invalid KSUID length %d, expected %d", len(s), KSUIDLength)
+	}
+
+	value := new(big.Int)
+	base := big.NewInt(62)
+	for i := 0; i < len(s); i++ {
+		digit := strings.IndexByte(Base62CharSet, s[i])
+		if digit < 0 {
+			return time.Time{}, fmt.Errorf("invalid base62 character %q in KSUID %s", s[i], s)
+		}
+		value.Mul(value, base)
+		value.Add(value, big.NewInt(int64(digit)))
+	}
+
+	if value.BitLen() > (ksuidTimestampBytes+ksuidPayloadBytes)*8 {
+		return time.Time{}, fmt.Errorf("KSUID %s overflows 160 bits", s)
+	}
+
+	var raw [ksuidTimestampBytes + ksuidPayloadBytes]byte
+	value.FillBytes(raw[:])
+
+	timestamp := binary.BigEndian.Uint32(raw[:ksuidTimestampBytes])
+	return time.Unix(int64(timestamp)+KSUIDEpoch, 0), nil
+}
+
+// encodeKSUID encodes raw KSUID bytes as a zero-padded base62 string.
+//
+// Parameters:
+//   - raw: The 20 KSUID bytes
+//
+// Returns: The 27-character base62 representation
+func encodeKSUID(raw []byte) string {
+	value := new(big.Int).SetBytes(raw)
+	base := big.NewInt(62)
+	digit := new(big.Int)
+
+	result := make([]byte, KSUIDLength)
+	for i := KSUIDLength - 1; i >= 0; i-- {
+		value.DivMod(value, base, digit)
+		result[i] = Base62CharSet[digit.Int64()]
+	}
+
+	return string(result)
+}
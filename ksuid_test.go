@@ -0,0 +1,64 @@
+package tsuniqid
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestIDGenerator_GenerateKSUID tests that KSUIDs have the standard length,
+// use only base62 characters and decode to a time near now.
+func TestIDGenerator_GenerateKSUID(t *testing.T) {
+	gen := NewGenerator()
+	seen := make(map[string]bool)
+
+	for i := 0; i < 1000; i++ {
+		id := gen.GenerateKSUID()
+
+		if len(id) != KSUIDLength {
+			t.Fatalf("KSUID has wrong length: %s (length: %d)", id, len(id))
+		}
+		for _, c := range id {
+			if !strings.ContainsRune(Base62CharSet, c) {
+				t.Errorf("KSUID %s contains invalid character %q", id, c)
+			}
+		}
+
+		if seen[id] {
+			t.Errorf("Duplicate KSUID found: %s", id)
+		}
+		seen[id] = true
+
+		ts, err := DecodeKSUID(id)
+		if err != nil {
+			t.Errorf("Failed to decode %s: %v", id, err)
+		} else if d := time.Since(ts); d < -time.Second || d > 5*time.Second {
+			t.Errorf("Decoded time %v is not near now", ts)
+		}
+	}
+}
+
+// TestDecodeKSUID tests decoding of known and malformed KSUIDs.
+func TestDecodeKSUID(t *testing.T) {
+	// Reference KSUID from the segmentio/ksuid documentation
+	ts, err := DecodeKSUID("0ujtsYcgvSTl8PAuAdqWYSMnLOv")
+	if err != nil {
+		t.Fatalf("Failed to decode reference KSUID: %v", err)
+	}
+	if want := time.Unix(KSUIDEpoch+107608047, 0); !ts.Equal(want) {
+		t.Errorf("Reference KSUID time mismatch: got %v, expected %v", ts, want)
+	}
+
+	// Round-trip through a pinned clock
+	pinned := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	gen := NewGenerator(WithClock(func() time.Time { return pinned }))
+	if ts, err := DecodeKSUID(gen.GenerateKSUID()); err != nil || !ts.Equal(pinned) {
+		t.Errorf("Pinned KSUID round-trip mismatch: got %v (%v), expected %v", ts, err, pinned)
+	}
+
+	for _, s := range []string{"", "short", "0ujtsYcgvSTl8PAuAdqWYSMnLO-", "zzzzzzzzzzzzzzzzzzzzzzzzzzz"} {
+		if _, err := DecodeKSUID(s); err == nil {
+			t.Errorf("Expected error decoding %q", s)
+		}
+	}
+}
@@ -0,0 +1,181 @@
+// Package tsuniqid - Configurable bit layouts for uint64 IDs
+package tsuniqid
+
+import (
+	"fmt"
+)
+
+// Layout describes how the 64 bits of a uint64 ID are split between its
+// fields. From the most significant bit down, an ID holds the optional
+// reserved bits, which are always zero, the machine ID, the instance ID, the
+// timestamp, the counter and the optional watermark.
+//
+// Fewer machine bits and more counter bits suit bursty workloads on a small
+// fleet; more timestamp bits extend the range of representable times.
+//
+// The watermark is a per-generator sequence number that increases by one
+// with every generated ID, independently of the counter, so consumers can
+// detect lost IDs (see WatermarkOf). It wraps around at its field width, so
+// gaps are only detected modulo 2^WatermarkBits. Because it occupies the
+// lowest bits, it cannot be combined with WithParity.
+type Layout struct {
+	ReservedBits  uint // number of high bits kept zero; see WithReservedHighBits
+	MachineBits   uint // width of the machine ID field
+	InstanceBits  uint // width of the instance ID field
+	TimestampBits uint // width of the timestamp field, in milliseconds
+	CounterBits   uint // width of the counter field
+	WatermarkBits uint // width of the watermark field; zero disables it
+}
+
+// DefaultLayout is the 4/4/42/14 layout used by NewGenerator and by the
+// package-level constants such as MachineIDShift and MaxCounter.
+var DefaultLayout = Layout{
+	MachineBits:   4,
+	InstanceBits:  4,
+	TimestampBits: 42,
+	CounterBits:   14,
+}
+
+// WatermarkLayout reserves 6 bits of the default counter for a watermark,
+// leaving an 8-bit counter (256 IDs per millisecond per generator).
+var WatermarkLayout = Layout{
+	MachineBits:   4,
+	InstanceBits:  4,
+	TimestampBits: 42,
+	CounterBits:   8,
+	WatermarkBits: 6,
+}
+
+// Validate checks that every field except the reserved bits and watermark is
+// at least one bit wide and that the fields add up to exactly 64 bits.
+//
+// Returns: An error describing why the layout is invalid
+func (l Layout) Validate() error {
+	if l.MachineBits == 0 || l.InstanceBits == 0 || l.TimestampBits == 0 || l.CounterBits == 0 {
+		return fmt.Errorf("layout %+v has an empty field", l)
+	}
+
+	// Check each field separately so huge widths cannot overflow the sum
+	if l.ReservedBits > 64 || l.MachineBits > 64 || l.InstanceBits > 64 || l.TimestampBits > 64 ||
+		l.WatermarkBits > 64 || l.CounterBits > 64 ||
+		l.ReservedBits+l.MachineBits+l.InstanceBits+l.TimestampBits+l.WatermarkBits+l.CounterBits != 64 {
+		return fmt.Errorf("layout %+v does not sum to 64 bits", l)
+	}
+
+	return nil
+}
+
+// ParseUint64ID decomposes a uint64 ID generated with this layout into its
+// bit fields.
+//
+// Parameters:
+//   - id: The identifier to decompose
+//
+// Returns:
+//   - machineID: The machine ID
+//   - instanceID: The instance ID
+//   - timestamp: The timestamp in milliseconds
+//   - counter: The counter
+func (l Layout) ParseUint64ID(id uint64) (machineID, instanceID, timestamp, counter uint64) {
+	machineID = (id >> l.machineShift()) & l.maxMachineID()
+	instanceID = (id >> l.instanceShift()) & l.maxInstanceID()
+	timestamp = (id >> l.timestampShift()) & l.maxTimestamp()
+	counter = (id >> l.counterShift()) & l.maxCounter()
+	return machineID, instanceID, timestamp, counter
+}
+
+// WatermarkOf extracts the watermark from a uint64 ID generated with this
+// layout. Consecutive IDs from one generator have consecutive watermarks
+// (modulo 2^WatermarkBits), so a jump greater than one reveals lost IDs.
+//
+// Parameters:
+//   - id: The identifier to inspect
+//
+// Returns: The watermark, or 0 if the layout has no watermark field
+func (l Layout) WatermarkOf(id uint64) uint64 {
+	return id & l.maxWatermark()
+}
+
+// WatermarkOf extracts the watermark from a uint64 ID generated by this
+// generator, like Layout.WatermarkOf with the generator's layout.
+//
+// Parameters:
+//   - id: The identifier to inspect
+//
+// Returns: The watermark, or 0 if the layout has no watermark field
+func (g *IDGenerator) WatermarkOf(id uint64) uint64 {
+	if g.interleave {
+		id = DeinterleaveBits(id)
+	}
+	return g.layout.WatermarkOf(g.UnspreadID(id))
+}
+
+// identityPrefix shifts a machine ID and instance ID into their positions
+// in a uint64 ID.
+//
+// Parameters:
+//   - machineID: The machine ID
+//   - instanceID: The instance ID
+//
+// Returns: The machine and instance bits of an ID
+func (l Layout) identityPrefix(machineID, instanceID uint64) uint64 {
+	return (machineID << l.machineShift()) | (instanceID << l.instanceShift())
+}
+
+// counterShift returns the number of bits to shift the counter.
+func (l Layout) counterShift() uint {
+	return l.WatermarkBits
+}
+
+// timestampShift returns the number of bits to shift the timestamp.
+func (l Layout) timestampShift() uint {
+	return l.counterShift() + l.CounterBits
+}
+
+// instanceShift returns the number of bits to shift the instance ID.
+func (l Layout) instanceShift() uint {
+	return l.timestampShift() + l.TimestampBits
+}
+
+// machineShift returns the number of bits to shift the machine ID.
+func (l Layout) machineShift() uint {
+	return l.instanceShift() + l.InstanceBits
+}
+
+// maxMachineID returns the maximum machine ID value.
+func (l Layout) maxMachineID() uint64 {
+	return bitMask(l.MachineBits)
+}
+
+// maxInstanceID returns the maximum instance ID value.
+func (l Layout) maxInstanceID() uint64 {
+	return bitMask(l.InstanceBits)
+}
+
+// maxTimestamp returns the maximum timestamp value.
+func (l Layout) maxTimestamp() uint64 {
+	return bitMask(l.TimestampBits)
+}
+
+// maxWatermark returns the maximum watermark value.
+func (l Layout) maxWatermark() uint64 {
+	return bitMask(l.WatermarkBits)
+}
+
+// maxCounter returns the maximum counter value.
+func (l Layout) maxCounter() uint64 {
+	return bitMask(l.CounterBits)
+}
+
+// bitMask returns a mask of the given number of low bits.
+//
+// Parameters:
+//   - bits: The number of bits, at most 64
+//
+// Returns: A value with the low bits set
+func bitMask(bits uint) uint64 {
+	if bits >= 64 {
+		return ^uint64(0)
+	}
+	return 1<<bits - 1
+}
@@ -0,0 +1,183 @@
+package tsuniqid
+
+import (
+	"testing"
+	"time"
+)
+
+// TestLayout_Validate tests that layouts must have non-empty fields summing
+// to 64 bits.
+func TestLayout_Validate(t *testing.T) {
+	testCases := []struct {
+		name    string
+		layout  Layout
+		wantErr bool
+	}{
+		{name: "Default", layout: DefaultLayout, wantErr: false},
+		{name: "Wide counter", layout: Layout{MachineBits: 2, InstanceBits: 2, TimestampBits: 42, CounterBits: 18}, wantErr: false},
+		{name: "Too short", layout: Layout{MachineBits: 4, InstanceBits: 4, TimestampBits: 41, CounterBits: 14}, wantErr: true},
+		{name: "Too long", layout: Layout{MachineBits: 4, InstanceBits: 4, TimestampBits: 43, CounterBits: 14}, wantErr: true},
+		{name: "Empty field", layout: Layout{MachineBits: 0, InstanceBits: 8, TimestampBits: 42, CounterBits: 14}, wantErr: true},
+		{name: "Overflowing sum", layout: Layout{MachineBits: ^uint(0), InstanceBits: 2, TimestampBits: 42, CounterBits: 21}, wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.layout.Validate()
+			if (err != nil) != tc.wantErr {
+				t.Errorf("Got error %v, wantErr %v", err, tc.wantErr)
+			}
+
+			gen, err := NewGeneratorWithLayout(tc.layout)
+			if (err != nil) != tc.wantErr || (gen == nil) != tc.wantErr {
+				t.Errorf("NewGeneratorWithLayout returned %v, %v; wantErr %v", gen, err, tc.wantErr)
+			}
+		})
+	}
+}
+
+// TestNewGeneratorWithLayout tests that a custom layout places every field
+// where Layout.ParseUint64ID expects it.
+func TestNewGeneratorWithLayout(t *testing.T) {
+	layout := Layout{MachineBits: 2, InstanceBits: 3, TimestampBits: 41, CounterBits: 18}
+	pinned := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	gen, err := NewGeneratorWithLayout(layout,
+		WithMachineID(3), WithInstanceID(6), WithClock(func() time.Time { return pinned }))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	seen := make(map[uint64]bool)
+	for i := uint64(1); i <= MaxCounter+100; i++ {
+		id := gen.GenerateUint64ID()
+		if seen[id] {
+			t.Fatalf("Duplicate ID found: %d", id)
+		}
+		seen[id] = true
+
+		// The wider counter does not wrap where the default one would
+		machineID, instanceID, timestamp, counter := layout.ParseUint64ID(id)
+		if machineID != 3 || instanceID != 6 || timestamp != uint64(pinned.UnixMilli()) || counter != i {
+			t.Fatalf("Unexpected fields %d/%d/%d/%d for ID %d", machineID, instanceID, timestamp, counter, i)
+		}
+	}
+
+	if ts := gen.Decode(gen.GenerateUint64ID()).Time(); !ts.Equal(pinned) {
+		t.Errorf("Decoded time mismatch: got %v, expected %v", ts, pinned)
+	}
+
+	// Field values are validated against the layout rather than the constants
+	if _, err := NewGeneratorWithLayout(layout, WithMachineID(4)); err == nil {
+		t.Errorf("Expected error for machine ID exceeding a 2-bit field")
+	}
+	if _, err := NewGeneratorWithLayout(Layout{MachineBits: 4, InstanceBits: 4, TimestampBits: 54, CounterBits: 2},
+		WithEnvironment(EnvironmentStaging)); err == nil {
+		t.Errorf("Expected error for environment tag filling the counter")
+	}
+}
+
+// TestDefaultLayout tests that the default layout matches the package constants.
+func TestDefaultLayout(t *testing.T) {
+	if DefaultLayout.machineShift() != MachineIDShift ||
+		DefaultLayout.instanceShift() != InstanceIDShift ||
+		DefaultLayout.timestampShift() != TimestampShift {
+		t.Errorf("Default layout shifts do not match the constants")
+	}
+	if DefaultLayout.maxMachineID() != MaxMachineID ||
+		DefaultLayout.maxInstanceID() != MaxInstanceID ||
+		DefaultLayout.maxTimestamp() != MaxTimestamp ||
+		DefaultLayout.maxCounter() != MaxCounter {
+		t.Errorf("Default layout masks do not match the constants")
+	}
+}
+
+// TestWatermarkLayout tests that watermarks increase by exactly one with
+// every generated ID, across generation methods and counter resets.
+func TestWatermarkLayout(t *testing.T) {
+	start := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	clock := newFakeClock(start, 100*time.Microsecond)
+
+	gen, err := NewGeneratorWithLayout(WatermarkLayout, WithClock(clock.Now), WithMonotonic())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var ids []uint64
+	for i := 0; i < 500; i++ {
+		ids = append(ids, gen.GenerateUint64ID())
+	}
+	ids = append(ids, gen.GenerateUint64IDs(500)...)
+
+	for i, id := range ids {
+		want := uint64(i) & WatermarkLayout.maxWatermark()
+		if got := gen.WatermarkOf(id); got != want {
+			t.Fatalf("Watermark of ID %d: got %d, expected %d", i, got, want)
+		}
+		if got := gen.Decode(id).Watermark; got != want {
+			t.Fatalf("Decoded watermark of ID %d: got %d, expected %d", i, got, want)
+		}
+		if i > 0 && id <= ids[i-1] {
+			t.Fatalf("ID %d not increasing: %d after %d", i, id, ids[i-1])
+		}
+	}
+
+	// The default layout has no watermark
+	if w := NewGenerator().WatermarkOf(^uint64(0)); w != 0 {
+		t.Errorf("Expected no watermark in the default layout, got %d", w)
+	}
+
+	if _, err := NewGeneratorWithLayout(WatermarkLayout, WithParity()); err == nil {
+		t.Errorf("Expected error combining parity with a watermark")
+	}
+}
+
+// TestWithReservedHighBits tests that reserved high bits stay zero and that
+// IDs still decode with the reduced layout.
+func TestWithReservedHighBits(t *testing.T) {
+	epoch := time.Now().AddDate(0, 0, -1)
+
+	for _, n := range []int{0, 1, 3, 8} {
+		gen, err := NewGeneratorWithError(WithReservedHighBits(n), WithEpoch(epoch), WithInstanceID(5))
+		if err != nil {
+			t.Fatalf("Unexpected error reserving %d bits: %v", n, err)
+		}
+		if err := gen.layout.Validate(); err != nil {
+			t.Fatalf("Invalid layout after reserving %d bits: %v", n, err)
+		}
+
+		for i := 0; i < 1000; i++ {
+			before := time.Now().Truncate(time.Millisecond)
+			id := gen.GenerateUint64ID()
+
+			if n > 0 && id>>(64-n) != 0 {
+				t.Fatalf("Top %d bits of ID %x are not zero", n, id)
+			}
+
+			components := gen.Decode(id)
+			if components.MachineID != gen.MachineID() || components.InstanceID != 5 {
+				t.Fatalf("Identity mismatch in %+v", components)
+			}
+			if ts := components.Time(); ts.Before(before) || ts.After(time.Now()) {
+				t.Fatalf("Decoded timestamp %v out of range", ts)
+			}
+		}
+	}
+
+	// Without a recent epoch the shrunk timestamp cannot hold the current time
+	if _, err := NewGeneratorWithError(WithReservedHighBits(8)); err == nil {
+		t.Errorf("Expected error for a timestamp field too short for the Unix epoch")
+	}
+
+	// Explicit IDs must still fit after the machine field shrinks
+	if _, err := NewGeneratorWithError(WithMachineID(MaxMachineID), WithReservedHighBits(1)); err == nil {
+		t.Errorf("Expected error for machine ID exceeding the reduced field")
+	}
+
+	if _, err := NewGeneratorWithError(WithReservedHighBits(-1)); err == nil {
+		t.Errorf("Expected error for negative reserved bits")
+	}
+	if _, err := NewGeneratorWithError(WithReservedHighBits(64)); err == nil {
+		t.Errorf("Expected error for reserving the whole ID")
+	}
+}
@@ -0,0 +1,82 @@
+// Package tsuniqid - Analysis of IDs from generators without per-millisecond counter resets
+package tsuniqid
+
+import (
+	"sort"
+)
+
+// LegacyIDReport summarizes how many IDs in a set follow the legacy counter
+// pattern of a free-running counter, which keeps incrementing across
+// milliseconds instead of restarting at zero.
+type LegacyIDReport struct {
+	Total        int // number of IDs analyzed
+	Streams      int // distinct machine and instance ID pairs
+	Milliseconds int // new milliseconds examined, excluding each stream's first
+	NonReset     int // new milliseconds whose first counter is not zero
+	Wrapped      int // IDs whose counter is not above the previous one in the same millisecond
+}
+
+// Legacy reports whether any ID shows the non-reset or wrapped pattern of a
+// free-running counter.
+//
+// Returns: True if the IDs do not all come from per-millisecond-reset
+// counters
+func (r LegacyIDReport) Legacy() bool {
+	return r.NonReset > 0 || r.Wrapped > 0
+}
+
+// AnalyzeLegacyIDs inspects IDs in the default layout for the counter
+// behavior of generators that never reset their counter per millisecond:
+// the free-running counter of the default mode, as opposed to the
+// per-millisecond-reset counter of WithMonotonic and WithOverflowWait.
+//
+// IDs are grouped into streams by machine and instance ID and ordered by
+// timestamp, keeping the input order within a millisecond, so ids should be
+// in generation order. A generator that resets its counter starts every
+// millisecond at zero and only increases it; a free-running generator starts
+// each millisecond wherever its counter happens to be and wraps at
+// MaxCounter. The first millisecond of each stream is not counted as a new
+// millisecond, since the data may begin partway through it. Sampled or
+// incomplete data can make reset generators look legacy as well.
+//
+// Parameters:
+//   - ids: The IDs to analyze, in generation order
+//
+// Returns: A report of the free-running patterns found
+func AnalyzeLegacyIDs(ids []uint64) LegacyIDReport {
+	report := LegacyIDReport{Total: len(ids)}
+
+	streams := make(map[uint64][]uint64)
+	var prefixes []uint64
+	for _, id := range ids {
+		prefix := id >> InstanceIDShift
+		if _, ok := streams[prefix]; !ok {
+			prefixes = append(prefixes, prefix)
+		}
+		streams[prefix] = append(streams[prefix], id)
+	}
+	report.Streams = len(prefixes)
+
+	for _, prefix := range prefixes {
+		stream := streams[prefix]
+		sort.SliceStable(stream, func(i, j int) bool {
+			return (stream[i]>>TimestampShift)&MaxTimestamp < (stream[j]>>TimestampShift)&MaxTimestamp
+		})
+
+		for i := 1; i < len(stream); i++ {
+			_, _, timestamp, counter := ParseUint64ID(stream[i])
+			_, _, prevTimestamp, prevCounter := ParseUint64ID(stream[i-1])
+
+			if timestamp != prevTimestamp {
+				report.Milliseconds++
+				if counter != 0 {
+					report.NonReset++
+				}
+			} else if counter <= prevCounter {
+				report.Wrapped++
+			}
+		}
+	}
+
+	return report
+}
@@ -0,0 +1,59 @@
+package tsuniqid
+
+import (
+	"testing"
+)
+
+// counterID builds a default-layout ID from its fields.
+func counterID(machineID, instanceID, timestamp, counter uint64) uint64 {
+	return machineID<<MachineIDShift | instanceID<<InstanceIDShift | timestamp<<TimestampShift | counter
+}
+
+// TestAnalyzeLegacyIDs tests the report for handcrafted reset and
+// free-running streams.
+func TestAnalyzeLegacyIDs(t *testing.T) {
+	// Stream 1/1 resets its counter every millisecond
+	reset := []uint64{
+		counterID(1, 1, 1000, 5),
+		counterID(1, 1, 1000, 6),
+		counterID(1, 1, 1001, 0),
+		counterID(1, 1, 1001, 1),
+		counterID(1, 1, 1002, 0),
+	}
+
+	// Stream 2/3 carries its counter across milliseconds and wraps once
+	freeRunning := []uint64{
+		counterID(2, 3, 1000, 100),
+		counterID(2, 3, 1001, 101),
+		counterID(2, 3, 1001, MaxCounter),
+		counterID(2, 3, 1001, 0),
+		counterID(2, 3, 1002, 1),
+		counterID(2, 3, 1003, 2),
+	}
+
+	if report := AnalyzeLegacyIDs(reset); report.Legacy() || report.Milliseconds != 2 {
+		t.Errorf("Reset stream reported as free-running: %+v", report)
+	}
+
+	// Interleave both streams
+	var ids []uint64
+	for i := 0; i < len(freeRunning); i++ {
+		if i < len(reset) {
+			ids = append(ids, reset[i])
+		}
+		ids = append(ids, freeRunning[i])
+	}
+
+	expected := LegacyIDReport{Total: 11, Streams: 2, Milliseconds: 5, NonReset: 3, Wrapped: 1}
+	report := AnalyzeLegacyIDs(ids)
+	if report != expected {
+		t.Errorf("Report mismatch: got %+v, expected %+v", report, expected)
+	}
+	if !report.Legacy() {
+		t.Errorf("Expected free-running pattern to be detected")
+	}
+
+	if report := AnalyzeLegacyIDs(nil); report != (LegacyIDReport{}) {
+		t.Errorf("Expected empty report, got %+v", report)
+	}
+}
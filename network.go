@@ -4,20 +4,32 @@ package tsuniqid
 import (
 	"errors"
 	"net"
+	"os"
 )
 
+// netInterfaces lists the system's network interfaces; replaced in tests
+var netInterfaces = net.Interfaces
+
+// osHostname returns the host name reported by the kernel; replaced in tests
+var osHostname = os.Hostname
+
+// osGetpid returns the process ID; replaced in tests
+var osGetpid = os.Getpid
+
 // getLocalIP retrieves the first available non-loopback IPv4 address from network interfaces.
 // This function iterates through all network interfaces and returns the first valid local IP address.
+// On IPv6-only hosts, it falls back to the first global unicast IPv6 address.
 //
 // Returns:
-//   - net.IP: The first available local IPv4 address
+//   - net.IP: The first available local IPv4 address, or IPv6 address if there is none
 //   - error: An error if no valid IP address is found
 func getLocalIP() (net.IP, error) {
-	interfaces, err := net.Interfaces()
+	interfaces, err := netInterfaces()
 	if err != nil {
 		return nil, err
 	}
 
+	var fallback net.IP
 	for _, iface := range interfaces {
 		// Skip interfaces that are down
 		if iface.Flags&net.FlagUp == 0 {
@@ -39,12 +51,46 @@ func getLocalIP() (net.IP, error) {
 			if ip != nil {
 				return ip, nil
 			}
+
+			// Remember the first IPv6 address in case there is no IPv4 address
+			if fallback == nil {
+				fallback = extractIPv6FromAddr(addr)
+			}
 		}
 	}
 
+	if fallback != nil {
+		return fallback, nil
+	}
+
 	return nil, errors.New("no valid local IP address found")
 }
 
+// getMACAddress retrieves the first non-loopback hardware address from network interfaces.
+//
+// Returns:
+//   - net.HardwareAddr: The first available hardware address
+//   - error: An error if no hardware address is found
+func getMACAddress() (net.HardwareAddr, error) {
+	interfaces, err := netInterfaces()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, iface := range interfaces {
+		// Skip loopback interfaces, which have no hardware address
+		if iface.Flags&net.FlagLoopback != 0 {
+			continue
+		}
+
+		if len(iface.HardwareAddr) > 0 {
+			return iface.HardwareAddr, nil
+		}
+	}
+
+	return nil, errors.New("no hardware address found")
+}
+
 // extractIPFromAddr extracts an IPv4 address from a network address.
 // This function handles both *net.IPNet and *net.IPAddr types and filters out
 // loopback addresses and IPv6 addresses.
@@ -55,17 +101,7 @@ func getLocalIP() (net.IP, error) {
 // Returns:
 //   - net.IP: The extracted IPv4 address, or nil if not valid
 func extractIPFromAddr(addr net.Addr) net.IP {
-	var ip net.IP
-
-	// Handle different address types
-	switch v := addr.(type) {
-	case *net.IPNet:
-		ip = v.IP
-	case *net.IPAddr:
-		ip = v.IP
-	default:
-		return nil
-	}
+	ip := addrIP(addr)
 
 	// Filter out invalid addresses
 	if ip == nil || ip.IsLoopback() {
@@ -80,3 +116,40 @@ func extractIPFromAddr(addr net.Addr) net.IP {
 
 	return ipv4
 }
+
+// extractIPv6FromAddr extracts a global unicast IPv6 address from a network address.
+// Link-local addresses are filtered out because they are not unique across hosts.
+//
+// Parameters:
+//   - addr: The network address to extract IP from
+//
+// Returns:
+//   - net.IP: The extracted IPv6 address, or nil if not valid
+func extractIPv6FromAddr(addr net.Addr) net.IP {
+	ip := addrIP(addr)
+
+	// Filter out IPv4, loopback, link-local and other non-global addresses
+	if ip == nil || ip.To4() != nil || !ip.IsGlobalUnicast() {
+		return nil
+	}
+
+	return ip
+}
+
+// addrIP returns the IP of a *net.IPNet or *net.IPAddr network address.
+//
+// Parameters:
+//   - addr: The network address to extract IP from
+//
+// Returns:
+//   - net.IP: The IP of the address, or nil for other address types
+func addrIP(addr net.Addr) net.IP {
+	switch v := addr.(type) {
+	case *net.IPNet:
+		return v.IP
+	case *net.IPAddr:
+		return v.IP
+	default:
+		return nil
+	}
+}
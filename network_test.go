@@ -1,7 +1,12 @@
 package tsuniqid
 
 import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log/slog"
 	"net"
+	"strings"
 	"testing"
 )
 
@@ -21,9 +26,9 @@ func TestGetLocalIP(t *testing.T) {
 		return
 	}
 
-	// IP should be IPv4
-	if ip.To4() == nil {
-		t.Errorf("getLocalIP returned non-IPv4 address: %v", ip)
+	// IP should be IPv4, or global unicast IPv6 on IPv6-only hosts
+	if ip.To4() == nil && !ip.IsGlobalUnicast() {
+		t.Errorf("getLocalIP returned non-global IPv6 address: %v", ip)
 	}
 
 	// IP should not be loopback
@@ -99,3 +104,307 @@ func TestExtractIPFromAddr_UnsupportedType(t *testing.T) {
 		t.Errorf("Expected nil for unsupported address type, got %v", ip)
 	}
 }
+
+// TestExtractIPv6FromAddr tests the extractIPv6FromAddr function with various address types.
+func TestExtractIPv6FromAddr(t *testing.T) {
+	testCases := []struct {
+		name     string
+		addr     net.Addr
+		expected bool // whether we expect a valid IP
+	}{
+		{
+			name:     "Valid IPv6 IPNet",
+			addr:     &net.IPNet{IP: net.ParseIP("2001:db8::1"), Mask: net.CIDRMask(64, 128)},
+			expected: true,
+		},
+		{
+			name:     "Valid IPv6 IPAddr",
+			addr:     &net.IPAddr{IP: net.ParseIP("2606:4700::1111")},
+			expected: true,
+		},
+		{
+			name:     "IPv4 address (should be filtered out)",
+			addr:     &net.IPNet{IP: net.ParseIP("192.168.1.100"), Mask: net.CIDRMask(24, 32)},
+			expected: false,
+		},
+		{
+			name:     "Loopback address (should be filtered out)",
+			addr:     &net.IPNet{IP: net.ParseIP("::1"), Mask: net.CIDRMask(128, 128)},
+			expected: false,
+		},
+		{
+			name:     "Link-local address (should be filtered out)",
+			addr:     &net.IPAddr{IP: net.ParseIP("fe80::1")},
+			expected: false,
+		},
+		{
+			name:     "Nil IP in IPNet",
+			addr:     &net.IPNet{IP: nil, Mask: net.CIDRMask(64, 128)},
+			expected: false,
+		},
+		{
+			name:     "Unsupported address type",
+			addr:     &net.TCPAddr{IP: net.ParseIP("2001:db8::1"), Port: 8080},
+			expected: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ip := extractIPv6FromAddr(tc.addr)
+
+			if tc.expected {
+				if ip == nil {
+					t.Errorf("Expected valid IP, got nil")
+				} else if ip.To4() != nil {
+					t.Errorf("Expected IPv6 address, got %v", ip)
+				} else if !ip.IsGlobalUnicast() {
+					t.Errorf("Expected global unicast address, got %v", ip)
+				}
+			} else {
+				if ip != nil {
+					t.Errorf("Expected nil IP, got %v", ip)
+				}
+			}
+		})
+	}
+}
+
+// TestGetMACAddress tests hardware address selection with a fake interface list.
+func TestGetMACAddress(t *testing.T) {
+	defer func(old func() ([]net.Interface, error)) { netInterfaces = old }(netInterfaces)
+
+	mac := net.HardwareAddr{0x02, 0x42, 0xac, 0x11, 0x00, 0x02}
+	netInterfaces = func() ([]net.Interface, error) {
+		return []net.Interface{
+			{Name: "lo", Flags: net.FlagUp | net.FlagLoopback},
+			{Name: "tun0", Flags: net.FlagUp},
+			{Name: "eth0", Flags: net.FlagUp, HardwareAddr: mac},
+			{Name: "eth1", Flags: net.FlagUp, HardwareAddr: net.HardwareAddr{0x02, 0, 0, 0, 0, 0x01}},
+		}, nil
+	}
+
+	got, err := getMACAddress()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got.String() != mac.String() {
+		t.Errorf("Expected %v, got %v", mac, got)
+	}
+
+	// The machine ID is derived from the MAC address alone
+	gen := NewGenerator(WithMACMachineID())
+	if want := hashToUint64(mac.String()) & MaxMachineID; gen.MachineID() != want {
+		t.Errorf("Machine ID mismatch: got %d, expected %d", gen.MachineID(), want)
+	}
+
+	// Without a hardware address, derivation falls back to hostname and IP
+	netInterfaces = func() ([]net.Interface, error) {
+		return []net.Interface{{Name: "lo", Flags: net.FlagUp | net.FlagLoopback}}, nil
+	}
+	if _, err := getMACAddress(); err == nil {
+		t.Errorf("Expected error without hardware addresses")
+	}
+	if gen := NewGenerator(WithMACMachineID()); gen.MachineID() > MaxMachineID {
+		t.Errorf("Fallback machine ID out of range: %d", gen.MachineID())
+	}
+}
+
+// TestWithHostnameMachineID tests that the machine ID depends only on the
+// hostname and falls back to a random ID without one.
+func TestWithHostnameMachineID(t *testing.T) {
+	defer func(old func() (string, error)) { osHostname = old }(osHostname)
+	defer func(old func() ([]net.Interface, error)) { netInterfaces = old }(netInterfaces)
+
+	osHostname = func() (string, error) { return "web-0", nil }
+	want := hashToUint64("web-0") & MaxMachineID
+
+	// Without a usable IP the default derivation is random on every start,
+	// but the hostname-only derivation stays the same
+	netInterfaces = func() ([]net.Interface, error) {
+		return nil, errors.New("no interfaces")
+	}
+	for i := 0; i < 2; i++ {
+		if gen := NewGenerator(WithHostnameMachineID()); gen.MachineID() != want {
+			t.Errorf("Machine ID mismatch: got %d, expected %d", gen.MachineID(), want)
+		}
+	}
+
+	// An explicit machine ID takes precedence
+	if gen := NewGenerator(WithHostnameMachineID(), WithMachineID(3)); gen.MachineID() != 3 {
+		t.Errorf("Expected explicit machine ID 3, got %d", gen.MachineID())
+	}
+
+	// An empty hostname falls back to a random machine ID
+	osHostname = func() (string, error) { return "", nil }
+	if gen := NewGenerator(WithHostnameMachineID()); gen.MachineID() > MaxMachineID {
+		t.Errorf("Fallback machine ID out of range: %d", gen.MachineID())
+	}
+}
+
+// TestGenerateMachineID_Cached tests that the default machine ID is computed
+// once and recomputed only after resetMachineIDCache.
+func TestGenerateMachineID_Cached(t *testing.T) {
+	defer func(old func() (string, error)) { osHostname = old }(osHostname)
+	defer func(old func() ([]net.Interface, error)) { netInterfaces = old }(netInterfaces)
+	defer resetMachineIDCache()
+
+	var calls int
+	osHostname = func() (string, error) {
+		calls++
+		return "cache-host", nil
+	}
+	netInterfaces = func() ([]net.Interface, error) { return nil, nil }
+
+	resetMachineIDCache()
+	first := NewGenerator().MachineID()
+	for i := 0; i < 10; i++ {
+		if gen := NewGenerator(); gen.MachineID() != first {
+			t.Errorf("Machine ID changed from %d to %d", first, gen.MachineID())
+		}
+	}
+	if calls != 1 {
+		t.Errorf("Expected the hostname to be read once, got %d", calls)
+	}
+
+	resetMachineIDCache()
+	NewGenerator()
+	if calls != 2 {
+		t.Errorf("Expected a recomputation after reset, got %d hostname reads", calls)
+	}
+}
+
+// TestNewGeneratorE tests that a random machine ID is reported only when both
+// the hostname and IP are unavailable.
+func TestNewGeneratorE(t *testing.T) {
+	defer func(old func() (string, error)) { osHostname = old }(osHostname)
+	defer func(old func() ([]net.Interface, error)) { netInterfaces = old }(netInterfaces)
+	defer resetMachineIDCache()
+
+	// Start from an empty registry so earlier tests cannot exhaust the machine
+	assignmentsMu.Lock()
+	assignments = make(map[Layout]map[Assignment]int)
+	assignmentsMu.Unlock()
+
+	osHostname = func() (string, error) { return "", errors.New("no hostname") }
+	netInterfaces = func() ([]net.Interface, error) { return nil, errors.New("no interfaces") }
+	resetMachineIDCache()
+
+	gen, err := NewGeneratorE()
+	if !errors.Is(err, ErrMachineIDUnavailable) {
+		t.Errorf("Expected ErrMachineIDUnavailable, got %v", err)
+	}
+	if gen == nil || gen.MachineID() > MaxMachineID {
+		t.Errorf("Expected a usable generator alongside the error")
+	}
+	if _, err := NewGeneratorE(WithHostnameMachineID()); !errors.Is(err, ErrMachineIDUnavailable) {
+		t.Errorf("Expected ErrMachineIDUnavailable without a hostname, got %v", err)
+	}
+
+	// An explicit machine ID needs no lookup
+	if _, err := NewGeneratorE(WithMachineID(1)); err != nil {
+		t.Errorf("Unexpected error with an explicit machine ID: %v", err)
+	}
+
+	// One source of host information is enough
+	osHostname = func() (string, error) { return "strict-host", nil }
+	resetMachineIDCache()
+	if _, err := NewGeneratorE(); err != nil {
+		t.Errorf("Unexpected error with a hostname: %v", err)
+	}
+
+	// Invalid options are reported without a generator
+	if gen, err := NewGeneratorE(WithSuffixLength(-1)); err == nil || gen != nil {
+		t.Errorf("Expected an option error and no generator, got %v, %v", gen, err)
+	}
+}
+
+// TestWithRandomMachineID tests that a random machine ID never reads the
+// hostname or network interfaces.
+func TestWithRandomMachineID(t *testing.T) {
+	defer func(old func() (string, error)) { osHostname = old }(osHostname)
+	defer func(old func() ([]net.Interface, error)) { netInterfaces = old }(netInterfaces)
+	defer resetMachineIDCache()
+
+	osHostname = func() (string, error) {
+		t.Errorf("Hostname read for a random machine ID")
+		return "leaky-host", nil
+	}
+	netInterfaces = func() ([]net.Interface, error) {
+		t.Errorf("Network interfaces read for a random machine ID")
+		return nil, nil
+	}
+	resetMachineIDCache()
+
+	// Over many generators every machine ID shows up; so many generators
+	// necessarily exhaust the instance IDs, which is not under test here
+	seen := make(map[uint64]bool)
+	for i := 0; i < 500; i++ {
+		gen, err := NewGeneratorE(WithRandomMachineID())
+		if errors.Is(err, ErrMachineIDUnavailable) || (err != nil && !errors.Is(err, ErrInstanceIDExhausted)) {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		seen[gen.MachineID()] = true
+	}
+	if len(seen) != MaxMachineID+1 {
+		t.Errorf("Expected all %d machine IDs, got %d", MaxMachineID+1, len(seen))
+	}
+
+	// The entropy source failing still yields a machine ID
+	defer func(old func([]byte) (int, error)) { cryptoRead = old }(cryptoRead)
+	cryptoRead = func([]byte) (int, error) { return 0, errors.New("entropy unavailable") }
+	if gen := NewGenerator(WithRandomMachineID()); gen.MachineID() > MaxMachineID {
+		t.Errorf("Fallback machine ID out of range: %d", gen.MachineID())
+	}
+}
+
+// TestWithLogger_Identity tests the Info line logged at construction, and
+// that it does not read host information for a random machine ID.
+func TestWithLogger_Identity(t *testing.T) {
+	defer func(old func() (string, error)) { osHostname = old }(osHostname)
+	defer resetMachineIDCache()
+
+	hostnameReads := 0
+	osHostname = func() (string, error) {
+		hostnameReads++
+		return "audit-host", nil
+	}
+	resetMachineIDCache()
+
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&logs, nil))
+
+	gen := NewGenerator(WithLogger(logger))
+	line := logs.String()
+	for _, want := range []string{
+		"level=INFO", `msg="generator created"`, "hostname=audit-host", "machine_id_source=host",
+		fmt.Sprintf("machine_id=%d", gen.MachineID()), fmt.Sprintf("instance_id=%d", gen.InstanceID()),
+	} {
+		if !strings.Contains(line, want) {
+			t.Errorf("Creation log %q lacks %q", line, want)
+		}
+	}
+
+	logs.Reset()
+	hostnameReads = 0
+	NewGenerator(WithLogger(logger), WithRandomMachineID())
+	if hostnameReads != 0 || strings.Contains(logs.String(), "hostname=") {
+		t.Errorf("Random machine ID leaked host information: %q", logs.String())
+	}
+	if !strings.Contains(logs.String(), "machine_id_source=random") {
+		t.Errorf("Expected random source in %q", logs.String())
+	}
+
+	logs.Reset()
+	NewGenerator(WithLogger(logger), WithMachineID(7))
+	if !strings.Contains(logs.String(), "machine_id=7") || !strings.Contains(logs.String(), "machine_id_source=explicit") {
+		t.Errorf("Expected explicit machine ID 7 in %q", logs.String())
+	}
+
+	// Without a logger nothing is read or logged
+	hostnameReads = 0
+	NewGenerator(WithMachineID(7))
+	if hostnameReads != 0 {
+		t.Errorf("Hostname read without a logger")
+	}
+}
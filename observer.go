@@ -0,0 +1,69 @@
+// Package tsuniqid - Hooks for monitoring generation
+package tsuniqid
+
+import "sync/atomic"
+
+// Observer receives generation events from a generator configured with
+// WithObserver, for wiring to metrics such as Prometheus counters.
+//
+// Methods are called synchronously on the generating goroutine, possibly
+// from many goroutines at once, so they must be fast and safe for concurrent
+// use; incrementing an atomic counter is typical.
+type Observer interface {
+	// OnGenerate is called once for every uint64 ID generated, including
+	// the uint64 part of string IDs and each ID of a batch.
+	OnGenerate()
+
+	// OnCounterOverflow is called when the counter is exhausted within a
+	// millisecond: in the sequenced modes enabled by WithMonotonic and
+	// WithOverflowWait before waiting for the next millisecond; for batches
+	// that spill into later milliseconds; and otherwise once for each
+	// millisecond in which more IDs are generated than the counter holds,
+	// after which IDs within that millisecond may repeat. It is never
+	// called while the generation rate stays within the counter's capacity.
+	OnCounterOverflow()
+}
+
+// observeGenerate reports a generated ID to the observer, if any.
+func (g *IDGenerator) observeGenerate() {
+	if g.observer != nil {
+		g.observer.OnGenerate()
+	}
+}
+
+// observeOverflow reports an exhausted counter to the observer, if any.
+func (g *IDGenerator) observeOverflow() {
+	if g.observer != nil {
+		g.observer.OnCounterOverflow()
+	}
+}
+
+// observeWindow reports an overflow, once per timestamp, when the IDs
+// generated with the current free-running timestamp outnumber the counter
+// values available to it.
+//
+// With WithShardedCounter the IDs are compared with one shard's share of the
+// counter, the point from which a shard may have wrapped.
+//
+// Parameters:
+//   - timestamp: The timestamp of the IDs just generated
+func (g *IDGenerator) observeWindow(timestamp uint64) {
+	if atomic.LoadUint64(&g.windowTimestamp) != timestamp {
+		return
+	}
+	if g.counterValue()-atomic.LoadUint64(&g.windowBase) <= g.shardCapacity() {
+		return
+	}
+
+	// Stored as timestamp+1 so an overflow at timestamp 0 is reported too
+	for {
+		reported := atomic.LoadUint64(&g.overflowTimestamp)
+		if reported > timestamp {
+			return
+		}
+		if atomic.CompareAndSwapUint64(&g.overflowTimestamp, reported, timestamp+1) {
+			g.observer.OnCounterOverflow()
+			return
+		}
+	}
+}
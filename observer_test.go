@@ -0,0 +1,110 @@
+package tsuniqid
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// countingObserver counts the events it receives.
+type countingObserver struct {
+	generated  uint64
+	overflowed uint64
+}
+
+func (o *countingObserver) OnGenerate()        { atomic.AddUint64(&o.generated, 1) }
+func (o *countingObserver) OnCounterOverflow() { atomic.AddUint64(&o.overflowed, 1) }
+
+// TestWithObserver tests that generation and counter overflow events are
+// reported in the default and sequenced modes.
+func TestWithObserver(t *testing.T) {
+	pinned := time.Now()
+	observer := &countingObserver{}
+	gen := NewGenerator(WithObserver(observer), WithClock(func() time.Time { return pinned }))
+
+	for i := 0; i < 10; i++ {
+		gen.GenerateUint64ID()
+	}
+	gen.GenerateStringIDs(5)
+	if observer.generated != 15 || observer.overflowed != 0 {
+		t.Errorf("Expected 15 generated and no overflow, got %+v", *observer)
+	}
+
+	// Wrapping the counter within one millisecond is an overflow
+	for i := 0; i < MaxCounter; i++ {
+		gen.GenerateUint64ID()
+	}
+	if observer.overflowed != 1 {
+		t.Errorf("Expected one overflow after wrapping, got %d", observer.overflowed)
+	}
+
+	// So is a batch that spills into the next millisecond
+	observer = &countingObserver{}
+	clock := newFakeClock(pinned, time.Millisecond)
+	gen = NewGenerator(WithObserver(observer), WithClock(clock.Now))
+	gen.GenerateUint64IDs(MaxCounter + 10)
+	if observer.generated != MaxCounter+10 || observer.overflowed != 1 {
+		t.Errorf("Expected %d generated and one overflow, got %+v", MaxCounter+10, *observer)
+	}
+
+	// In the sequenced modes, an overflow is reported before each wait
+	observer = &countingObserver{}
+	clock = newFakeClock(pinned, 10*time.Nanosecond)
+	gen = NewGenerator(WithObserver(observer), WithClock(clock.Now), WithOverflowWait())
+	for i := 0; i < 3*MaxCounter; i++ {
+		gen.GenerateUint64ID()
+	}
+	if observer.generated != 3*MaxCounter || observer.overflowed == 0 {
+		t.Errorf("Expected %d generated and overflows, got %+v", 3*MaxCounter, *observer)
+	}
+
+	if _, err := NewGeneratorWithError(WithObserver(nil)); err == nil {
+		t.Errorf("Expected error for nil observer")
+	}
+}
+
+// TestWithObserver_LowRate tests that a generator issuing far fewer IDs per
+// millisecond than its counter holds never reports an overflow, however many
+// IDs it generates in total.
+func TestWithObserver_LowRate(t *testing.T) {
+	for _, opts := range [][]Option{nil, {WithShardedCounter(4)}} {
+		observer := &countingObserver{}
+		clock := newFakeClock(time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC), time.Second)
+		gen := NewGenerator(append(opts, WithObserver(observer), WithClock(clock.Now))...)
+
+		for i := 0; i < 3*(MaxCounter+1); i++ {
+			gen.GenerateUint64ID()
+		}
+		if observer.overflowed != 0 {
+			t.Errorf("Expected no overflow at one ID per second, got %d", observer.overflowed)
+		}
+	}
+}
+
+// TestWithObserver_NoTracking tests that only generators with an observer
+// track each timestamp's counter usage for overflow detection.
+func TestWithObserver_NoTracking(t *testing.T) {
+	pinned := time.Now()
+	for _, observed := range []bool{false, true} {
+		opts := []Option{WithClock(func() time.Time { return pinned })}
+		if observed {
+			opts = append(opts, WithObserver(&countingObserver{}))
+		}
+		gen := NewGenerator(opts...)
+		gen.GenerateUint64ID()
+
+		if tracked := atomic.LoadUint64(&gen.windowTimestamp) != 0; tracked != observed {
+			t.Errorf("Observer %v: expected tracking %v, got %v", observed, observed, tracked)
+		}
+	}
+}
+
+// BenchmarkGenerateUint64ID_Observer benchmarks generation with an observer
+// counting events.
+func BenchmarkGenerateUint64ID_Observer(b *testing.B) {
+	gen := NewGenerator(WithObserver(&countingObserver{}))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = gen.GenerateUint64ID()
+	}
+}
@@ -0,0 +1,34 @@
+// Package tsuniqid - One-off IDs without a generator
+package tsuniqid
+
+import (
+	"encoding/binary"
+	"math/rand"
+	"time"
+)
+
+// oneShotRandomMask selects the machine, instance and counter fields, which
+// OneShotID fills with random bits
+const oneShotRandomMask = (MaxMachineID << MachineIDShift) | (MaxInstanceID << InstanceIDShift) | MaxCounter
+
+// OneShotID creates a uint64 identifier in the default layout without a
+// generator, for scripts that need a single ID. It never touches the global
+// Generator, the hostname or the network.
+//
+// The timestamp field holds the current time, and the machine, instance and
+// counter fields hold 22 random bits from crypto/rand (falling back to
+// math/rand if the system entropy source fails). Uniqueness is therefore only
+// probabilistic: two calls within the same millisecond collide with
+// probability 1 in about 4 million, and the decoded machine and instance IDs
+// are meaningless. Use a generator when IDs must be guaranteed unique.
+//
+// Returns: A reasonably unique uint64 identifier
+func OneShotID() uint64 {
+	var buf [8]byte
+	if _, err := cryptoRead(buf[:]); err != nil {
+		binary.BigEndian.PutUint64(buf[:], rand.Uint64())
+	}
+
+	timestamp := uint64(time.Now().UnixMilli()) & MaxTimestamp
+	return (timestamp << TimestampShift) | (binary.BigEndian.Uint64(buf[:]) & oneShotRandomMask)
+}
@@ -0,0 +1,35 @@
+package tsuniqid
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestOneShotID tests that consecutive one-off IDs differ and carry the
+// current time.
+func TestOneShotID(t *testing.T) {
+	before := time.Now().Truncate(time.Millisecond)
+	first, second := OneShotID(), OneShotID()
+	after := time.Now()
+
+	if first == second {
+		t.Errorf("Consecutive one-shot IDs are equal: %d", first)
+	}
+
+	for _, id := range []uint64{first, second} {
+		if ts := DecodeUint64(id).Time(); ts.Before(before) || ts.After(after) {
+			t.Errorf("Decoded timestamp %v not between %v and %v", ts, before, after)
+		}
+	}
+}
+
+// TestOneShotID_EntropyFailure tests the math/rand fallback.
+func TestOneShotID_EntropyFailure(t *testing.T) {
+	defer func(old func([]byte) (int, error)) { cryptoRead = old }(cryptoRead)
+	cryptoRead = func([]byte) (int, error) { return 0, errors.New("no entropy") }
+
+	if OneShotID() == OneShotID() {
+		t.Errorf("Consecutive one-shot IDs are equal without crypto/rand")
+	}
+}
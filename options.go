@@ -0,0 +1,896 @@
+// Package tsuniqid - Functional options for configuring generators
+package tsuniqid
+
+import (
+	"fmt"
+	"log/slog"
+	"math/bits"
+	"math/rand"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Option configures an IDGenerator at construction time.
+// Options are applied in order by NewGenerator and return an error when
+// their argument is invalid.
+type Option func(*IDGenerator) error
+
+// validateOptions checks that the options applied to a generator are
+// compatible with each other.
+//
+// Returns: An error describing the first incompatibility found
+func (g *IDGenerator) validateOptions() error {
+	if g.maxCounter() == 0 {
+		return fmt.Errorf("layout with %d counter bits leaves no room for the counter", g.layout.CounterBits)
+	}
+	if g.parity && g.layout.WatermarkBits > 0 {
+		return fmt.Errorf("parity bit conflicts with the watermark field")
+	}
+
+	// Reserved bits may have shrunk fields after other options were applied
+	if g.machineIDSet && g.machineID > g.layout.maxMachineID() {
+		return fmt.Errorf("machine ID %d exceeds maximum %d", g.machineID, g.layout.maxMachineID())
+	}
+	if g.instanceIDSet && g.instanceID > g.layout.maxInstanceID() {
+		return fmt.Errorf("instance ID %d exceeds maximum %d", g.instanceID, g.layout.maxInstanceID())
+	}
+	if g.spreadBits > g.layout.MachineBits+g.layout.InstanceBits {
+		return fmt.Errorf("%d spread bits exceed the %d machine and instance bits", g.spreadBits, g.layout.MachineBits+g.layout.InstanceBits)
+	}
+	if g.spreadBits > 0 && g.interleave {
+		return fmt.Errorf("insert spreading conflicts with bit interleaving")
+	}
+	if g.parity && g.interleave {
+		return fmt.Errorf("parity bit conflicts with bit interleaving")
+	}
+	if g.relativeTimestamp && g.epoch != 0 {
+		return fmt.Errorf("relative timestamp conflicts with a custom epoch")
+	}
+	if g.layout.ReservedBits > 0 && !g.relativeTimestamp && g.timestamp() > g.layout.maxTimestamp() {
+		return fmt.Errorf("%d-bit timestamp cannot represent the current time; use WithEpoch", g.layout.TimestampBits)
+	}
+	if g.resolution != time.Millisecond && !g.relativeTimestamp && g.timestamp() > g.layout.maxTimestamp() {
+		return fmt.Errorf("%d-bit timestamp cannot represent the current time in %v units; use a layout with more timestamp bits", g.layout.TimestampBits, g.resolution)
+	}
+	if g.shards != nil && g.shardedCounterBits() == 0 {
+		return fmt.Errorf("%d counter shards leave no room for the counter", len(g.shards))
+	}
+	if g.checkedSuffix && g.suffixLength < 1 {
+		return fmt.Errorf("checked suffix needs a suffix length of at least 1")
+	}
+	if g.checkedSuffix && g.charSet != CharSet {
+		return fmt.Errorf("checked suffix conflicts with a custom character set")
+	}
+	if g.filenameSafe {
+		if err := checkFilenameSafe(g.charSet, HexIDLength+g.suffixLength); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WithUnsafeSingleThreaded disables the mutex that guards the random number
+// generator used for string ID suffixes.
+//
+// This removes locking overhead for single-goroutine batch jobs, but the
+// resulting generator is NOT safe for concurrent use: calling any string ID
+// method from more than one goroutine is a data race.
+//
+// Returns: An Option that disables suffix locking
+func WithUnsafeSingleThreaded() Option {
+	return func(g *IDGenerator) error {
+		g.singleThreaded = true
+		return nil
+	}
+}
+
+// WithBootNonce mixes a boot nonce (or generation epoch) into the instance ID.
+//
+// When a decommissioned node's machine ID is reassigned, the new node could
+// otherwise reproduce historical IDs that share the same timestamp and
+// counter. Supplying a nonce that changes on every reassignment, such as a
+// persisted boot counter, XORs it into each auto-assigned instance ID. Only
+// the low 4 bits of the nonce take effect, so up to 16 consecutive nonces
+// are distinct.
+//
+// The nonce only changes which instance ID each generator receives, not the
+// set of instance IDs a process uses. The n-th generator created on the new
+// node gets different instance bits from the n-th generator the old node
+// created, so nodes running one generator per process never share an
+// instance ID; but a later generator on the new node can still take an
+// instance ID one of the old node's generators held, and then reproduce its
+// IDs. Explicit instance IDs set with WithInstanceID ignore the nonce.
+//
+// Parameters:
+//   - nonce: The boot nonce to mix into the instance ID
+//
+// Returns: An Option that sets the boot nonce
+func WithBootNonce(nonce uint64) Option {
+	return func(g *IDGenerator) error {
+		g.bootNonce = nonce
+		return nil
+	}
+}
+
+// WithPIDInstanceID folds a hash of the process ID into the instance ID, like
+// a boot nonce (see WithBootNonce), so independently started processes on
+// one machine, which would otherwise all start from the same instance
+// counter value, get different instance bits.
+//
+// The instance ID field is only 4 bits wide in the default layout, so this
+// lowers collisions rather than ruling them out: two processes on one
+// machine share an instance ID with probability 1/16, and n processes
+// collide with the probability given by MachineIDCollisionProbability(n),
+// which passes 50% at 5 processes. Assign instance IDs explicitly with
+// WithInstanceID when that matters. An explicit instance ID ignores this
+// option.
+//
+// Returns: An Option that mixes the process ID into the instance ID
+func WithPIDInstanceID() Option {
+	return func(g *IDGenerator) error {
+		g.pidNonce = true
+		return nil
+	}
+}
+
+// WithMachineID sets a deterministic machine ID instead of deriving one from
+// the hostname and local IP. This suits containerized environments where
+// hostnames are random and IPs are shared, letting the orchestration layer
+// assign stable machine IDs.
+//
+// Values above MaxMachineID (or the layout's machine ID width) are masked by
+// NewGenerator and rejected by NewGeneratorWithError.
+//
+// Parameters:
+//   - id: The machine ID, in the range [0, MaxMachineID]
+//
+// Returns: An Option that sets the machine ID
+func WithMachineID(id uint64) Option {
+	return func(g *IDGenerator) error {
+		maxMachineID := g.layout.maxMachineID()
+		g.machineID = id & maxMachineID
+		g.machineIDSet = true
+
+		if id > maxMachineID {
+			return fmt.Errorf("machine ID %d exceeds maximum %d", id, maxMachineID)
+		}
+		return nil
+	}
+}
+
+// WithMACMachineID derives the machine ID from the first non-loopback
+// hardware (MAC) address instead of the hostname and local IP. MAC addresses
+// stay the same across reboots and DHCP changes, giving a more stable
+// machine ID. If no hardware address is available, the hostname and IP are
+// used as usual. WithMachineID takes precedence over this option.
+//
+// Returns: An Option that derives the machine ID from the MAC address
+func WithMACMachineID() Option {
+	return func(g *IDGenerator) error {
+		g.machineIDFunc = generateMACMachineID
+		g.machineSource = "mac"
+		return nil
+	}
+}
+
+// WithHostnameMachineID derives the machine ID from the hostname alone
+// instead of the hostname and local IP. On container platforms that set the
+// hostname from a stable pod name but assign a fresh IP on every restart,
+// this keeps the machine ID the same across restarts. If the hostname is
+// empty, a random machine ID is used. WithMachineID takes precedence over
+// this option.
+//
+// Returns: An Option that derives the machine ID from the hostname
+func WithHostnameMachineID() Option {
+	return func(g *IDGenerator) error {
+		g.machineIDFunc = generateHostnameMachineID
+		g.machineSource = "hostname"
+		return nil
+	}
+}
+
+// WithRandomMachineID draws the machine ID from crypto/rand at construction
+// instead of deriving it from the hostname and local IP, for privacy-
+// sensitive deployments where IDs must not reveal anything about the host.
+// The hostname and network interfaces are never read.
+//
+// This trades stability for privacy: the machine ID changes on every
+// restart, and with only 16 machine IDs in the default layout, two hosts of
+// even a small fleet are likely to share one (see
+// MachineIDCollisionProbability). WithMachineID takes precedence over this
+// option.
+//
+// Returns: An Option that draws a random machine ID
+func WithRandomMachineID() Option {
+	return func(g *IDGenerator) error {
+		g.machineIDFunc = generateRandomMachineID
+		g.machineSource = "random"
+		return nil
+	}
+}
+
+// WithInstanceID sets the instance ID instead of drawing one from the
+// process-wide instance counter. This lets multi-process deployments assign
+// instance IDs from an external source, such as a Redis counter or a pod
+// ordinal, so they are stable across restarts and coordinated across
+// processes. The boot nonce is not mixed into an explicit instance ID.
+//
+// Values above MaxInstanceID (or the layout's instance ID width) are masked
+// by NewGenerator and rejected by NewGeneratorWithError.
+//
+// Parameters:
+//   - id: The instance ID, in the range [0, MaxInstanceID]
+//
+// Returns: An Option that sets the instance ID
+func WithInstanceID(id uint64) Option {
+	return func(g *IDGenerator) error {
+		maxInstanceID := g.layout.maxInstanceID()
+		g.instanceID = id & maxInstanceID
+		g.instanceIDSet = true
+
+		if id > maxInstanceID {
+			return fmt.Errorf("instance ID %d exceeds maximum %d", id, maxInstanceID)
+		}
+		return nil
+	}
+}
+
+// WithK8sPodInstanceID uses the ordinal of a Kubernetes StatefulSet pod as
+// the instance ID. StatefulSet pods are named after their ordinal, such as
+// "web-3", so the trailing number of the hostname gives each replica a
+// stable instance ID across restarts without external coordination.
+//
+// The ordinal is masked to MaxInstanceID (or the layout's instance ID
+// width), so StatefulSets with more than 16 replicas reuse instance IDs and
+// should also vary the machine ID. If the hostname has no trailing ordinal,
+// the instance ID is assigned from the process-wide counter as usual.
+//
+// Returns: An Option that derives the instance ID from the pod ordinal
+func WithK8sPodInstanceID() Option {
+	return func(g *IDGenerator) error {
+		hostname, err := osHostname()
+		if err != nil {
+			return nil
+		}
+
+		if ordinal, ok := podOrdinal(hostname); ok {
+			g.instanceID = ordinal & g.layout.maxInstanceID()
+			g.instanceIDSet = true
+		}
+		return nil
+	}
+}
+
+// podOrdinal parses the ordinal from a StatefulSet pod name of the form
+// "<name>-<ordinal>".
+//
+// Parameters:
+//   - hostname: The pod's hostname
+//
+// Returns:
+//   - uint64: The ordinal
+//   - bool: False if the hostname does not end in "-<ordinal>"
+func podOrdinal(hostname string) (uint64, bool) {
+	i := strings.LastIndexByte(hostname, '-')
+	if i < 0 {
+		return 0, false
+	}
+
+	ordinal, err := strconv.ParseUint(hostname[i+1:], 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return ordinal, true
+}
+
+// WithSlugGroupSize sets the number of characters per hyphen-separated group
+// in slugs created by GenerateSlug. The default is DefaultSlugGroupSize.
+//
+// Parameters:
+//   - size: The number of characters per group; must be positive
+//
+// Returns: An Option that sets the slug group size
+func WithSlugGroupSize(size int) Option {
+	return func(g *IDGenerator) error {
+		if size <= 0 {
+			return fmt.Errorf("slug group size %d must be positive", size)
+		}
+
+		g.slugGroupSize = size
+		return nil
+	}
+}
+
+// WithSuffixLength sets the length of the random suffix appended to string
+// IDs. The default is RandomSuffixLength; zero disables the suffix.
+//
+// Lengths above MaxUsefulSuffixLength are allowed but log a warning through
+// the logger set by WithLogger, since the extra characters only make IDs
+// longer.
+//
+// Parameters:
+//   - length: The suffix length; must not be negative
+//
+// Returns: An Option that sets the suffix length
+func WithSuffixLength(length int) Option {
+	return func(g *IDGenerator) error {
+		if length < 0 {
+			return fmt.Errorf("suffix length %d must not be negative", length)
+		}
+
+		g.suffixLength = length
+		return nil
+	}
+}
+
+// WithCharSet sets the characters random suffixes are drawn from instead of
+// CharSet, for example adding uppercase letters for more entropy per
+// character in case-sensitive contexts, or restricting suffixes to digits.
+// Characters are indexed as bytes, so the set is limited to ASCII.
+//
+// ValidStringID and VerifyCheckedSuffix assume CharSet, so a custom set
+// cannot be combined with WithCheckedSuffix.
+//
+// Parameters:
+//   - charset: The suffix characters; must be non-empty ASCII without duplicates
+//
+// Returns: An Option that sets the suffix character set
+func WithCharSet(charset string) Option {
+	return func(g *IDGenerator) error {
+		if charset == "" {
+			return fmt.Errorf("character set must not be empty")
+		}
+
+		var seen [utf8.RuneSelf]bool
+		for i := 0; i < len(charset); i++ {
+			c := charset[i]
+			if c >= utf8.RuneSelf {
+				return fmt.Errorf("character set %q contains a non-ASCII character", charset)
+			}
+			if seen[c] {
+				return fmt.Errorf("character set %q contains duplicate character %q", charset, c)
+			}
+			seen[c] = true
+		}
+
+		g.charSet = charset
+		return nil
+	}
+}
+
+// WithCheckedSuffix makes the last character of the random suffix of hex
+// string IDs a check character computed over the rest of the ID, so
+// VerifyCheckedSuffix can detect any single mistyped character. The suffix
+// keeps its length, leaving one random character fewer.
+//
+// This applies to GenerateStringID, GenerateFixedStringID and
+// GenerateStringIDs. The check is computed over CharSet, so other string
+// formats such as base62 IDs are not covered.
+//
+// Returns: An Option that enables the check character
+func WithCheckedSuffix() Option {
+	return func(g *IDGenerator) error {
+		g.checkedSuffix = true
+		return nil
+	}
+}
+
+// WithRandSource builds the random number generator for string ID suffixes
+// from src instead of a source seeded with the current time, so a seeded
+// source makes suffixes reproducible in tests and deterministic simulations.
+//
+// This makes suffixes predictable: do not use it for IDs that must be hard
+// to guess. It has no effect on suffixes drawn by WithCryptoRandomSuffix,
+// and Reset replaces the source with a time-seeded one.
+//
+// Parameters:
+//   - src: The random source; must not be nil
+//
+// Returns: An Option that sets the random source
+func WithRandSource(src rand.Source) Option {
+	return func(g *IDGenerator) error {
+		if src == nil {
+			return fmt.Errorf("random source must not be nil")
+		}
+
+		g.rng = rand.New(src)
+		return nil
+	}
+}
+
+// WithSeed is WithRandSource with a source seeded by seed, for reproducible
+// suffixes. The same caveats apply.
+//
+// Parameters:
+//   - seed: The seed for the random source
+//
+// Returns: An Option that sets a seeded random source
+func WithSeed(seed int64) Option {
+	return WithRandSource(rand.NewSource(seed))
+}
+
+// WithObserver reports generation events to o, such as every generated ID
+// and every counter overflow, for monitoring how close a generator runs to
+// counter exhaustion. To detect overflows, a generator with an observer
+// tracks how much of each timestamp's counter is used, at the cost of an
+// atomic compare-and-swap per ID. Without an observer, generation only pays
+// for a nil check.
+//
+// Parameters:
+//   - o: The observer; must not be nil
+//
+// Returns: An Option that sets the observer
+func WithObserver(o Observer) Option {
+	return func(g *IDGenerator) error {
+		if o == nil {
+			return fmt.Errorf("observer must not be nil")
+		}
+
+		// Overflows are detected through the per-timestamp tracking
+		g.observer = o
+		g.windowTracking = 1
+		return nil
+	}
+}
+
+// WithLogger sets the logger that receives warnings about the generator's
+// configuration and behavior. By default nothing is logged.
+//
+// At construction the generator also logs one Info line with its machine
+// ID, instance ID, how the machine ID was chosen and, for machine IDs
+// derived from the host, the hostname and IP, which makes it easy to audit
+// which hosts share a machine ID.
+//
+// Parameters:
+//   - logger: The logger; must not be nil
+//
+// Returns: An Option that sets the logger
+func WithLogger(logger *slog.Logger) Option {
+	return func(g *IDGenerator) error {
+		if logger == nil {
+			return fmt.Errorf("logger must not be nil")
+		}
+
+		g.logger = logger
+		return nil
+	}
+}
+
+// WithEpoch sets a custom epoch so the stored timestamp is the number of
+// milliseconds since t rather than since the Unix epoch. A recent epoch
+// keeps the 42-bit timestamp field from wasting its range on decades that
+// have already passed.
+//
+// IDs only record the offset from the epoch, so decoding them requires the
+// same epoch (see IDGenerator.Decode and DecodeUint64WithEpoch). Mixing IDs
+// from generators with different epochs is unsafe: they neither sort
+// chronologically nor are guaranteed to be unique.
+//
+// Parameters:
+//   - t: The epoch; must not be in the future
+//
+// Returns: An Option that sets the epoch
+func WithEpoch(t time.Time) Option {
+	return func(g *IDGenerator) error {
+		if t.After(time.Now()) {
+			return fmt.Errorf("epoch %v is in the future", t)
+		}
+
+		g.epoch = t.UnixMilli()
+		return nil
+	}
+}
+
+// WithRelativeTimestamp stores the timestamp as milliseconds since the
+// generator was constructed, for short-lived generators (per request, say)
+// whose layout has only a small timestamp field (see NewGeneratorWithLayout).
+// It is WithEpoch with the construction time as the epoch, read from the
+// clock set by WithClock.
+//
+// IDs only record the offset, so decoding them requires the base returned
+// by CreatedAt (see TimeOf, IDGenerator.Decode and DecodeUint64WithEpoch).
+// Once the offset no longer fits the timestamp field, it wraps and a warning
+// is logged through the logger set by WithLogger; replace the generator
+// before then.
+//
+// Returns: An Option that enables relative timestamps
+func WithRelativeTimestamp() Option {
+	return func(g *IDGenerator) error {
+		g.relativeTimestamp = true
+		return nil
+	}
+}
+
+// WithTimestampResolution sets the unit of the timestamp field to
+// time.Millisecond (the default) or time.Microsecond. With microseconds the
+// counter restarts a thousand times as often, so bursts spread over many
+// more timestamps before the counter is exhausted.
+//
+// Microsecond timestamps need more bits: since the Unix epoch they need 51,
+// so the default 42-bit layout only works with WithRelativeTimestamp, for
+// about 50 days. Take the bits from the counter with NewGeneratorWithLayout,
+// for example a 48-bit timestamp and an 8-bit counter, which with a recent
+// WithEpoch lasts about 8 years and still allows 256 IDs per microsecond.
+// The generator returns an error if its layout cannot represent the current
+// time.
+//
+// IDs do not record their resolution, so decoding them requires knowing it:
+// IDGenerator.Decode and TimeOf use the generator's resolution, while the
+// package-level decoders assume milliseconds.
+//
+// Parameters:
+//   - resolution: time.Millisecond or time.Microsecond
+//
+// Returns: An Option that sets the timestamp resolution
+func WithTimestampResolution(resolution time.Duration) Option {
+	return func(g *IDGenerator) error {
+		if resolution != time.Millisecond && resolution != time.Microsecond {
+			return fmt.Errorf("timestamp resolution %v must be 1ms or 1µs", resolution)
+		}
+
+		g.resolution = resolution
+		return nil
+	}
+}
+
+// WithClock sets the time source used for timestamps, replacing time.Now.
+// This lets tests pin or step time deterministically, assert exact timestamp
+// bits, and simulate clock rollback. The clock is called directly on every
+// generation, so the default time.Now adds no overhead.
+//
+// Parameters:
+//   - clock: The function returning the current time; must not be nil
+//
+// Returns: An Option that sets the clock
+func WithClock(clock func() time.Time) Option {
+	return func(g *IDGenerator) error {
+		if clock == nil {
+			return fmt.Errorf("clock must not be nil")
+		}
+
+		g.clock = clock
+		g.customClock = true
+		return nil
+	}
+}
+
+// WithCachedClock reads the clock in a background goroutine every interval
+// and lets generation read the stored time instead of calling the clock (by
+// default time.Now) for every ID, which takes a large share of the time of
+// GenerateUint64ID. Call Close to stop the goroutine when the generator is
+// no longer needed.
+//
+// The tradeoff is granularity: timestamps lag the clock by up to one
+// interval and advance in steps of the interval. Keep the interval at or
+// below the timestamp resolution so that every millisecond still gets its
+// own timestamp; with a longer interval, consecutive milliseconds share one
+// timestamp and its counter values. It also applies to the other formats
+// that read the generator's clock, such as GenerateULID.
+//
+// Parameters:
+//   - interval: How often to refresh the stored time; must be positive
+//
+// Returns: An Option that enables the cached clock
+func WithCachedClock(interval time.Duration) Option {
+	return func(g *IDGenerator) error {
+		if interval <= 0 {
+			return fmt.Errorf("cached clock interval %v must be positive", interval)
+		}
+
+		g.cachedClockInterval = interval
+		return nil
+	}
+}
+
+// WithMonotonic guarantees that each ID from the generator is strictly
+// greater than the previous one, making IDs usable as sortable keys.
+//
+// In this mode the counter restarts every millisecond. If the wall clock
+// moves backward (for example after an NTP adjustment) the generator keeps
+// using the last emitted timestamp and relies on the counter. If the counter
+// is exhausted within that frozen millisecond, generation blocks until the
+// clock catches up, which may take as long as the clock regression.
+//
+// Generation is serialized by a mutex in this mode.
+//
+// Returns: An Option that enables monotonic mode
+func WithMonotonic() Option {
+	return func(g *IDGenerator) error {
+		g.monotonic = true
+		return nil
+	}
+}
+
+// WithOverflowWait makes counter exhaustion block instead of wrapping.
+//
+// By default the 14-bit counter silently wraps, so generating more than
+// MaxCounter+1 IDs within one millisecond produces duplicates. With this
+// option the counter restarts every millisecond, and once it is exhausted
+// the generator waits for the clock to advance before issuing more IDs,
+// making uniqueness a hard guarantee under load.
+//
+// Generation is serialized by a mutex in this mode.
+//
+// Returns: An Option that enables waiting on counter overflow
+func WithOverflowWait() Option {
+	return func(g *IDGenerator) error {
+		g.overflowWait = true
+		return nil
+	}
+}
+
+// WithCryptoRandomSuffix draws string ID suffixes from crypto/rand instead of
+// math/rand, making them unpredictable enough for tokens that must not be
+// guessed, such as password reset links.
+//
+// Random bytes are buffered internally to avoid a system call per character.
+// If the system entropy source fails, the generator falls back to its
+// math/rand source rather than failing.
+//
+// Returns: An Option that enables crypto/rand suffixes
+func WithCryptoRandomSuffix() Option {
+	return func(g *IDGenerator) error {
+		g.cryptoSuffix = true
+		g.cryptoBuf = make([]byte, cryptoBufferSize)
+		g.cryptoPos = cryptoBufferSize
+		return nil
+	}
+}
+
+// WithParity reserves the lowest bit of every uint64 ID as an even parity bit
+// computed over the other 63 bits, so receivers can detect single-bit errors
+// with CheckParity.
+//
+// The counter moves up to bits 13-1, halving the number of IDs a generator
+// can issue per millisecond to 8192. The parity bit cannot be combined with
+// WithBitInterleaving, which mixes bit 0 with the others.
+//
+// Returns: An Option that enables the parity bit
+func WithParity() Option {
+	return func(g *IDGenerator) error {
+		g.parity = true
+		return nil
+	}
+}
+
+// WithEnvironment tags every uint64 ID with the environment it was generated
+// in, so services can cheaply reject IDs from another environment with
+// EnvironmentOf.
+//
+// The tag occupies bits 13-12, shrinking the counter to 12 bits and the
+// number of IDs a generator can issue per millisecond to 4096.
+//
+// Parameters:
+//   - env: The environment tag; one of the Environment constants
+//
+// Returns: An Option that sets the environment tag
+func WithEnvironment(env Environment) Option {
+	return func(g *IDGenerator) error {
+		if env < EnvironmentProduction || env > MaxEnvironment {
+			return fmt.Errorf("invalid environment %d", env)
+		}
+
+		g.environment = env
+		return nil
+	}
+}
+
+// WithServiceCode tags every uint64 ID with a code identifying the service
+// that generated it, so IDs from several services in a shared table can be
+// filtered with ServiceCodeOf without a join.
+//
+// The code occupies bits 11-8 and bits 13-12 stay reserved for the
+// environment tag, shrinking the counter to 8 bits and the number of IDs a
+// generator can issue per millisecond to 256.
+//
+// Parameters:
+//   - code: The service code, at most MaxServiceCode
+//
+// Returns: An Option that sets the service code
+func WithServiceCode(code uint64) Option {
+	return func(g *IDGenerator) error {
+		if code > MaxServiceCode {
+			return fmt.Errorf("service code %d exceeds maximum %d", code, MaxServiceCode)
+		}
+
+		g.serviceCode = code
+		g.serviceCodeSet = true
+		return nil
+	}
+}
+
+// WithShardedCounter spreads the counter over several independent shards to
+// reduce contention on the atomic counter when many goroutines generate IDs
+// at once on many processors. Each processor draws from its own shard,
+// handed out round-robin through a sync.Pool, and the shard index is stored
+// in the highest counter bits so shards never produce the same ID. With
+// more processors than shards, processors share shards.
+//
+// The tradeoff is per-millisecond capacity. Each shard counts through its
+// share of the counter bits left after tags and parity, 2^14/shards values in
+// the plain default layout: 4096 with 4 shards and 1024 with 16. Once the IDs
+// drawn from one shard within a millisecond exceed that share, the shard
+// wraps and repeats IDs of that millisecond, even if the generator as a whole
+// is far below MaxIDsPerMillisecond; a single busy goroutine keeps drawing
+// from one shard. Keep each processor's rate below the per-shard share. The
+// sequenced modes enabled by WithMonotonic and WithOverflowWait do not use
+// the shards.
+//
+// Parameters:
+//   - shards: The number of shards; a power of two, where 1 disables sharding
+//
+// Returns: An Option that enables the sharded counter
+func WithShardedCounter(shards int) Option {
+	return func(g *IDGenerator) error {
+		if shards < 1 || shards&(shards-1) != 0 {
+			return fmt.Errorf("shard count %d is not a positive power of two", shards)
+		}
+
+		g.shards, g.shardBits = nil, 0
+		if shards > 1 {
+			g.shards = make([]shardCounter, shards)
+			g.shardBits = uint(bits.TrailingZeros(uint(shards)))
+			g.shardTokens.New = g.newShardToken
+		}
+		return nil
+	}
+}
+
+// WithCounterStart starts the counter at the given offset instead of 0. When
+// several generators share a machine and instance ID, which the 4-bit fields
+// make hard to avoid, counters that all start at 0 produce the same IDs in
+// their first milliseconds; a start drawn at random spreads them apart.
+//
+// This reduces collisions among same-identity generators but does not
+// eliminate them: the counters can still meet, and do whenever their offsets
+// match. The start is masked to the counter width available after the other
+// options, such as WithEnvironment, have taken their bits. Reset returns the
+// counter to this start. Only the free-running counter is offset; the
+// sequenced modes enabled by WithMonotonic and WithOverflowWait, and the
+// shards of WithShardedCounter, keep their own counters.
+//
+// Parameters:
+//   - start: The initial counter value, such as a random number
+//
+// Returns: An Option that sets the counter start
+func WithCounterStart(start uint64) Option {
+	return func(g *IDGenerator) error {
+		g.counterStart = start
+		return nil
+	}
+}
+
+// WithTestMachineIndex derives the machine ID from a test-provided node index
+// instead of the real hostname and IP, so in-process "nodes" in integration
+// tests get distinct, reproducible machine IDs. The index is masked to the
+// machine ID space, so indexes 0 through MaxMachineID are all distinct.
+//
+// Parameters:
+//   - index: The node index; must not be negative
+//
+// Returns: An Option that sets the machine ID from the index
+func WithTestMachineIndex(index int) Option {
+	return func(g *IDGenerator) error {
+		if index < 0 {
+			return fmt.Errorf("test machine index %d must not be negative", index)
+		}
+
+		g.machineID = uint64(index) & g.layout.maxMachineID()
+		g.machineIDSet = true
+		return nil
+	}
+}
+
+// WithFilenameSafe guarantees that string IDs are valid filenames on all
+// major platforms (Windows, macOS and Linux), and makes NewGeneratorWithError
+// reject any other option that would break that guarantee.
+//
+// See FilenameSafeCharSet and MaxFilenameLength for the exact rules.
+//
+// Returns: An Option that enforces filename-safe string IDs
+func WithFilenameSafe() Option {
+	return func(g *IDGenerator) error {
+		g.filenameSafe = true
+		return nil
+	}
+}
+
+// WithClockRollbackError makes GenerateUint64IDChecked return ErrClockRollback
+// when the system clock jumps backward by more than ClockRollbackTolerance,
+// so safety-critical services can fail loudly rather than issue IDs that may
+// duplicate earlier ones. GenerateUint64ID is unaffected.
+//
+// Returns: An Option that enables clock rollback errors
+func WithClockRollbackError() Option {
+	return func(g *IDGenerator) error {
+		g.rollbackError = true
+		return nil
+	}
+}
+
+// WithBitInterleaving passes every uint64 ID through InterleaveBits, so IDs
+// used directly as hash-table keys spread evenly across buckets instead of
+// clustering on the low counter bits. Interleaved IDs no longer sort by time.
+//
+// IDGenerator.Decode reverses the mixing automatically; package-level
+// decoders such as DecodeUint64 and EnvironmentOf must be given
+// DeinterleaveBits(id) instead. Interleaving conflicts with WithParity, whose
+// bit it would mix away.
+//
+// Returns: An Option that enables bit interleaving
+func WithBitInterleaving() Option {
+	return func(g *IDGenerator) error {
+		g.interleave = true
+		return nil
+	}
+}
+
+// WithInsertSpreading spreads consecutive uint64 IDs over buckets of the key
+// space, so write-heavy B-tree indexes do not concentrate every insert on
+// the rightmost leaf. The top bits of each ID, below any reserved bits, are
+// XORed with a hash of its timestamp and counter, which picks one of buckets
+// evenly sized ranges.
+//
+// The timestamp and counter fields stay in place, so DecodeUint64 still
+// recovers the generation time, and IDs remain unique because the hash is
+// reversible given the rest of the ID. Spread IDs no longer sort by time
+// and do not fit IDRangeForTime; use UnspreadID to recover the original IDs
+// and their order for range scans. IDGenerator.Decode unspreads
+// automatically, while package-level decoders of the machine and instance
+// IDs must be given UnspreadID(id).
+//
+// Parameters:
+//   - buckets: The number of buckets; a power of two, at most 256 in the
+//     default layout, where 1 disables spreading
+//
+// Returns: An Option that enables insert spreading
+func WithInsertSpreading(buckets int) Option {
+	return func(g *IDGenerator) error {
+		if buckets < 1 || buckets&(buckets-1) != 0 {
+			return fmt.Errorf("bucket count %d is not a positive power of two", buckets)
+		}
+
+		g.spreadBits = uint(bits.TrailingZeros(uint(buckets)))
+		return nil
+	}
+}
+
+// WithReservedHighBits keeps the top n bits of every uint64 ID zero, so that
+// applications can later OR their own flags into them. The bits are taken
+// from the machine ID field first, keeping at least one machine bit, and
+// then from the timestamp field.
+//
+// A shorter timestamp field covers a shorter time span: if it can no longer
+// represent the current time, NewGeneratorWithError fails and WithEpoch
+// should be used to move the epoch closer. IDs must be decoded with
+// IDGenerator.Decode, which knows the reduced layout.
+//
+// Parameters:
+//   - n: The number of high bits to reserve
+//
+// Returns: An Option that reserves high bits
+func WithReservedHighBits(n int) Option {
+	return func(g *IDGenerator) error {
+		if n < 0 {
+			return fmt.Errorf("reserved bit count %d must not be negative", n)
+		}
+
+		remaining := uint(n)
+		fromMachine := g.layout.MachineBits - 1
+		if fromMachine > remaining {
+			fromMachine = remaining
+		}
+		remaining -= fromMachine
+
+		if remaining >= g.layout.TimestampBits {
+			return fmt.Errorf("cannot reserve %d bits in layout %+v", n, g.layout)
+		}
+
+		g.layout.ReservedBits += uint(n)
+		g.layout.MachineBits -= fromMachine
+		g.layout.TimestampBits -= remaining
+		return nil
+	}
+}
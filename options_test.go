@@ -0,0 +1,499 @@
+package tsuniqid
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"math/rand"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeClock is a deterministic time source for tests. Every call to Now
+// returns the current time and then advances it by step.
+type fakeClock struct {
+	mu   sync.Mutex
+	now  time.Time
+	step time.Duration
+}
+
+// newFakeClock creates a fakeClock starting at start.
+func newFakeClock(start time.Time, step time.Duration) *fakeClock {
+	return &fakeClock{now: start, step: step}
+}
+
+// Now returns the current fake time and advances it by the step.
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now
+	c.now = c.now.Add(c.step)
+	return now
+}
+
+// Set moves the fake clock to t and changes its step.
+func (c *fakeClock) Set(t time.Time, step time.Duration) {
+	c.mu.Lock()
+	c.now = t
+	c.step = step
+	c.mu.Unlock()
+}
+
+// TestWithUnsafeSingleThreaded tests that a single-threaded generator still
+// produces well-formed, unique string IDs when used from one goroutine.
+func TestWithUnsafeSingleThreaded(t *testing.T) {
+	gen := NewGenerator(WithUnsafeSingleThreaded())
+	seen := make(map[string]bool)
+
+	for i := 0; i < 10000; i++ {
+		id := gen.GenerateStringID()
+
+		if seen[id] {
+			t.Errorf("Duplicate ID found: %s", id)
+		}
+		seen[id] = true
+
+		hexPart := id[:len(id)-RandomSuffixLength]
+		if _, err := strconv.ParseUint(hexPart, 16, 64); err != nil {
+			t.Errorf("Invalid hex part in ID %s: %v", id, err)
+		}
+	}
+}
+
+// BenchmarkGenerateRandomSuffix_Locked benchmarks suffix generation with the
+// default mutex-guarded random number generator.
+func BenchmarkGenerateRandomSuffix_Locked(b *testing.B) {
+	gen := NewGenerator()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = gen.generateRandomSuffix(RandomSuffixLength)
+	}
+}
+
+// BenchmarkGenerateRandomSuffix_Unlocked benchmarks suffix generation with
+// locking disabled via WithUnsafeSingleThreaded.
+func BenchmarkGenerateRandomSuffix_Unlocked(b *testing.B) {
+	gen := NewGenerator(WithUnsafeSingleThreaded())
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = gen.generateRandomSuffix(RandomSuffixLength)
+	}
+}
+
+// TestWithBootNonce tests that the n-th generators of two nodes sharing a
+// machine ID, but started with different boot nonces, get different instance
+// IDs and never collide even when their clocks and counters are
+// synchronized, while later generators can still reuse each other's
+// instance IDs, as documented.
+func TestWithBootNonce(t *testing.T) {
+	defer func(counter uint64) { atomic.StoreUint64(&globalInstanceCounter, counter) }(atomic.LoadUint64(&globalInstanceCounter))
+
+	// Simulate each node with a fresh registry and the same counter value,
+	// whose next four instance IDs are 0 to 3 before the nonce
+	const generators = 4
+	start := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	nodes := make([][]*IDGenerator, 2)
+	for node, nonce := range []uint64{1, 2} {
+		assignmentsMu.Lock()
+		assignments = make(map[Layout]map[Assignment]int)
+		assignmentsMu.Unlock()
+		atomic.StoreUint64(&globalInstanceCounter, MaxInstanceID)
+
+		for i := 0; i < generators; i++ {
+			gen := NewGenerator(WithMachineID(0x5), WithBootNonce(nonce),
+				WithClock(newFakeClock(start, time.Microsecond).Now))
+			defer gen.Close()
+			nodes[node] = append(nodes[node], gen)
+		}
+	}
+
+	oldInstances := make(map[uint64]bool)
+	for _, gen := range nodes[0] {
+		oldInstances[gen.InstanceID()] = true
+	}
+
+	reused := 0
+	for i := 0; i < generators; i++ {
+		oldGen, newGen := nodes[0][i], nodes[1][i]
+		if oldGen.InstanceID() == newGen.InstanceID() {
+			t.Fatalf("Generator %d of both nodes has instance ID %d", i, oldGen.InstanceID())
+		}
+		if oldInstances[newGen.InstanceID()] {
+			reused++
+		}
+
+		seen := make(map[uint64]bool)
+		for j := 0; j < 10000; j++ {
+			for _, id := range []uint64{oldGen.GenerateUint64ID(), newGen.GenerateUint64ID()} {
+				if seen[id] {
+					t.Fatalf("Collision between generator %d of both nodes: %d", i, id)
+				}
+				seen[id] = true
+			}
+		}
+	}
+
+	// XORing the nonce permutes the four instance IDs among themselves
+	if reused != generators {
+		t.Errorf("Expected the new node to reuse all %d of the old node's instance IDs, got %d", generators, reused)
+	}
+}
+
+// TestWithMachineID tests that an explicit machine ID is used as-is and that
+// out-of-range values are masked or rejected depending on the constructor.
+func TestWithMachineID(t *testing.T) {
+	gen, err := NewGeneratorWithError(WithMachineID(0xa))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	id := gen.GenerateUint64ID()
+	if machineID := (id >> MachineIDShift) & MaxMachineID; machineID != 0xa {
+		t.Errorf("Machine ID mismatch: got %d, expected %d", machineID, 0xa)
+	}
+
+	// Out-of-range values are rejected by NewGeneratorWithError
+	if gen, err := NewGeneratorWithError(WithMachineID(MaxMachineID + 1)); err == nil || gen != nil {
+		t.Errorf("Expected error for out-of-range machine ID, got generator %v", gen)
+	}
+
+	// NewGenerator masks them instead
+	gen = NewGenerator(WithMachineID(0x1f))
+	if gen.machineID != 0xf {
+		t.Errorf("Machine ID not masked: got %d, expected %d", gen.machineID, 0xf)
+	}
+}
+
+// TestWithInstanceID tests that an explicit instance ID is used as-is and that
+// generators sharing it on different machine IDs never collide.
+func TestWithInstanceID(t *testing.T) {
+	genA, err := NewGeneratorWithError(WithMachineID(1), WithInstanceID(5))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	genB := NewGenerator(WithMachineID(2), WithInstanceID(5))
+
+	seen := make(map[uint64]bool)
+	for i := 0; i < 10000; i++ {
+		// Keep both counters in lockstep
+		genA.counter = genB.counter
+
+		for _, id := range []uint64{genA.GenerateUint64ID(), genB.GenerateUint64ID()} {
+			if instanceID := (id >> InstanceIDShift) & MaxInstanceID; instanceID != 5 {
+				t.Fatalf("Instance ID mismatch: got %d, expected %d", instanceID, 5)
+			}
+			if seen[id] {
+				t.Fatalf("Collision between machine IDs: %d", id)
+			}
+			seen[id] = true
+		}
+	}
+
+	// Out-of-range values are rejected by NewGeneratorWithError
+	if gen, err := NewGeneratorWithError(WithInstanceID(MaxInstanceID + 1)); err == nil || gen != nil {
+		t.Errorf("Expected error for out-of-range instance ID, got generator %v", gen)
+	}
+
+	// NewGenerator masks them instead
+	if gen := NewGenerator(WithInstanceID(0x13)); gen.instanceID != 0x3 {
+		t.Errorf("Instance ID not masked: got %d, expected %d", gen.instanceID, 0x3)
+	}
+}
+
+// TestWithK8sPodInstanceID tests that StatefulSet ordinals become instance
+// IDs and that other hostnames fall back to the instance counter.
+func TestWithK8sPodInstanceID(t *testing.T) {
+	defer func(old func() (string, error)) { osHostname = old }(osHostname)
+
+	testCases := []struct {
+		hostname string
+		ordinal  bool
+		expected uint64
+	}{
+		{hostname: "web-0", ordinal: true, expected: 0},
+		{hostname: "web-15", ordinal: true, expected: 15},
+		{hostname: "web-21", ordinal: true, expected: 21 & MaxInstanceID},
+		{hostname: "my-app-db-7", ordinal: true, expected: 7},
+		{hostname: "no-ordinal", ordinal: false},
+		{hostname: "web-", ordinal: false},
+		{hostname: "web", ordinal: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.hostname, func(t *testing.T) {
+			hostname := tc.hostname
+			osHostname = func() (string, error) { return hostname, nil }
+
+			gen, err := NewGeneratorWithError(WithMachineID(1), WithK8sPodInstanceID())
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if gen.instanceIDSet != tc.ordinal {
+				t.Errorf("Ordinal detected: got %v, expected %v", gen.instanceIDSet, tc.ordinal)
+			}
+			if tc.ordinal && gen.InstanceID() != tc.expected {
+				t.Errorf("Instance ID mismatch: got %d, expected %d", gen.InstanceID(), tc.expected)
+			}
+		})
+	}
+
+	osHostname = func() (string, error) { return "", errors.New("no hostname") }
+	if gen, err := NewGeneratorWithError(WithK8sPodInstanceID()); err != nil || gen.instanceIDSet {
+		t.Errorf("Expected fallback without a hostname, got error %v", err)
+	}
+}
+
+// TestWithCounterStart tests that the counter starts at the masked offset,
+// including after Reset.
+func TestWithCounterStart(t *testing.T) {
+	gen := NewGenerator(WithMachineID(1), WithInstanceID(1), WithCounterStart(1000))
+	if counter := gen.GenerateUint64ID() & MaxCounter; counter != 1001 {
+		t.Errorf("First counter: got %d, expected 1001", counter)
+	}
+
+	gen.Reset()
+	if counter := gen.GenerateUint64ID() & MaxCounter; counter != 1001 {
+		t.Errorf("First counter after Reset: got %d, expected 1001", counter)
+	}
+
+	// The start is masked to the counter width
+	gen = NewGenerator(WithMachineID(1), WithInstanceID(1), WithCounterStart(MaxCounter+1+7))
+	if counter := gen.GenerateUint64ID() & MaxCounter; counter != 8 {
+		t.Errorf("Masked counter: got %d, expected 8", counter)
+	}
+
+	// Without the option the counter starts at 0
+	gen = NewGenerator(WithMachineID(1), WithInstanceID(1))
+	if counter := gen.GenerateUint64ID() & MaxCounter; counter != 1 {
+		t.Errorf("Default counter: got %d, expected 1", counter)
+	}
+}
+
+// TestWithTestMachineIndex tests that indexed in-process nodes get distinct
+// machine bits and never collide with each other.
+func TestWithTestMachineIndex(t *testing.T) {
+	nodes := make([]*IDGenerator, 3)
+	for i := range nodes {
+		nodes[i] = NewGenerator(WithTestMachineIndex(i))
+	}
+
+	machineIDs := make(map[uint64]bool)
+	seen := make(map[uint64]bool)
+	for i, node := range nodes {
+		id := node.GenerateUint64ID()
+
+		machineID := (id >> MachineIDShift) & MaxMachineID
+		if machineID != uint64(i) {
+			t.Errorf("Node %d has machine ID %d", i, machineID)
+		}
+		machineIDs[machineID] = true
+
+		// Synchronize counters so only the machine bits can tell IDs apart
+		node.counter = 0
+		for j := 0; j < 1000; j++ {
+			id := node.GenerateUint64ID()
+			if seen[id] {
+				t.Errorf("Cross-node collision on ID %d", id)
+			}
+			seen[id] = true
+		}
+	}
+
+	if len(machineIDs) != len(nodes) {
+		t.Errorf("Expected %d distinct machine IDs, got %d", len(nodes), len(machineIDs))
+	}
+
+	if _, err := NewGeneratorWithError(WithTestMachineIndex(-1)); err == nil {
+		t.Errorf("Expected error for negative index")
+	}
+}
+
+// TestWithClock tests that a pinned clock produces exact timestamp bits.
+func TestWithClock(t *testing.T) {
+	pinned := time.Date(2025, 6, 1, 12, 34, 56, 789*int(time.Millisecond), time.UTC)
+	gen := NewGenerator(WithClock(func() time.Time { return pinned }))
+
+	for i := 0; i < 10; i++ {
+		id := gen.GenerateUint64ID()
+
+		timestamp := (id >> TimestampShift) & MaxTimestamp
+		if timestamp != uint64(pinned.UnixMilli()) {
+			t.Errorf("Timestamp mismatch: got %d, expected %d", timestamp, pinned.UnixMilli())
+		}
+	}
+
+	if _, err := NewGeneratorWithError(WithClock(nil)); err == nil {
+		t.Errorf("Expected error for nil clock")
+	}
+}
+
+// TestWithSuffixLength tests that string IDs use the configured suffix length
+// and that excessive lengths log a warning but still work.
+func TestWithSuffixLength(t *testing.T) {
+	gen, err := NewGeneratorWithError(WithSuffixLength(4))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if id := gen.GenerateFixedStringID(); len(id) != HexIDLength+4 {
+		t.Errorf("Fixed string ID has wrong length: %s", id)
+	}
+
+	// The warning fires regardless of option order
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&logs, nil))
+	gen = NewGenerator(WithSuffixLength(MaxUsefulSuffixLength+1), WithLogger(logger))
+
+	if !strings.Contains(logs.String(), "level=WARN") || !strings.Contains(logs.String(), "suffix length") {
+		t.Errorf("Expected suffix length warning, got %q", logs.String())
+	}
+
+	id := gen.GenerateStringID()
+	suffix := id[len(id)-(MaxUsefulSuffixLength+1):]
+	for _, c := range suffix {
+		if !strings.ContainsRune(CharSet, c) {
+			t.Errorf("Suffix %s contains invalid character %q", suffix, c)
+		}
+	}
+	if _, err := DecodeBase62(gen.GenerateBase62ID()); err != nil {
+		t.Errorf("Failed to decode base62 ID with long suffix: %v", err)
+	}
+
+	// Reasonable lengths do not warn
+	logs.Reset()
+	NewGenerator(WithLogger(logger), WithSuffixLength(MaxUsefulSuffixLength))
+	if strings.Contains(logs.String(), "exceeds useful entropy") {
+		t.Errorf("Unexpected warning: %q", logs.String())
+	}
+
+	if _, err := NewGeneratorWithError(WithSuffixLength(-1)); err == nil {
+		t.Errorf("Expected error for negative suffix length")
+	}
+	if _, err := NewGeneratorWithError(WithLogger(nil)); err == nil {
+		t.Errorf("Expected error for nil logger")
+	}
+}
+
+// TestWithTimestampResolution tests microsecond timestamps, their decoding
+// and the validation of the resolution and layout.
+func TestWithTimestampResolution(t *testing.T) {
+	epoch := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := epoch.Add(time.Hour + 1234567*time.Nanosecond)
+	clock := newFakeClock(now, 0)
+	layout := Layout{MachineBits: 4, InstanceBits: 4, TimestampBits: 48, CounterBits: 8}
+
+	gen, err := NewGeneratorWithLayout(layout, WithTimestampResolution(time.Microsecond),
+		WithEpoch(epoch), WithClock(clock.Now), WithOverflowWait())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	id := gen.GenerateUint64ID()
+	components := gen.Decode(id)
+	if want := uint64(time.Hour/time.Microsecond) + 1234; components.Timestamp != want {
+		t.Errorf("Expected timestamp %d, got %d", want, components.Timestamp)
+	}
+	if want := now.Truncate(time.Microsecond); !gen.TimeOf(id).Equal(want) {
+		t.Errorf("Expected time %v, got %v", want, gen.TimeOf(id))
+	}
+	if n := gen.MaxIDsPerMillisecond(); n != 256*1000 {
+		t.Errorf("Expected 256000 IDs per millisecond, got %d", n)
+	}
+
+	// Exhausting the counter moves on to the next microsecond
+	clock.Set(now, time.Microsecond)
+	for i := 0; i < 300; i++ {
+		id = gen.GenerateUint64ID()
+	}
+	if ts := gen.Decode(id).Timestamp; ts <= components.Timestamp {
+		t.Errorf("Expected a later microsecond after overflow, got %d", ts)
+	}
+
+	// The ID range uses microseconds too
+	minID, maxID := gen.IDRangeForTime(now, now)
+	if first := gen.Decode(minID).Timestamp; first != components.Timestamp {
+		t.Errorf("Range starts at %d, expected %d", first, components.Timestamp)
+	}
+	if maxID < minID {
+		t.Errorf("Invalid range [%d, %d]", minID, maxID)
+	}
+
+	// Microseconds since the Unix epoch do not fit the default layout
+	if _, err := NewGeneratorWithError(WithTimestampResolution(time.Microsecond)); err == nil {
+		t.Errorf("Expected error for microseconds in a 42-bit timestamp")
+	}
+	if _, err := NewGeneratorWithError(WithTimestampResolution(time.Microsecond), WithRelativeTimestamp()); err != nil {
+		t.Errorf("Unexpected error with relative timestamps: %v", err)
+	}
+	if _, err := NewGeneratorWithError(WithTimestampResolution(time.Second)); err == nil {
+		t.Errorf("Expected error for an unsupported resolution")
+	}
+}
+
+// TestWithCharSet tests that suffixes are drawn from a custom character set
+// and that invalid sets are rejected.
+func TestWithCharSet(t *testing.T) {
+	const digits = "0123456789"
+
+	gen, err := NewGeneratorWithError(WithCharSet(digits))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	pool := NewGeneratorPool(gen)
+	ids := append(gen.GenerateStringIDs(10), gen.GenerateStringID(), gen.GenerateFixedStringID(), pool.StringID())
+	for _, id := range ids {
+		suffix := id[len(id)-RandomSuffixLength:]
+		for _, c := range suffix {
+			if !strings.ContainsRune(digits, c) {
+				t.Errorf("Suffix %s contains character %q outside the set", suffix, c)
+			}
+		}
+	}
+
+	// A single character is a valid, if useless, set
+	if id := NewGenerator(WithCharSet("x")).GenerateStringID(); !strings.HasSuffix(id, "xxxxxxxx") {
+		t.Errorf("Expected suffix of x, got %s", id)
+	}
+
+	for _, charset := range []string{"", "aba", "abcé"} {
+		if _, err := NewGeneratorWithError(WithCharSet(charset)); err == nil {
+			t.Errorf("Expected error for character set %q", charset)
+		}
+	}
+	if _, err := NewGeneratorWithError(WithCharSet(digits), WithCheckedSuffix()); err == nil {
+		t.Errorf("Expected error for a custom character set with a checked suffix")
+	}
+	if _, err := NewGeneratorWithError(WithCharSet("abc/"), WithFilenameSafe()); err == nil {
+		t.Errorf("Expected error for a character set that is not filename safe")
+	}
+}
+
+// TestWithSeed tests that seeded generators produce reproducible suffixes and
+// that a nil source is rejected.
+func TestWithSeed(t *testing.T) {
+	genA := NewGenerator(WithSeed(42))
+	genB := NewGenerator(WithRandSource(rand.NewSource(42)))
+	genC := NewGenerator(WithSeed(43))
+
+	same, different := true, false
+	for i := 0; i < 100; i++ {
+		a, b, c := genA.generateRandomSuffix(RandomSuffixLength), genB.generateRandomSuffix(RandomSuffixLength),
+			genC.generateRandomSuffix(RandomSuffixLength)
+		same = same && a == b
+		different = different || a != c
+	}
+	if !same {
+		t.Errorf("Generators with the same seed produced different suffixes")
+	}
+	if !different {
+		t.Errorf("Generators with different seeds produced the same suffixes")
+	}
+
+	if _, err := NewGeneratorWithError(WithRandSource(nil)); err == nil {
+		t.Errorf("Expected error for nil random source")
+	}
+}
@@ -0,0 +1,84 @@
+// Package tsuniqid - Ordering helpers for uint64 IDs
+package tsuniqid
+
+import (
+	"sort"
+)
+
+// sequenceMask selects the timestamp and counter fields, which together
+// order IDs chronologically regardless of the machine and instance IDs
+const sequenceMask = MaxTimestamp<<TimestampShift | MaxCounter
+
+// AssertMonotonic reports whether a slice of IDs is strictly increasing,
+// as expected from an append-only stream.
+//
+// IDs are compared as plain uint64 values. Because the machine and instance
+// IDs occupy the highest bits, this only reflects generation order for
+// streams produced by a single generator (see WithMonotonic); interleaved
+// streams from several generators will usually report a violation.
+//
+// Parameters:
+//   - ids: The IDs in stream order
+//
+// Returns:
+//   - bool: True if every ID is greater than the one before it
+//   - int: The index of the first ID that is not, or -1 if none
+func AssertMonotonic(ids []uint64) (bool, int) {
+	for i := 1; i < len(ids); i++ {
+		if ids[i] <= ids[i-1] {
+			return false, i
+		}
+	}
+	return true, -1
+}
+
+// RankOf returns the index at which an ID would be inserted into a slice of
+// IDs sorted chronologically, for paginating over time-ordered IDs.
+//
+// IDs are ordered by their timestamp and then their counter, ignoring the
+// machine and instance IDs, so IDs from different generators interleave by
+// time. The slice must already be sorted in that order. If IDs with the same
+// sequence are present, the rank is the index of the first of them.
+//
+// Parameters:
+//   - id: The ID to rank
+//   - sorted: The IDs sorted by timestamp and counter
+//
+// Returns: The number of IDs in sorted that come strictly before id
+func RankOf(id uint64, sorted []uint64) int {
+	key := id & sequenceMask
+	return sort.Search(len(sorted), func(i int) bool {
+		return sorted[i]&sequenceMask >= key
+	})
+}
+
+// CompareByTime orders IDs chronologically by their timestamp and then their
+// counter, ignoring the machine and instance IDs.
+//
+// Plain uint64 comparison sorts by machine ID first, because the machine and
+// instance IDs occupy the highest bits, so IDs from different generators do
+// not come out in generation order. CompareByTime interleaves them by time
+// instead, matching the order RankOf expects:
+//
+//	sort.Slice(ids, func(i, j int) bool {
+//		return tsuniqid.CompareByTime(ids[i], ids[j]) < 0
+//	})
+//
+// IDs from different generators with the same timestamp and counter compare
+// as equal; use sort.SliceStable to keep their input order.
+//
+// Parameters:
+//   - a: The first ID
+//   - b: The second ID
+//
+// Returns: -1 if a was generated before b, 1 if after, and 0 if neither
+func CompareByTime(a, b uint64) int {
+	switch ka, kb := a&sequenceMask, b&sequenceMask; {
+	case ka < kb:
+		return -1
+	case ka > kb:
+		return 1
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,129 @@
+package tsuniqid
+
+import (
+	"sort"
+	"testing"
+	"time"
+)
+
+// TestAssertMonotonic tests detection of out-of-order and repeated IDs.
+func TestAssertMonotonic(t *testing.T) {
+	gen := NewGenerator(WithMonotonic())
+
+	ids := make([]uint64, 1000)
+	for i := range ids {
+		ids[i] = gen.GenerateUint64ID()
+	}
+
+	if ok, index := AssertMonotonic(ids); !ok || index != -1 {
+		t.Errorf("Expected monotonic stream, got violation at %d", index)
+	}
+
+	testCases := []struct {
+		name     string
+		ids      []uint64
+		ok       bool
+		expected int
+	}{
+		{name: "Empty", ids: nil, ok: true, expected: -1},
+		{name: "Single", ids: []uint64{42}, ok: true, expected: -1},
+		{name: "Out of order", ids: []uint64{1, 2, 5, 4, 6}, ok: false, expected: 3},
+		{name: "Repeated", ids: []uint64{1, 2, 2, 3}, ok: false, expected: 2},
+		{name: "Swapped generated IDs", ids: []uint64{ids[0], ids[2], ids[1]}, ok: false, expected: 2},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ok, index := AssertMonotonic(tc.ids)
+			if ok != tc.ok || index != tc.expected {
+				t.Errorf("Got (%v, %d), expected (%v, %d)", ok, index, tc.ok, tc.expected)
+			}
+		})
+	}
+}
+
+// TestRankOf tests that ranks match the insertion points of IDs from several
+// generators in a chronologically sorted slice.
+func TestRankOf(t *testing.T) {
+	generators := []*IDGenerator{NewGenerator(WithMachineID(MaxMachineID)), NewGenerator(WithMachineID(0))}
+
+	// Offset one counter so no two IDs share a timestamp and counter
+	generators[1].counter = MaxCounter / 2
+
+	var all []uint64
+	for i := 0; i < 2000; i++ {
+		all = append(all, generators[i%2].GenerateUint64ID())
+	}
+	sort.Slice(all, func(i, j int) bool {
+		return all[i]&sequenceMask < all[j]&sequenceMask
+	})
+
+	// Remove every other ID and check that each one ranks where it was
+	var sorted, removed []uint64
+	for i, id := range all {
+		if i%2 == 0 {
+			sorted = append(sorted, id)
+		} else {
+			removed = append(removed, id)
+		}
+	}
+
+	for i, id := range removed {
+		if rank := RankOf(id, sorted); rank != i+1 {
+			t.Errorf("Rank of removed ID %d: got %d, expected %d", i, rank, i+1)
+		}
+	}
+
+	// Present IDs rank at their own index
+	for i, id := range sorted {
+		if rank := RankOf(id, sorted); rank != i {
+			t.Errorf("Rank of present ID %d: got %d", i, rank)
+		}
+	}
+
+	if rank := RankOf(all[0], nil); rank != 0 {
+		t.Errorf("Expected rank 0 in an empty slice, got %d", rank)
+	}
+}
+
+// TestCompareByTime tests that IDs from different machines sort by time
+// rather than by machine ID.
+func TestCompareByTime(t *testing.T) {
+	compose := func(machineID, timestamp, counter uint64) uint64 {
+		return machineID<<MachineIDShift | timestamp<<TimestampShift | counter
+	}
+
+	// Machine 15 generated first, so plain uint64 order is wrong
+	early := compose(MaxMachineID, 1000, 5)
+	late := compose(0, 1001, 0)
+	if early < late {
+		t.Fatalf("Test IDs should be out of order as plain uint64 values")
+	}
+	if CompareByTime(early, late) != -1 || CompareByTime(late, early) != 1 {
+		t.Errorf("Expected %x before %x", early, late)
+	}
+
+	// Within a millisecond the counter decides
+	if CompareByTime(compose(3, 1000, 1), compose(1, 1000, 2)) != -1 {
+		t.Errorf("Expected the lower counter first")
+	}
+	if CompareByTime(compose(3, 1000, 7), compose(1, 1000, 7)) != 0 {
+		t.Errorf("Expected equal sequences to compare equal")
+	}
+
+	// Sorting IDs from several generators interleaves them by time
+	clock := newFakeClock(time.Now(), time.Millisecond)
+	var ids []uint64
+	for i := 0; i < 20; i++ {
+		gen := NewGenerator(WithMachineID(uint64(MaxMachineID-i%4)), WithClock(clock.Now))
+		ids = append(ids, gen.GenerateUint64ID())
+	}
+	shuffled := append([]uint64(nil), ids...)
+	sort.Slice(shuffled, func(i, j int) bool { return shuffled[i] > shuffled[j] })
+	sort.Slice(shuffled, func(i, j int) bool { return CompareByTime(shuffled[i], shuffled[j]) < 0 })
+	for i := range ids {
+		if shuffled[i] != ids[i] {
+			t.Fatalf("Position %d: expected %x, got %x", i, ids[i], shuffled[i])
+		}
+	}
+}
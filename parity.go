@@ -0,0 +1,40 @@
+// Package tsuniqid - Parity bit for single-bit error detection
+package tsuniqid
+
+import (
+	"math/bits"
+)
+
+// Bit layout constants for IDs generated with WithParity.
+//
+// The regular layout is kept, except that the counter field is split:
+// - Bits 13-1 (13 bits): Counter
+// - Bit 0 (1 bit): Even parity over all other bits
+const (
+	// MaxParityCounter represents the maximum counter value with a parity bit (13 bits)
+	MaxParityCounter = 0x1fff
+
+	// ParityCounterShift is the number of bits to shift the counter with a parity bit
+	ParityCounterShift = 1
+)
+
+// CheckParity reports whether an ID generated with WithParity has even parity,
+// i.e. whether no single bit was flipped in transmission.
+//
+// Parameters:
+//   - id: The identifier to check
+//
+// Returns: True if the ID has an even number of set bits
+func CheckParity(id uint64) bool {
+	return bits.OnesCount64(id)%2 == 0
+}
+
+// parityOf computes the parity bit that makes the total number of set bits even.
+//
+// Parameters:
+//   - id: The identifier with its parity bit cleared
+//
+// Returns: 1 if the ID has an odd number of set bits, 0 otherwise
+func parityOf(id uint64) uint64 {
+	return uint64(bits.OnesCount64(id) & 1)
+}
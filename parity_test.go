@@ -0,0 +1,43 @@
+package tsuniqid
+
+import (
+	"testing"
+)
+
+// TestWithParity tests that generated IDs pass the parity check and that
+// flipping any single bit makes it fail.
+func TestWithParity(t *testing.T) {
+	gen := NewGenerator(WithParity())
+	seen := make(map[uint64]bool)
+
+	for i := 0; i < 1000; i++ {
+		id := gen.GenerateUint64ID()
+
+		if seen[id] {
+			t.Errorf("Duplicate ID found: %d", id)
+		}
+		seen[id] = true
+
+		if !CheckParity(id) {
+			t.Errorf("Generated ID %x fails parity check", id)
+		}
+
+		for bit := 0; bit < 64; bit++ {
+			if corrupted := id ^ (1 << bit); CheckParity(corrupted) {
+				t.Errorf("Flipping bit %d of %x was not detected", bit, id)
+			}
+		}
+	}
+}
+
+// TestWithParity_Counter tests that the counter is stored above the parity bit.
+func TestWithParity_Counter(t *testing.T) {
+	gen := NewGenerator(WithParity())
+
+	for i := uint64(1); i <= 10; i++ {
+		id := gen.GenerateUint64ID()
+		if counter := (id >> ParityCounterShift) & MaxParityCounter; counter != i {
+			t.Errorf("Counter mismatch: got %d, expected %d", counter, i)
+		}
+	}
+}
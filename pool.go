@@ -0,0 +1,139 @@
+// Package tsuniqid - Per-goroutine string ID generation with pooled sources
+package tsuniqid
+
+import (
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// GeneratorPool generates string IDs without serializing goroutines on the
+// generator's mutex. Each goroutine borrows a lightweight source from a
+// sync.Pool, which keeps one per processor: the source shares the
+// generator's machine and instance IDs, but has its own random number
+// generator for the suffix and, on generators created with
+// WithShardedCounter, its own counter shard, whose index fills the counter's
+// high bits. Sources on different processors therefore touch neither a lock
+// nor a shared counter, and IDs stay unique with those from the generator
+// itself, which draws from the same shards.
+//
+// Without WithShardedCounter, and in the sequenced modes of WithMonotonic and
+// WithOverflowWait, there are no shards to hand out and sources take the
+// uint64 part from the generator as GenerateUint64ID does. Each source
+// handles about 16384/shards IDs per millisecond before its shard wraps (see
+// WithShardedCounter).
+type GeneratorPool struct {
+	gen     *IDGenerator
+	sources sync.Pool
+	seeds   uint64 // number of sources created, mixed into their seeds
+}
+
+// poolSourceStep is the increment of a poolSource's random state, the
+// golden-ratio constant of SplitMix64
+const poolSourceStep = 0x9e3779b97f4a7c15
+
+// poolSource is the per-goroutine state of a GeneratorPool.
+type poolSource struct {
+	state     uint64 // random state, advanced by poolSourceStep and mixed by InterleaveBits
+	cryptoBuf []byte // buffered crypto/rand bytes, with WithCryptoRandomSuffix
+	cryptoPos int    // next unread position in cryptoBuf
+	shard     uint64 // the generator's counter shard this source draws from
+}
+
+// NewGeneratorPool creates a pool generating string IDs in the same format as
+// g.GenerateStringID, including its suffix length, character set, check
+// character and crypto/rand suffixes. Give g WithShardedCounter to partition
+// its counter among the pool's sources.
+//
+// Parameters:
+//   - g: The generator providing the identity, counter and format
+//
+// Returns: A new GeneratorPool
+func NewGeneratorPool(g *IDGenerator) *GeneratorPool {
+	p := &GeneratorPool{gen: g}
+	p.sources.New = p.newSource
+	return p
+}
+
+// newSource creates a source with its own random state and, on sharded
+// generators, the next shard round-robin. sync.Pool drops idle sources at
+// every other garbage collection, so sources are cheap to create: the state
+// is a single SplitMix64 word rather than a math/rand source.
+//
+// Returns: The new *poolSource
+func (p *GeneratorPool) newSource() interface{} {
+	// Distinct seeds keep sources created in the same nanosecond apart
+	seed := uint64(time.Now().UnixNano()) ^ atomic.AddUint64(&p.seeds, 1)*poolSourceStep
+	src := &poolSource{state: seed}
+
+	g := p.gen
+	if g.shards != nil {
+		src.shard = g.newShardToken().(*shardToken).index
+	}
+	if g.cryptoSuffix {
+		src.cryptoBuf = make([]byte, cryptoBufferSize)
+		src.cryptoPos = cryptoBufferSize
+	}
+	return src
+}
+
+// StringID creates a unique string identifier like GenerateStringID on the
+// pool's generator, without taking the generator's mutex.
+//
+// Returns: A unique string identifier
+func (p *GeneratorPool) StringID() string {
+	g := p.gen
+	src := p.sources.Get().(*poolSource)
+
+	var buf [HexIDLength + RandomSuffixLength]byte
+	dst := strconv.AppendUint(buf[:0], p.uint64ID(src), 16)
+	for i := 0; i < g.suffixLength; i++ {
+		dst = append(dst, g.charSet[src.intn(len(g.charSet))])
+	}
+	p.sources.Put(src)
+
+	if g.checkedSuffix && g.suffixLength > 0 {
+		if check, ok := checkChar(string(dst[:len(dst)-1])); ok {
+			dst[len(dst)-1] = check
+		}
+	}
+	return string(dst)
+}
+
+// uint64ID generates the uint64 part of a string ID from the source's shard,
+// or from the generator when it has no shards to hand out.
+//
+// Parameters:
+//   - src: The borrowed source
+//
+// Returns: A unique uint64 identifier
+func (p *GeneratorPool) uint64ID(src *poolSource) uint64 {
+	g := p.gen
+	if g.shards == nil || g.monotonic || g.overflowWait {
+		return g.GenerateUint64ID()
+	}
+
+	last, base, capacity := g.reserveShardCounters(src.shard, 1)
+	timestamp := g.freeRunningTimestamp()
+	g.trackWindow(timestamp, 1)
+	return g.compose(timestamp, base|last%capacity)
+}
+
+// intn returns a random integer in [0, n) for the suffix, drawn from
+// crypto/rand when the source has a buffer and from its SplitMix64 state
+// otherwise, like IDGenerator.randIntn. The modulo bias is below n/2^64.
+//
+// Parameters:
+//   - n: The exclusive upper bound
+//
+// Returns: A random integer in [0, n)
+func (s *poolSource) intn(n int) int {
+	if s.cryptoBuf != nil {
+		if v, ok := cryptoIntnFrom(s.cryptoBuf, &s.cryptoPos, n); ok {
+			return v
+		}
+	}
+	s.state += poolSourceStep
+	return int(InterleaveBits(s.state) % uint64(n))
+}
@@ -0,0 +1,124 @@
+package tsuniqid
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestGeneratorPool_StringID tests that pooled string IDs are unique across
+// goroutines and keep the generator's format.
+func TestGeneratorPool_StringID(t *testing.T) {
+	gen := NewGenerator(WithMachineID(0xc), WithCheckedSuffix())
+	pool := NewGeneratorPool(gen)
+
+	const goroutines, perGoroutine = 8, 1000
+	results := make(chan string, goroutines*perGoroutine)
+	var wg sync.WaitGroup
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < perGoroutine; j++ {
+				results <- pool.StringID()
+			}
+		}()
+	}
+	wg.Wait()
+	close(results)
+
+	seen := make(map[string]bool)
+	for id := range results {
+		if seen[id] {
+			t.Errorf("Duplicate ID found: %s", id)
+		}
+		seen[id] = true
+
+		if !ValidStringID(id) || !VerifyCheckedSuffix(id) {
+			t.Errorf("Pooled ID has an invalid format: %s", id)
+		}
+		value, _ := strconv.ParseUint(id[:len(id)-RandomSuffixLength], 16, 64)
+		if machineID := DecodeUint64(value).MachineID; machineID != 0xc {
+			t.Errorf("Machine ID mismatch in %s: got %d", id, machineID)
+		}
+	}
+
+	// Sources draw suffixes from their own crypto/rand buffers
+	pool = NewGeneratorPool(NewGenerator(WithCryptoRandomSuffix()))
+	if id := pool.StringID(); !ValidStringID(id) {
+		t.Errorf("Pooled crypto ID has an invalid format: %s", id)
+	}
+}
+
+// TestGeneratorPool_Shards tests that pool sources on a sharded generator
+// own a shard each, and that their IDs stay unique with the generator's own
+// IDs within one millisecond.
+func TestGeneratorPool_Shards(t *testing.T) {
+	pinned := time.Now()
+	gen := NewGenerator(WithShardedCounter(4), WithClock(func() time.Time { return pinned }))
+	pool := NewGeneratorPool(gen)
+
+	// Sources take the shards round-robin
+	sources := make([]*poolSource, 4)
+	for i := range sources {
+		sources[i] = pool.sources.Get().(*poolSource)
+	}
+	shards := make(map[uint64]bool)
+	for _, src := range sources {
+		shards[src.shard] = true
+		if id := pool.uint64ID(src); (id&MaxCounter)>>12 != src.shard {
+			t.Errorf("ID %x does not carry shard %d in its high counter bits", id, src.shard)
+		}
+	}
+	if len(shards) != 4 {
+		t.Errorf("Expected 4 sources to own 4 shards, got %d", len(shards))
+	}
+	for _, src := range sources {
+		pool.sources.Put(src)
+	}
+
+	// Stay within one shard's 4096 values per millisecond
+	seen := make(map[uint64]bool)
+	for i := 0; i < 1000; i++ {
+		text := pool.StringID()
+		value, err := strconv.ParseUint(text[:len(text)-RandomSuffixLength], 16, 64)
+		if err != nil {
+			t.Fatalf("Pooled ID %s has an invalid hex part: %v", text, err)
+		}
+
+		for _, id := range []uint64{value, gen.GenerateUint64ID()} {
+			if seen[id] {
+				t.Fatalf("Duplicate ID found: %x", id)
+			}
+			seen[id] = true
+		}
+	}
+}
+
+// BenchmarkGenerateStringID_Shared benchmarks parallel string ID generation
+// on one generator, whose random number generator is guarded by a mutex.
+// Run with -cpu 1,4,16 on a machine with as many cores to compare scaling
+// with GeneratorPool.
+func BenchmarkGenerateStringID_Shared(b *testing.B) {
+	gen := NewGenerator(WithShardedCounter(16))
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			_ = gen.GenerateStringID()
+		}
+	})
+}
+
+// BenchmarkGeneratorPool_StringID benchmarks parallel string ID generation
+// with pooled sources, each with its own random number generator and counter
+// shard.
+func BenchmarkGeneratorPool_StringID(b *testing.B) {
+	pool := NewGeneratorPool(NewGenerator(WithShardedCounter(16)))
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			_ = pool.StringID()
+		}
+	})
+}
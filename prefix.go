@@ -0,0 +1,49 @@
+// Package tsuniqid - Prefixed string IDs for self-describing identifiers
+package tsuniqid
+
+import "strings"
+
+// PrefixSeparator separates the prefix from the string ID in prefixed IDs,
+// as in "cus_18f3a2c0b4e5d6a7f1x9k2q"
+const PrefixSeparator = '_'
+
+// GenerateStringIDWithPrefix creates a unique string identifier in the format
+// of GenerateStringID, preceded by prefix and PrefixSeparator, so IDs name
+// the kind of object they identify in logs and APIs. An empty prefix gives a
+// plain string ID.
+//
+// StripPrefix splits at the last separator, so the prefix may itself contain
+// PrefixSeparator, but the ID's character set (see WithCharSet) must not.
+//
+// Parameters:
+//   - prefix: The namespace, such as "cus" or "order"
+//
+// Returns: A unique prefixed string identifier
+func (g *IDGenerator) GenerateStringIDWithPrefix(prefix string) string {
+	if prefix == "" {
+		return g.GenerateStringID()
+	}
+
+	buf := make([]byte, 0, len(prefix)+1+HexIDLength+g.suffixLength)
+	buf = append(buf, prefix...)
+	buf = append(buf, PrefixSeparator)
+	return string(g.AppendStringID(buf))
+}
+
+// StripPrefix splits a prefixed ID from GenerateStringIDWithPrefix into its
+// prefix and string ID at the last PrefixSeparator. An ID without a separator
+// is returned unchanged with an empty prefix.
+//
+// Parameters:
+//   - s: The prefixed ID
+//
+// Returns:
+//   - prefix: The namespace before the separator
+//   - id: The string ID after the separator
+func StripPrefix(s string) (prefix, id string) {
+	i := strings.LastIndexByte(s, PrefixSeparator)
+	if i < 0 {
+		return "", s
+	}
+	return s[:i], s[i+1:]
+}
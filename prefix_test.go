@@ -0,0 +1,56 @@
+package tsuniqid
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestGenerateStringIDWithPrefix tests that prefixed IDs carry the prefix,
+// split back into prefix and a valid string ID, and stay unique.
+func TestGenerateStringIDWithPrefix(t *testing.T) {
+	gen := NewGenerator()
+
+	seen := make(map[string]bool)
+	for _, prefix := range []string{"cus", "sub_sched"} {
+		for i := 0; i < 1000; i++ {
+			s := gen.GenerateStringIDWithPrefix(prefix)
+			if !strings.HasPrefix(s, prefix+string(PrefixSeparator)) {
+				t.Fatalf("ID %q does not start with %q", s, prefix)
+			}
+			if !ValidStringID(s) {
+				t.Errorf("Prefixed ID reported invalid: %s", s)
+			}
+			if gotPrefix, id := StripPrefix(s); gotPrefix != prefix || !ValidStringID(id) {
+				t.Errorf("StripPrefix(%q) = %q, %q", s, gotPrefix, id)
+			}
+			if seen[s] {
+				t.Fatalf("Duplicate ID: %s", s)
+			}
+			seen[s] = true
+		}
+	}
+
+	if s := gen.GenerateStringIDWithPrefix(""); strings.IndexByte(s, PrefixSeparator) >= 0 || !ValidStringID(s) {
+		t.Errorf("Empty prefix gave %q, expected a plain string ID", s)
+	}
+}
+
+// TestStripPrefix tests splitting at the last separator.
+func TestStripPrefix(t *testing.T) {
+	testCases := []struct {
+		s      string
+		prefix string
+		id     string
+	}{
+		{s: "cus_0abcdefgh", prefix: "cus", id: "0abcdefgh"},
+		{s: "sub_sched_0abcdefgh", prefix: "sub_sched", id: "0abcdefgh"},
+		{s: "0abcdefgh", prefix: "", id: "0abcdefgh"},
+		{s: "_0abcdefgh", prefix: "", id: "0abcdefgh"},
+	}
+
+	for _, tc := range testCases {
+		if prefix, id := StripPrefix(tc.s); prefix != tc.prefix || id != tc.id {
+			t.Errorf("StripPrefix(%q) = %q, %q, expected %q, %q", tc.s, prefix, id, tc.prefix, tc.id)
+		}
+	}
+}
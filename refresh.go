@@ -0,0 +1,64 @@
+// Package tsuniqid - Re-deriving the machine ID after network changes
+package tsuniqid
+
+import (
+	"errors"
+	"sync/atomic"
+)
+
+// ErrMachineIDExplicit is returned by RefreshMachineID for generators whose
+// machine ID was set with WithMachineID or WithTestMachineIndex.
+var ErrMachineIDExplicit = errors.New("machine ID was set explicitly; nothing to refresh")
+
+// RefreshMachineID derives the machine ID again, the same way NewGenerator
+// did, and switches future IDs to it. Long-running daemons can call it after
+// a DHCP renewal or interface change so their identity follows the host. The
+// refreshed host identity is also cached for generators created afterwards.
+// The instance ID is kept and claimed on the new machine ID, and its claim
+// on the old machine ID is released.
+//
+// The switch has consequences for IDs around it:
+//   - Sortability: the machine ID occupies the top bits, so IDs issued after
+//     the switch sort before or after earlier ones by machine ID, not time.
+//   - Uniqueness: the new machine and instance pair may already be used by
+//     another generator, on this host or another, which then produces
+//     colliding IDs. IDs issued in flight during the switch carry either ID.
+//
+// If the hostname and IP are both unavailable, the current machine ID is
+// kept rather than replaced by a random one, and ErrMachineIDUnavailable is
+// returned. Generators using WithRandomMachineID draw a new random ID.
+//
+// Returns: An error if the machine ID was not refreshed, or nil
+func (g *IDGenerator) RefreshMachineID() error {
+	if g.machineIDSet {
+		return ErrMachineIDExplicit
+	}
+	g.ensureIdentity()
+
+	derive := g.machineIDFunc
+	if g.machineSource == "host" {
+		derive = refreshMachineID
+	}
+	machineHash, random := derive()
+	if random {
+		return ErrMachineIDUnavailable
+	}
+
+	machineID := machineHash & g.layout.maxMachineID()
+	old := atomic.SwapUint64(&g.machineID, machineID)
+	atomic.StoreUint64(&g.machineHash, machineHash)
+	atomic.StoreUint64(&g.prefix, g.layout.identityPrefix(machineID, g.instanceID))
+
+	if machineID != old {
+		claimInstanceID(g.layout, machineID, g.instanceID)
+		if atomic.LoadInt32(&g.instanceHeld) == 1 {
+			releaseInstanceID(g.layout, old, g.instanceID)
+		}
+		atomic.StoreInt32(&g.instanceHeld, 1)
+		if g.logger != nil {
+			g.logger.Info("machine ID refreshed", "old_machine_id", old, "machine_id", machineID,
+				"instance_id", g.instanceID)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package tsuniqid
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"testing"
+)
+
+// TestRefreshMachineID tests that a changed host identity reaches future IDs
+// and new generators, and that explicit or unavailable identities are kept.
+func TestRefreshMachineID(t *testing.T) {
+	defer func(old func() (string, error)) { osHostname = old }(osHostname)
+	defer func(old func() ([]net.Interface, error)) { netInterfaces = old }(netInterfaces)
+	defer resetMachineIDCache()
+
+	hostname := "refresh-host-0"
+	osHostname = func() (string, error) { return hostname, nil }
+	netInterfaces = func() ([]net.Interface, error) { return nil, nil }
+	resetMachineIDCache()
+
+	gen := NewGenerator()
+	before := gen.MachineID()
+
+	// Generation may run concurrently with the refresh
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 1000; i++ {
+			gen.GenerateUint64ID()
+		}
+	}()
+
+	// Without an IP the machine ID also hashes a random string, so rename
+	// the host until the machine bits change
+	for i := 1; gen.MachineID() == before && i < 100; i++ {
+		hostname = fmt.Sprintf("refresh-host-%d", i)
+		if err := gen.RefreshMachineID(); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+	wg.Wait()
+
+	after := gen.MachineID()
+	if after == before {
+		t.Fatalf("Machine ID never changed from %d", before)
+	}
+	if machineID := gen.Decode(gen.GenerateUint64ID()).MachineID; machineID != after {
+		t.Errorf("Decoded machine ID after refresh: got %d, expected %d", machineID, after)
+	}
+	if machineID := NewGenerator().MachineID(); machineID != after {
+		t.Errorf("New generator machine ID: got %d, expected the refreshed %d", machineID, after)
+	}
+
+	// Without host information the current machine ID is kept
+	osHostname = func() (string, error) { return "", errors.New("no hostname") }
+	netInterfaces = func() ([]net.Interface, error) { return nil, errors.New("no interfaces") }
+	if err := gen.RefreshMachineID(); !errors.Is(err, ErrMachineIDUnavailable) {
+		t.Errorf("Expected ErrMachineIDUnavailable, got %v", err)
+	}
+	if gen.MachineID() != after {
+		t.Errorf("Machine ID changed to %d without host information", gen.MachineID())
+	}
+
+	if err := NewGenerator(WithMachineID(3)).RefreshMachineID(); !errors.Is(err, ErrMachineIDExplicit) {
+		t.Errorf("Expected ErrMachineIDExplicit, got %v", err)
+	}
+}
@@ -0,0 +1,39 @@
+// Package tsuniqid - Named generators shared across a process
+package tsuniqid
+
+import (
+	"sync"
+)
+
+// namedGenerators caches the generators created by GetNamedGenerator
+var (
+	namedMu         sync.Mutex
+	namedGenerators = make(map[string]*IDGenerator)
+)
+
+// GetNamedGenerator returns the process-wide generator registered under
+// name, creating it with NewGenerator on first use. Multi-tenant services
+// can give every tenant or namespace its own generator without each package
+// managing generator lifecycles.
+//
+// Each named generator draws its own instance ID, skipping IDs already taken
+// on this machine, so generators for different names never produce the same
+// ID. The default layout has only 16 instance IDs per machine; beyond that,
+// instance IDs are shared and uniqueness across names is no longer
+// guaranteed.
+//
+// Parameters:
+//   - name: The name of the generator, such as a tenant ID
+//
+// Returns: The generator registered under name
+func GetNamedGenerator(name string) *IDGenerator {
+	namedMu.Lock()
+	defer namedMu.Unlock()
+
+	g, ok := namedGenerators[name]
+	if !ok {
+		g = NewGenerator()
+		namedGenerators[name] = g
+	}
+	return g
+}
@@ -0,0 +1,44 @@
+package tsuniqid
+
+import (
+	"sync"
+	"testing"
+)
+
+// TestGetNamedGenerator tests that names map to one shared generator each,
+// with distinct instance IDs, even when first requested concurrently.
+func TestGetNamedGenerator(t *testing.T) {
+	names := []string{"tenant-a", "tenant-b", "tenant-c"}
+
+	var wg sync.WaitGroup
+	got := make([][]*IDGenerator, 8)
+	for i := range got {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for _, name := range names {
+				got[i] = append(got[i], GetNamedGenerator(name))
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	for i := range got {
+		for j, gen := range got[i] {
+			if gen != got[0][j] {
+				t.Errorf("Name %s returned different generators", names[j])
+			}
+		}
+	}
+	if GetNamedGenerator("tenant-a") != got[0][0] {
+		t.Errorf("Later lookup returned a different generator")
+	}
+
+	a, b, c := got[0][0], got[0][1], got[0][2]
+	if a == b || b == c || a == c {
+		t.Errorf("Different names shared a generator")
+	}
+	if a.MachineID() == b.MachineID() && a.InstanceID() == b.InstanceID() {
+		t.Errorf("Named generators share instance ID %d", a.InstanceID())
+	}
+}
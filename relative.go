@@ -0,0 +1,41 @@
+// Package tsuniqid - Timestamps relative to generator construction
+package tsuniqid
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// CreatedAt returns the time the generator was constructed. With
+// WithRelativeTimestamp, it is read from the clock set by WithClock and is
+// the base the timestamps of its IDs are relative to; otherwise it is the
+// wall-clock time.
+//
+// Returns: The construction time
+func (g *IDGenerator) CreatedAt() time.Time {
+	return g.createdAt
+}
+
+// TimeOf returns the time at which an ID from this generator was created,
+// adding back the generator's epoch, which is CreatedAt with
+// WithRelativeTimestamp.
+//
+// Parameters:
+//   - id: An identifier generated by this generator
+//
+// Returns: The creation time, with the precision of the generator's resolution
+func (g *IDGenerator) TimeOf(id uint64) time.Time {
+	return g.Decode(id).Time()
+}
+
+// checkRelativeOverflow logs a warning the first time a relative timestamp
+// no longer fits the layout's timestamp field.
+//
+// Parameters:
+//   - timestamp: The offset from CreatedAt in units of the resolution
+func (g *IDGenerator) checkRelativeOverflow(timestamp uint64) {
+	if timestamp > g.layout.maxTimestamp() && atomic.CompareAndSwapUint32(&g.overflowWarned, 0, 1) {
+		g.warn("relative timestamp overflowed its field",
+			"offset", timestamp, "max_offset", g.layout.maxTimestamp(), "resolution", g.resolution, "created_at", g.createdAt)
+	}
+}
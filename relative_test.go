@@ -0,0 +1,65 @@
+package tsuniqid
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestWithRelativeTimestamp tests that relative timestamps count from
+// construction, decode back to absolute time, and warn when they overflow a
+// small timestamp field.
+func TestWithRelativeTimestamp(t *testing.T) {
+	start := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	clock := newFakeClock(start, 0)
+
+	var logs bytes.Buffer
+	layout := Layout{MachineBits: 4, InstanceBits: 4, TimestampBits: 20, CounterBits: 36}
+	gen, err := NewGeneratorWithLayout(layout, WithRelativeTimestamp(), WithClock(clock.Now),
+		WithLogger(slog.New(slog.NewTextHandler(&logs, nil))))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !gen.CreatedAt().Equal(start) {
+		t.Errorf("CreatedAt mismatch: got %v, expected %v", gen.CreatedAt(), start)
+	}
+
+	clock.Set(start.Add(5*time.Second+7*time.Millisecond), 0)
+	id := gen.GenerateUint64ID()
+	if offset := gen.Decode(id).Timestamp; offset != 5007 {
+		t.Errorf("Offset mismatch: got %d, expected %d", offset, 5007)
+	}
+	if ts := gen.TimeOf(id); !ts.Equal(start.Add(5007 * time.Millisecond)) {
+		t.Errorf("Decoded time mismatch: got %v", ts)
+	}
+
+	// The default layout decodes with the package helpers too
+	clock.Set(start, 0)
+	gen = NewGenerator(WithRelativeTimestamp(), WithClock(clock.Now))
+	clock.Set(start.Add(time.Minute), 0)
+	id = gen.GenerateUint64ID()
+	if ts := DecodeUint64WithEpoch(id, gen.CreatedAt()).Time(); !ts.Equal(start.Add(time.Minute)) {
+		t.Errorf("Decoded time mismatch: got %v", ts)
+	}
+
+	if strings.Contains(logs.String(), "overflowed") {
+		t.Errorf("Unexpected warning: %q", logs.String())
+	}
+
+	// Outliving the 20-bit field warns once
+	clock.Set(start.Add(time.Duration(layout.maxTimestamp()+1)*time.Millisecond), 0)
+	gen, _ = NewGeneratorWithLayout(layout, WithRelativeTimestamp(), WithClock(clock.Now),
+		WithLogger(slog.New(slog.NewTextHandler(&logs, nil))))
+	clock.Set(clock.Now().Add(time.Duration(layout.maxTimestamp()+1)*time.Millisecond), 0)
+	gen.GenerateUint64ID()
+	gen.GenerateUint64ID()
+	if count := strings.Count(logs.String(), "relative timestamp overflowed"); count != 1 {
+		t.Errorf("Expected one overflow warning, got %d: %q", count, logs.String())
+	}
+
+	if _, err := NewGeneratorWithError(WithRelativeTimestamp(), WithEpoch(start)); err == nil {
+		t.Errorf("Expected error for relative timestamp with a custom epoch")
+	}
+}
@@ -0,0 +1,88 @@
+// Package tsuniqid - Detection of system clock rollback
+package tsuniqid
+
+import (
+	"errors"
+	"sync/atomic"
+	"time"
+)
+
+// ClockRollbackTolerance is how far the clock may move backward before
+// GenerateUint64IDChecked reports ErrClockRollback. Smaller regressions, such
+// as NTP slewing, are absorbed by reusing the last timestamp.
+const ClockRollbackTolerance = 5 * time.Millisecond
+
+// ErrClockRollback is returned by GenerateUint64IDChecked when the clock has
+// moved backward by more than ClockRollbackTolerance.
+var ErrClockRollback = errors.New("clock moved backward")
+
+// GenerateUint64IDChecked creates a unique uint64 identifier like
+// GenerateUint64ID, but fails when the clock has moved backward instead of
+// risking IDs that duplicate previously issued ones.
+//
+// The check is only performed on generators created with
+// WithClockRollbackError, and only timestamps of IDs generated by this
+// method are tracked. On other generators it always succeeds.
+//
+// Returns:
+//   - uint64: A unique uint64 identifier
+//   - error: ErrClockRollback if the clock is behind the last issued timestamp
+//     by more than ClockRollbackTolerance
+func (g *IDGenerator) GenerateUint64IDChecked() (uint64, error) {
+	if !g.rollbackError {
+		return g.GenerateUint64ID(), nil
+	}
+
+	if g.monotonic || g.overflowWait {
+		g.seqMu.Lock()
+		defer g.seqMu.Unlock()
+
+		now := g.timestamp()
+		if g.isRollback(now, g.lastTimestamp) {
+			return 0, ErrClockRollback
+		}
+		if !g.monotonic && now < g.lastTimestamp {
+			// Without monotonic mode the sequence would restart on an
+			// already used timestamp, so wait out the small regression
+			g.waitAfter(g.lastTimestamp - 1)
+		}
+
+		timestamp, counter := g.nextSequenceLocked()
+		return g.compose(timestamp, counter), nil
+	}
+
+	counter := g.nextCounter()
+	timestamp := g.freeRunningTimestamp()
+
+	for {
+		last := atomic.LoadUint64(&g.lastChecked)
+		if timestamp <= last {
+			if g.isRollback(timestamp, last) {
+				return 0, ErrClockRollback
+			}
+
+			// A small regression; stay on the last timestamp
+			timestamp = last
+			break
+		}
+
+		if atomic.CompareAndSwapUint64(&g.lastChecked, last, timestamp) {
+			break
+		}
+	}
+	g.trackWindow(timestamp, 1)
+
+	return g.compose(timestamp, counter), nil
+}
+
+// isRollback reports whether a timestamp is behind the last issued timestamp
+// by more than ClockRollbackTolerance.
+//
+// Parameters:
+//   - timestamp: The current timestamp
+//   - last: The last issued timestamp
+//
+// Returns: True if the clock has rolled back beyond the tolerance
+func (g *IDGenerator) isRollback(timestamp, last uint64) bool {
+	return timestamp+uint64(ClockRollbackTolerance/g.resolution) < last
+}
@@ -0,0 +1,66 @@
+package tsuniqid
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestIDGenerator_GenerateUint64IDChecked tests that small clock regressions
+// are absorbed and large ones are reported, in both counter modes.
+func TestIDGenerator_GenerateUint64IDChecked(t *testing.T) {
+	for _, monotonic := range []bool{false, true} {
+		start := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+		clock := newFakeClock(start, 0)
+
+		opts := []Option{WithClock(clock.Now), WithClockRollbackError()}
+		if monotonic {
+			opts = append(opts, WithMonotonic())
+		}
+		gen := NewGenerator(opts...)
+
+		first, err := gen.GenerateUint64IDChecked()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		// A regression within the tolerance reuses the last timestamp
+		clock.Set(start.Add(-time.Millisecond), 0)
+		id, err := gen.GenerateUint64IDChecked()
+		if err != nil {
+			t.Fatalf("Unexpected error within tolerance: %v", err)
+		}
+		if id <= first {
+			t.Errorf("ID %d not after %d within tolerance", id, first)
+		}
+
+		// A larger regression fails
+		clock.Set(start.Add(-ClockRollbackTolerance-time.Millisecond), 0)
+		if _, err := gen.GenerateUint64IDChecked(); !errors.Is(err, ErrClockRollback) {
+			t.Errorf("Expected ErrClockRollback, got %v", err)
+		}
+
+		// Generation recovers once the clock catches up
+		clock.Set(start.Add(time.Millisecond), 0)
+		if _, err := gen.GenerateUint64IDChecked(); err != nil {
+			t.Errorf("Unexpected error after recovery: %v", err)
+		}
+	}
+}
+
+// TestIDGenerator_GenerateUint64IDChecked_Disabled tests that generators
+// without WithClockRollbackError never report rollback.
+func TestIDGenerator_GenerateUint64IDChecked_Disabled(t *testing.T) {
+	start := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	clock := newFakeClock(start, 0)
+	gen := NewGenerator(WithClock(clock.Now))
+
+	if _, err := gen.GenerateUint64IDChecked(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	clock.Set(start.Add(-time.Hour), 0)
+	if _, err := gen.GenerateUint64IDChecked(); err != nil {
+		t.Errorf("Unexpected error without WithClockRollbackError: %v", err)
+	}
+}
@@ -0,0 +1,82 @@
+// Package tsuniqid - Runtime sanity check of the ID bit layout
+package tsuniqid
+
+import (
+	"fmt"
+	"time"
+)
+
+// selfTestInstanceID is the instance ID used by SelfTest; its alternating
+// bits expose fields that overlap or are shifted by one
+const selfTestInstanceID = 0x5
+
+// SelfTest generates an ID with a throwaway generator, decodes it and checks
+// that the machine ID, instance ID, timestamp and counter match the
+// generator's state and the current time. It also checks that the exported
+// bit constants agree with DefaultLayout.
+//
+// It catches mistakes in the bit constants and layout arithmetic, and can run
+// at startup as a sanity check in strict deployments. The throwaway
+// generator's machine and instance IDs are not recorded as taken, so they
+// stay available to real generators.
+//
+// Returns: An error describing the first mismatch, or nil if the round trip succeeds
+func SelfTest() error {
+	l := DefaultLayout
+	if MachineIDShift != l.machineShift() || InstanceIDShift != l.instanceShift() || TimestampShift != l.timestampShift() {
+		return fmt.Errorf("shift constants %d/%d/%d disagree with default layout %d/%d/%d",
+			MachineIDShift, InstanceIDShift, TimestampShift, l.machineShift(), l.instanceShift(), l.timestampShift())
+	}
+	if MaxMachineID != l.maxMachineID() || MaxInstanceID != l.maxInstanceID() ||
+		MaxTimestamp != l.maxTimestamp() || MaxCounter != l.maxCounter() {
+		return fmt.Errorf("maximum constants disagree with default layout %+v", l)
+	}
+
+	g, err := NewGeneratorWithError(WithMachineID(MaxMachineID), WithInstanceID(selfTestInstanceID), withUnheldInstanceID())
+	if err != nil {
+		return fmt.Errorf("self-test generator: %w", err)
+	}
+
+	before := uint64(time.Now().UnixMilli())
+	id := g.GenerateUint64ID()
+	after := uint64(time.Now().UnixMilli())
+
+	machineID := (id >> MachineIDShift) & MaxMachineID
+	instanceID := (id >> InstanceIDShift) & MaxInstanceID
+	timestamp := (id >> TimestampShift) & MaxTimestamp
+	counter := id & MaxCounter
+
+	if machineID != g.MachineID() {
+		return fmt.Errorf("decoded machine ID %d, expected %d", machineID, g.MachineID())
+	}
+	if instanceID != g.InstanceID() {
+		return fmt.Errorf("decoded instance ID %d, expected %d", instanceID, g.InstanceID())
+	}
+	if timestamp < before || timestamp > after {
+		return fmt.Errorf("decoded timestamp %d outside generation window [%d, %d]", timestamp, before, after)
+	}
+	if want := g.counterValue() & MaxCounter; counter != want {
+		return fmt.Errorf("decoded counter %d, expected %d", counter, want)
+	}
+
+	// The generator's own decoder must agree with the constants
+	components := g.Decode(id)
+	if components.MachineID != machineID || components.InstanceID != instanceID ||
+		components.Timestamp != timestamp || components.Counter != counter {
+		return fmt.Errorf("generator decoded %+v, constants decoded %d/%d/%d/%d",
+			components, machineID, instanceID, timestamp, counter)
+	}
+
+	return nil
+}
+
+// withUnheldInstanceID keeps the explicit instance ID of a throwaway
+// generator out of the taken assignments.
+//
+// Returns: An Option that skips recording the instance ID
+func withUnheldInstanceID() Option {
+	return func(g *IDGenerator) error {
+		g.instanceUnheld = true
+		return nil
+	}
+}
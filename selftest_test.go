@@ -0,0 +1,30 @@
+package tsuniqid
+
+import (
+	"testing"
+)
+
+// TestSelfTest tests that the round trip succeeds with the shipped constants.
+func TestSelfTest(t *testing.T) {
+	if err := SelfTest(); err != nil {
+		t.Errorf("Self-test failed: %v", err)
+	}
+}
+
+// TestSelfTest_Assignments tests that the self-test leaves no assignment
+// behind for real generators to trip over.
+func TestSelfTest_Assignments(t *testing.T) {
+	assignmentsMu.Lock()
+	assignments = make(map[Layout]map[Assignment]int)
+	assignmentsMu.Unlock()
+
+	if err := SelfTest(); err != nil {
+		t.Fatalf("Self-test failed: %v", err)
+	}
+	if taken := ExportAssignments(); len(taken) != 0 {
+		t.Errorf("Self-test left assignments %v", taken)
+	}
+	if free := AvailableInstanceIDs(MaxMachineID); len(free) != MaxInstanceID+1 {
+		t.Errorf("Expected every instance ID free after the self-test, got %v", free)
+	}
+}
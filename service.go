@@ -0,0 +1,51 @@
+// Package tsuniqid - Service codes embedded in uint64 IDs
+package tsuniqid
+
+// Bit layout constants for IDs generated with WithServiceCode.
+//
+// The regular layout is kept, except that the counter field is split:
+// - Bits 13-12 (2 bits): Environment tag (zero unless WithEnvironment is used)
+// - Bits 11-8 (4 bits): Service code
+// - Bits 7-0 (8 bits): Counter
+//
+// With a custom Layout, the code occupies the 4 bits below the environment
+// tag at the top of the counter field.
+const (
+	// MaxServiceCode represents the maximum service code value (4 bits)
+	MaxServiceCode = 0xf
+
+	// MaxServiceCodeCounter represents the maximum counter value with a service code (8 bits)
+	MaxServiceCodeCounter = 0xff
+
+	// ServiceCodeShift is the number of bits to shift the service code
+	ServiceCodeShift = 8
+
+	// serviceCodeBits is the width of the service code
+	serviceCodeBits = 4
+)
+
+// ServiceCodeOf extracts the service code from an ID generated with
+// WithServiceCode. The result is meaningless for IDs without a service code.
+//
+// Parameters:
+//   - id: The identifier to inspect
+//
+// Returns: The service code of the ID
+func ServiceCodeOf(id uint64) uint64 {
+	return (id >> ServiceCodeShift) & MaxServiceCode
+}
+
+// tagBits returns the number of high counter bits taken by the environment
+// tag and service code.
+//
+// Returns: The number of reserved counter bits
+func (g *IDGenerator) tagBits() uint {
+	if g.serviceCodeSet {
+		// The environment slot stays reserved so the code has a fixed position
+		return environmentBits + serviceCodeBits
+	}
+	if g.environment != 0 {
+		return environmentBits
+	}
+	return 0
+}
@@ -0,0 +1,55 @@
+package tsuniqid
+
+import (
+	"testing"
+)
+
+// TestWithServiceCode tests that service codes are extracted correctly and
+// that generators sharing everything but the service code never collide.
+func TestWithServiceCode(t *testing.T) {
+	services := []*IDGenerator{
+		NewGenerator(WithMachineID(1), WithInstanceID(1), WithServiceCode(3)),
+		NewGenerator(WithMachineID(1), WithInstanceID(1), WithServiceCode(MaxServiceCode)),
+	}
+	codes := []uint64{3, MaxServiceCode}
+
+	// Stay within one counter cycle, which may fit in a single millisecond
+	seen := make(map[uint64]bool)
+	for i := 0; i < MaxServiceCodeCounter; i++ {
+		// Keep both counters in lockstep
+		services[1].counter = services[0].counter
+
+		for j, gen := range services {
+			id := gen.GenerateUint64ID()
+			if code := ServiceCodeOf(id); code != codes[j] {
+				t.Fatalf("Service code mismatch: got %d, expected %d", code, codes[j])
+			}
+			if seen[id] {
+				t.Fatalf("Collision between service codes: %d", id)
+			}
+			seen[id] = true
+		}
+	}
+
+	if max := services[0].maxCounter(); max != MaxServiceCodeCounter {
+		t.Errorf("Counter not shrunk: got %d, expected %d", max, MaxServiceCodeCounter)
+	}
+
+	// The service code and environment tag coexist
+	gen := NewGenerator(WithServiceCode(5), WithEnvironment(EnvironmentStaging), WithParity())
+	for i := 0; i < 1000; i++ {
+		id := gen.GenerateUint64ID()
+		if ServiceCodeOf(id) != 5 || EnvironmentOf(id) != EnvironmentStaging || !CheckParity(id) {
+			t.Fatalf("Tags not preserved in ID %x", id)
+		}
+	}
+
+	// Code zero is a valid service code
+	if id := NewGenerator(WithServiceCode(0)).GenerateUint64ID(); ServiceCodeOf(id) != 0 {
+		t.Errorf("Expected service code 0, got %d", ServiceCodeOf(id))
+	}
+
+	if _, err := NewGeneratorWithError(WithServiceCode(MaxServiceCode + 1)); err == nil {
+		t.Errorf("Expected error for out-of-range service code")
+	}
+}
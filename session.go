@@ -0,0 +1,98 @@
+// Package tsuniqid - Stateless session tokens signed with HMAC
+package tsuniqid
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"time"
+)
+
+// Session token layout before base64url encoding:
+// - Bytes 0-7: uint64 ID
+// - Bytes 8-15: Expiry in Unix milliseconds
+// - Bytes 16-47: HMAC-SHA256 over bytes 0-15
+const (
+	// sessionPayloadLength is the length of the signed part of a session token
+	sessionPayloadLength = 16
+
+	// sessionTokenLength is the length of a decoded session token
+	sessionTokenLength = sessionPayloadLength + sha256.Size
+)
+
+var (
+	// ErrInvalidSession is returned by VerifySession when a token is malformed
+	// or its HMAC does not match
+	ErrInvalidSession = errors.New("invalid session token")
+
+	// ErrSessionExpired is returned by VerifySession when a token's HMAC is
+	// valid but its expiry has passed
+	ErrSessionExpired = errors.New("session token expired")
+)
+
+// GenerateSession creates a stateless session token carrying a new uint64 ID
+// and an expiry, signed with HMAC-SHA256 so it can be validated with
+// VerifySession without a session store. The issue time comes from the
+// generator's clock.
+//
+// The token is signed, not encrypted: anyone holding it can read the ID and
+// expiry.
+//
+// Parameters:
+//   - key: The secret HMAC key
+//   - ttl: How long the token remains valid
+//
+// Returns: A base64url-encoded session token
+func (g *IDGenerator) GenerateSession(key []byte, ttl time.Duration) string {
+	token := make([]byte, sessionPayloadLength, sessionTokenLength)
+
+	binary.BigEndian.PutUint64(token[:8], g.GenerateUint64ID())
+	binary.BigEndian.PutUint64(token[8:], uint64(g.clock().Add(ttl).UnixMilli()))
+
+	token = append(token, signSession(key, token)...)
+	return base64.RawURLEncoding.EncodeToString(token)
+}
+
+// VerifySession validates a token created by GenerateSession and returns the
+// ID it carries. The HMAC is checked in constant time before the expiry.
+//
+// Parameters:
+//   - token: The base64url-encoded session token
+//   - key: The secret HMAC key the token was signed with
+//
+// Returns:
+//   - uint64: The ID carried by the token
+//   - error: ErrInvalidSession or ErrSessionExpired if the token is not valid
+func VerifySession(token string, key []byte) (uint64, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil || len(raw) != sessionTokenLength {
+		return 0, ErrInvalidSession
+	}
+
+	payload := raw[:sessionPayloadLength]
+	if !hmac.Equal(raw[sessionPayloadLength:], signSession(key, payload)) {
+		return 0, ErrInvalidSession
+	}
+
+	expiry := int64(binary.BigEndian.Uint64(payload[8:]))
+	if time.Now().UnixMilli() >= expiry {
+		return 0, ErrSessionExpired
+	}
+
+	return binary.BigEndian.Uint64(payload[:8]), nil
+}
+
+// signSession computes the HMAC-SHA256 of a session token payload.
+//
+// Parameters:
+//   - key: The secret HMAC key
+//   - payload: The ID and expiry bytes
+//
+// Returns: The 32-byte HMAC
+func signSession(key, payload []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
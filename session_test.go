@@ -0,0 +1,70 @@
+package tsuniqid
+
+import (
+	"encoding/base64"
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestIDGenerator_GenerateSession tests that a fresh token verifies and
+// returns the ID it was issued with.
+func TestIDGenerator_GenerateSession(t *testing.T) {
+	key := []byte("session-secret")
+	gen := NewGenerator()
+
+	before := time.Now().Truncate(time.Millisecond)
+	token := gen.GenerateSession(key, time.Minute)
+
+	id, err := VerifySession(token, key)
+	if err != nil {
+		t.Fatalf("Failed to verify valid token: %v", err)
+	}
+	if ts := DecodeUint64(id).Time(); ts.Before(before) || ts.After(time.Now()) {
+		t.Errorf("Session ID timestamp %v out of range", ts)
+	}
+
+	if _, err := VerifySession(token, []byte("other-secret")); !errors.Is(err, ErrInvalidSession) {
+		t.Errorf("Expected ErrInvalidSession for wrong key, got %v", err)
+	}
+}
+
+// TestVerifySession_Tampered tests that modifying any byte of a token
+// invalidates its HMAC.
+func TestVerifySession_Tampered(t *testing.T) {
+	key := []byte("session-secret")
+	token := NewGenerator().GenerateSession(key, time.Minute)
+
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		t.Fatalf("Failed to decode token: %v", err)
+	}
+
+	for i := range raw {
+		tampered := append([]byte(nil), raw...)
+		tampered[i] ^= 0x01
+
+		if _, err := VerifySession(base64.RawURLEncoding.EncodeToString(tampered), key); !errors.Is(err, ErrInvalidSession) {
+			t.Errorf("Expected ErrInvalidSession after flipping byte %d, got %v", i, err)
+		}
+	}
+
+	for _, s := range []string{"", "not base64!", token[:len(token)-4]} {
+		if _, err := VerifySession(s, key); !errors.Is(err, ErrInvalidSession) {
+			t.Errorf("Expected ErrInvalidSession for %q, got %v", s, err)
+		}
+	}
+}
+
+// TestVerifySession_Expired tests that a correctly signed token is rejected
+// after its expiry.
+func TestVerifySession_Expired(t *testing.T) {
+	key := []byte("session-secret")
+	issued := time.Now().Add(-time.Hour)
+	gen := NewGenerator(WithClock(func() time.Time { return issued }))
+
+	token := gen.GenerateSession(key, time.Minute)
+	if _, err := VerifySession(token, key); !errors.Is(err, ErrSessionExpired) {
+		t.Errorf("Expected ErrSessionExpired, got %v", err)
+	}
+}
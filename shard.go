@@ -0,0 +1,94 @@
+// Package tsuniqid - Sharded counters for highly concurrent generation
+package tsuniqid
+
+import (
+	"math/bits"
+	"sync/atomic"
+)
+
+// shardCounter is a counter padded to its own cache line, so goroutines
+// incrementing neighbouring shards do not contend on the same line.
+type shardCounter struct {
+	n uint64
+	_ [56]byte
+}
+
+// shardToken names the shard a processor draws from. Tokens live in a
+// sync.Pool, which keeps one per processor, so goroutines running on
+// different processors usually increment different shards without any
+// shared state or random number generation.
+type shardToken struct {
+	index uint64
+}
+
+// newShardToken hands out shards round-robin as sync.Pool creates tokens,
+// one per processor as long as there are enough shards.
+//
+// Returns: A token for the next shard
+func (g *IDGenerator) newShardToken() interface{} {
+	next := atomic.AddUint64(&g.shardNext, 1) - 1
+	return &shardToken{index: next & uint64(len(g.shards)-1)}
+}
+
+// shardedCounterBits returns the number of counter bits each shard may use
+// for its own count, below the shard index.
+//
+// Returns: The number of per-shard counter bits, or 0 if there are none left
+func (g *IDGenerator) shardedCounterBits() uint {
+	counterBits := uint(bits.Len64(g.maxCounter()))
+	if g.shardBits >= counterBits {
+		return 0
+	}
+	return counterBits - g.shardBits
+}
+
+// reserveCounters reserves n consecutive counter values with one atomic
+// operation. With WithShardedCounter, the values come from the shard of the
+// calling processor and base holds that shard's index, shifted above the
+// per-shard count.
+//
+// Parameters:
+//   - n: The number of counter values to reserve
+//
+// Returns:
+//   - last: The last reserved value; the first is last-n+1
+//   - base: The bits to OR into every counter value taken modulo capacity
+//   - capacity: The number of counter values available per millisecond
+func (g *IDGenerator) reserveCounters(n uint64) (last, base, capacity uint64) {
+	if g.shards == nil {
+		return atomic.AddUint64(&g.counter, n), 0, g.maxCounter() + 1
+	}
+
+	token := g.shardTokens.Get().(*shardToken)
+	last, base, capacity = g.reserveShardCounters(token.index, n)
+	g.shardTokens.Put(token)
+	return last, base, capacity
+}
+
+// reserveShardCounters reserves n consecutive counter values from one shard.
+//
+// Parameters:
+//   - shard: The index of the shard
+//   - n: The number of counter values to reserve
+//
+// Returns:
+//   - last: The last reserved value; the first is last-n+1
+//   - base: The shard's index, shifted above the per-shard count
+//   - capacity: The number of counter values the shard has per millisecond
+func (g *IDGenerator) reserveShardCounters(shard, n uint64) (last, base, capacity uint64) {
+	perShardBits := g.shardedCounterBits()
+	last = atomic.AddUint64(&g.shards[shard].n, n)
+	return last, shard << perShardBits, 1 << perShardBits
+}
+
+// counterValue returns the total number of counter values handed out, summed
+// over all shards.
+//
+// Returns: The current counter value
+func (g *IDGenerator) counterValue() uint64 {
+	total := atomic.LoadUint64(&g.counter)
+	for i := range g.shards {
+		total += atomic.LoadUint64(&g.shards[i].n)
+	}
+	return total
+}
@@ -0,0 +1,103 @@
+package tsuniqid
+
+import (
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestWithShardedCounter tests that sharded IDs stay unique, carry their
+// shard index in the high counter bits, and that invalid shard counts are
+// rejected.
+func TestWithShardedCounter(t *testing.T) {
+	pinned := time.Now()
+	gen, err := NewGeneratorWithError(WithShardedCounter(16), WithClock(func() time.Time { return pinned }))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// Stay within one shard's 1024 values, since every goroutine may run on
+	// the same processor and draw from the same shard
+	const goroutines, perGoroutine = 8, 100
+	results := make(chan uint64, goroutines*perGoroutine)
+	var wg sync.WaitGroup
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < perGoroutine; j++ {
+				results <- gen.GenerateUint64ID()
+			}
+		}()
+	}
+	wg.Wait()
+	close(results)
+
+	// Every ID shares one millisecond, so only the shard and its count tell them apart
+	seen := make(map[uint64]bool)
+	for id := range results {
+		if seen[id] {
+			t.Errorf("Duplicate ID found: %d", id)
+		}
+		seen[id] = true
+	}
+
+	if value := gen.counterValue(); value != goroutines*perGoroutine {
+		t.Errorf("Counter value mismatch: got %d, expected %d", value, goroutines*perGoroutine)
+	}
+
+	// Batches stay within one shard and spill like the single counter
+	ids := gen.GenerateUint64IDs(100)
+	for _, id := range ids {
+		if seen[id] {
+			t.Errorf("Duplicate batch ID found: %d", id)
+		}
+		seen[id] = true
+	}
+
+	// Tokens for new processors take the shards round-robin
+	gen = NewGenerator(WithShardedCounter(4))
+	for i := uint64(0); i < 8; i++ {
+		if token := gen.shardTokens.New().(*shardToken); token.index != i%4 {
+			t.Errorf("Token %d has shard %d, expected %d", i, token.index, i%4)
+		}
+	}
+	if _, base, capacity := gen.reserveShardCounters(3, 1); base != 3<<12 || capacity != 1<<12 {
+		t.Errorf("Shard 3 has base %#x and capacity %d, expected %#x and %d", base, capacity, 3<<12, 1<<12)
+	}
+
+	for _, shards := range []int{0, -4, 3, MaxCounter + 1} {
+		if _, err := NewGeneratorWithError(WithShardedCounter(shards)); err == nil {
+			t.Errorf("Expected error for %d shards", shards)
+		}
+	}
+	if gen := NewGenerator(WithShardedCounter(1)); gen.shards != nil {
+		t.Errorf("Expected a single shard to disable sharding")
+	}
+}
+
+// benchmarkAt32Goroutines runs generate from at least 32 goroutines. The
+// shards only pay off with real parallelism: compare the benchmarks below
+// with -cpu 1,8,32 on a machine with that many cores.
+func benchmarkAt32Goroutines(b *testing.B, generate func() uint64) {
+	b.SetParallelism((32 + runtime.GOMAXPROCS(0) - 1) / runtime.GOMAXPROCS(0))
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			_ = generate()
+		}
+	})
+}
+
+// BenchmarkGenerateUint64ID_SingleCounter benchmarks 32 goroutines sharing
+// the single atomic counter.
+func BenchmarkGenerateUint64ID_SingleCounter(b *testing.B) {
+	benchmarkAt32Goroutines(b, NewGenerator().GenerateUint64ID)
+}
+
+// BenchmarkGenerateUint64ID_ShardedCounter benchmarks 32 goroutines spread
+// over 16 counter shards.
+func BenchmarkGenerateUint64ID_ShardedCounter(b *testing.B) {
+	benchmarkAt32Goroutines(b, NewGenerator(WithShardedCounter(16)).GenerateUint64ID)
+}
@@ -0,0 +1,71 @@
+// Package tsuniqid - Hash-prefixed IDs for MongoDB shard keys
+package tsuniqid
+
+import (
+	"errors"
+	"time"
+)
+
+// Bit layout constants for shard keys.
+//
+// A shard key is a regular uint64 ID whose top 8 bits (machine and instance
+// IDs) are XORed with a mix of the lower 56 bits (timestamp and counter).
+const (
+	// ShardKeyPrefixShift is the number of bits to shift the hashed prefix
+	ShardKeyPrefixShift = InstanceIDShift
+
+	// shardKeySequenceMask selects the bits the prefix hash is computed over
+	shardKeySequenceMask = 1<<ShardKeyPrefixShift - 1
+)
+
+// ErrShardKeyUnsupported is returned by GenerateMongoShardKey on generators
+// whose IDs do not use the default layout with millisecond timestamps, which
+// ShardKeyTimestamp decodes.
+var ErrShardKeyUnsupported = errors.New("shard keys need the default layout with millisecond timestamps and without parity, insert spreading or Snowflake bit order")
+
+// GenerateMongoShardKey creates a unique uint64 identifier suitable as a
+// MongoDB shard key. Monotonic keys send every insert to the same chunk; a
+// shard key instead starts with 8 bits that depend on a hash of the rest of
+// the ID, spreading consecutive keys evenly over the key space, even those
+// generated in the same millisecond.
+//
+// The prefix is the machine and instance IDs XORed with the hash, so keys
+// from different generators remain distinct, and the timestamp bits are left
+// in place so ShardKeyTimestamp can recover the generation time. Shard keys
+// are never interleaved.
+//
+// Only generators with the default layout, millisecond timestamps and no
+// parity bit, insert spreading or Snowflake bit order can generate shard
+// keys; bit interleaving is undone before the prefix is hashed.
+//
+// Returns:
+//   - uint64: A unique, hash-prefixed uint64 identifier
+//   - error: ErrShardKeyUnsupported if the generator cannot generate shard
+//     keys
+func (g *IDGenerator) GenerateMongoShardKey() (uint64, error) {
+	if g.layout != DefaultLayout || g.resolution != time.Millisecond ||
+		g.parity || g.spreadBits > 0 || g.snowflake {
+		return 0, ErrShardKeyUnsupported
+	}
+
+	id := g.GenerateUint64ID()
+	if g.interleave {
+		id = DeinterleaveBits(id)
+	}
+
+	// Every bit of the mixer's output depends on every input bit, so keys
+	// that differ only in their low counter bits get unrelated prefixes
+	prefix := InterleaveBits(id&shardKeySequenceMask) >> ShardKeyPrefixShift
+	return id ^ (prefix << ShardKeyPrefixShift), nil
+}
+
+// ShardKeyTimestamp extracts the generation time from a shard key created by
+// GenerateMongoShardKey, assuming the timestamp is relative to the Unix epoch.
+//
+// Parameters:
+//   - key: A shard key created by GenerateMongoShardKey
+//
+// Returns: The time at which the key was generated, with millisecond precision
+func ShardKeyTimestamp(key uint64) time.Time {
+	return DecodeUint64(key).Time()
+}
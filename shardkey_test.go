@@ -0,0 +1,140 @@
+package tsuniqid
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestIDGenerator_GenerateMongoShardKey tests that shard keys keep their
+// timestamp, stay unique across generators and have uniformly spread prefixes.
+func TestIDGenerator_GenerateMongoShardKey(t *testing.T) {
+	const (
+		countPerGenerator = 32000
+		prefixes          = 256
+	)
+
+	// Monotonic generators with a slow clock produce maximally clustered IDs
+	start := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	generators := []*IDGenerator{
+		NewGenerator(WithClock(newFakeClock(start, time.Millisecond).Now), WithMonotonic(), WithMachineID(1)),
+		NewGenerator(WithClock(newFakeClock(start, time.Millisecond).Now), WithMonotonic(), WithMachineID(2)),
+	}
+
+	seen := make(map[uint64]bool)
+	var counts [prefixes]int
+	for i := 0; i < countPerGenerator; i++ {
+		want := start.Add(time.Duration(i) * time.Millisecond)
+
+		for _, gen := range generators {
+			key, err := gen.GenerateMongoShardKey()
+			if err != nil {
+				t.Fatalf("GenerateMongoShardKey failed: %v", err)
+			}
+
+			if seen[key] {
+				t.Fatalf("Duplicate shard key: %d", key)
+			}
+			seen[key] = true
+
+			if ts := ShardKeyTimestamp(key); !ts.Equal(want) {
+				t.Fatalf("Timestamp mismatch: got %v, expected %v", ts, want)
+			}
+			counts[key>>ShardKeyPrefixShift]++
+		}
+	}
+
+	// Every prefix must be within 30% of the ideal share
+	ideal := 2 * countPerGenerator / prefixes
+	for prefix, n := range counts {
+		if n < ideal*7/10 || n > ideal*13/10 {
+			t.Errorf("Prefix %d has %d keys, ideal %d", prefix, n, ideal)
+		}
+	}
+}
+
+// TestIDGenerator_GenerateMongoShardKey_Burst tests that keys generated in
+// the same millisecond, which differ only in their counter bits, still have
+// spread prefixes.
+func TestIDGenerator_GenerateMongoShardKey_Burst(t *testing.T) {
+	const (
+		count    = 4096
+		prefixes = 256
+	)
+
+	pinned := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	gen := NewGenerator(WithClock(func() time.Time { return pinned }), WithMachineID(1), WithInstanceID(1))
+
+	var counts [prefixes]int
+	for i := 0; i < count; i++ {
+		key, err := gen.GenerateMongoShardKey()
+		if err != nil {
+			t.Fatalf("GenerateMongoShardKey failed: %v", err)
+		}
+		counts[key>>ShardKeyPrefixShift]++
+	}
+
+	// Every prefix must be used, and none may take more than three times its share
+	ideal := count / prefixes
+	for prefix, n := range counts {
+		if n == 0 || n > 3*ideal {
+			t.Errorf("Prefix %d has %d keys, ideal %d", prefix, n, ideal)
+		}
+	}
+}
+
+// TestIDGenerator_GenerateMongoShardKey_Options tests that shard keys from
+// generators with supported options decode to their generation time, and
+// that generators whose IDs ShardKeyTimestamp cannot decode reject them.
+func TestIDGenerator_GenerateMongoShardKey_Options(t *testing.T) {
+	start := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	for _, opts := range [][]Option{
+		nil,
+		{WithBitInterleaving()},
+		{WithEnvironment(EnvironmentStaging)},
+		{WithServiceCode(3)},
+		{WithMonotonic()},
+	} {
+		gen := NewGenerator(append(opts, WithClock(newFakeClock(start, time.Millisecond).Now))...)
+		for i := 0; i < 100; i++ {
+			key, err := gen.GenerateMongoShardKey()
+			if err != nil {
+				t.Fatalf("GenerateMongoShardKey failed: %v", err)
+			}
+
+			want := start.Add(time.Duration(i) * time.Millisecond)
+			if ts := ShardKeyTimestamp(key); !ts.Equal(want) {
+				t.Fatalf("Timestamp mismatch with %v: got %v, expected %v", gen, ts, want)
+			}
+		}
+	}
+
+	layout := Layout{MachineBits: 6, InstanceBits: 6, TimestampBits: 40, CounterBits: 12}
+	custom, err := NewGeneratorWithLayout(layout)
+	if err != nil {
+		t.Fatalf("NewGeneratorWithLayout failed: %v", err)
+	}
+	snowflake, err := NewSnowflakeGenerator(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), 1)
+	if err != nil {
+		t.Fatalf("NewSnowflakeGenerator failed: %v", err)
+	}
+
+	testCases := []struct {
+		name string
+		gen  *IDGenerator
+	}{
+		{name: "Custom layout", gen: custom},
+		{name: "Snowflake", gen: snowflake},
+		{name: "Parity", gen: NewGenerator(WithParity())},
+		{name: "Insert spreading", gen: NewGenerator(WithInsertSpreading(4))},
+		{name: "Microsecond resolution", gen: NewGenerator(WithTimestampResolution(time.Microsecond))},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := tc.gen.GenerateMongoShardKey(); !errors.Is(err, ErrShardKeyUnsupported) {
+				t.Errorf("Expected ErrShardKeyUnsupported, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package tsuniqid
+
+import (
+	"testing"
+	"time"
+)
+
+// TestNewSnowflakeGenerator tests that IDs follow the Snowflake bit layout
+// and decode with both ParseSnowflake and the generator.
+func TestNewSnowflakeGenerator(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	clock := newFakeClock(now, 0)
+
+	gen, err := NewSnowflakeGenerator(DiscordSnowflakeEpoch, 0x2a5, WithClock(clock.Now))
+	if err != nil {
+		t.Fatalf("Failed to create generator: %v", err)
+	}
+
+	id := gen.GenerateUint64ID()
+	if int64(id) < 0 {
+		t.Errorf("Snowflake ID %d has the sign bit set", id)
+	}
+
+	// The fields sit exactly where Snowflake consumers expect them
+	wantTimestamp := uint64(now.Sub(DiscordSnowflakeEpoch) / time.Millisecond)
+	if ts := id >> SnowflakeTimestampShift; ts != wantTimestamp {
+		t.Errorf("Expected timestamp %d, got %d", wantTimestamp, ts)
+	}
+	if worker := (id >> SnowflakeWorkerShift) & MaxSnowflakeWorkerID; worker != 0x2a5 {
+		t.Errorf("Expected worker ID 0x2a5, got %#x", worker)
+	}
+
+	at, workerID, sequence := ParseSnowflake(id, DiscordSnowflakeEpoch)
+	if !at.Equal(now) || workerID != 0x2a5 || sequence != 0 {
+		t.Errorf("ParseSnowflake returned %v, %#x, %d", at, workerID, sequence)
+	}
+	if !gen.TimeOf(id).Equal(now) {
+		t.Errorf("Generator decoded time %v, expected %v", gen.TimeOf(id), now)
+	}
+
+	// The sequence restarts every millisecond
+	second := gen.GenerateUint64ID()
+	clock.Set(now.Add(time.Millisecond), 0)
+	third := gen.GenerateUint64ID()
+	if second != id+1 || third&MaxSnowflakeSequence != 0 || third <= second {
+		t.Errorf("Unexpected sequence: %d, %d, %d", id, second, third)
+	}
+
+	// Time range bounds use the Snowflake bit order
+	minID, maxID := gen.IDRangeForTime(now, now)
+	if id < minID || second > maxID || third <= maxID {
+		t.Errorf("Range [%d, %d] does not match IDs %d, %d, %d", minID, maxID, id, second, third)
+	}
+}
+
+// TestParseSnowflake tests decoding a known Discord Snowflake.
+func TestParseSnowflake(t *testing.T) {
+	// Example from the Discord API reference
+	at, workerID, sequence := ParseSnowflake(175928847299117063, DiscordSnowflakeEpoch)
+	if want := time.UnixMilli(1462015105796); !at.Equal(want) {
+		t.Errorf("Expected time %v, got %v", want, at)
+	}
+	// Discord splits the worker ID into worker 1 and process 0
+	if workerID != 1<<5 || sequence != 7 {
+		t.Errorf("Expected worker 0x20 and sequence 7, got %#x and %d", workerID, sequence)
+	}
+}
+
+// TestNewSnowflakeGenerator_Invalid tests worker ID and epoch validation.
+func TestNewSnowflakeGenerator_Invalid(t *testing.T) {
+	if _, err := NewSnowflakeGenerator(DiscordSnowflakeEpoch, MaxSnowflakeWorkerID+1); err == nil {
+		t.Errorf("Expected error for an out-of-range worker ID")
+	}
+	if _, err := NewSnowflakeGenerator(time.Date(1900, 1, 1, 0, 0, 0, 0, time.UTC), 1); err == nil {
+		t.Errorf("Expected error for an epoch the 41-bit timestamp cannot reach")
+	}
+	if _, err := NewSnowflakeGenerator(time.Now().Add(time.Hour), 1); err == nil {
+		t.Errorf("Expected error for a future epoch")
+	}
+}
@@ -0,0 +1,32 @@
+// Package tsuniqid - Insert spreading across index buckets
+package tsuniqid
+
+// UnspreadID recovers the original ID from one generated with
+// WithInsertSpreading, so IDs can be sorted back into generation order for
+// range scans. IDs from other generators are returned unchanged.
+//
+// Parameters:
+//   - id: An identifier generated by this generator
+//
+// Returns: The identifier before spreading
+func (g *IDGenerator) UnspreadID(id uint64) uint64 {
+	if g.spreadBits == 0 {
+		return id
+	}
+
+	return g.format().spread(id)
+}
+
+// BucketOf returns the insert bucket of an ID generated with
+// WithInsertSpreading: the top bits that spreading varies.
+//
+// Parameters:
+//   - id: An identifier generated by this generator
+//
+// Returns: The bucket in [0, buckets), or 0 without insert spreading
+func (g *IDGenerator) BucketOf(id uint64) int {
+	if g.spreadBits == 0 {
+		return 0
+	}
+	return int(id >> g.format().spreadShift() & bitMask(g.spreadBits))
+}
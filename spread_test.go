@@ -0,0 +1,67 @@
+package tsuniqid
+
+import (
+	"testing"
+	"time"
+)
+
+// TestWithInsertSpreading tests that consecutive IDs spread evenly across
+// buckets while their timestamps stay extractable and their original order
+// is recoverable.
+func TestWithInsertSpreading(t *testing.T) {
+	const buckets, count = 16, 4096
+	gen := NewGenerator(WithInsertSpreading(buckets), WithMonotonic(), WithParity())
+
+	before := time.Now().Truncate(time.Millisecond)
+	ids := make([]uint64, count)
+	for i := range ids {
+		ids[i] = gen.GenerateUint64ID()
+	}
+	after := time.Now()
+
+	perBucket := make([]int, buckets)
+	unspread := make([]uint64, count)
+	for i, id := range ids {
+		perBucket[gen.BucketOf(id)]++
+
+		if ts := DecodeUint64(id).Time(); ts.Before(before) || ts.After(after) {
+			t.Errorf("Timestamp %v of %x outside [%v, %v]", ts, id, before, after)
+		}
+		if !CheckParity(id) {
+			t.Errorf("Parity lost in spread ID %x", id)
+		}
+
+		unspread[i] = gen.UnspreadID(id)
+		if components := gen.Decode(id); components.MachineID != gen.MachineID() || components.InstanceID != gen.InstanceID() {
+			t.Errorf("Identity not recovered from %x: %+v", id, components)
+		}
+	}
+
+	for bucket, n := range perBucket {
+		if n < count/buckets/2 {
+			t.Errorf("Bucket %d received only %d of %d inserts", bucket, n, count)
+		}
+	}
+	if ok, index := AssertMonotonic(ids); ok {
+		t.Errorf("Spread IDs are still monotonic")
+	} else if ok, index = AssertMonotonic(unspread); !ok {
+		t.Errorf("Unspread IDs not in generation order at %d", index)
+	}
+
+	// Without spreading, IDs and buckets are unchanged
+	plain := NewGenerator(WithInsertSpreading(1))
+	if id := plain.GenerateUint64ID(); plain.UnspreadID(id) != id || plain.BucketOf(id) != 0 {
+		t.Errorf("Single bucket changed ID %x", id)
+	}
+
+	for _, opts := range [][]Option{
+		{WithInsertSpreading(0)},
+		{WithInsertSpreading(3)},
+		{WithInsertSpreading(512)},
+		{WithInsertSpreading(4), WithBitInterleaving()},
+	} {
+		if _, err := NewGeneratorWithError(opts...); err == nil {
+			t.Errorf("Expected error for options %d", len(opts))
+		}
+	}
+}
@@ -0,0 +1,62 @@
+// Package tsuniqid - Streaming decoder for binary ID dumps
+package tsuniqid
+
+import (
+	"encoding/binary"
+	"io"
+)
+
+// idByteLength is the size of a binary uint64 ID
+const idByteLength = 8
+
+// DecodeStream reads big-endian 8-byte IDs from r one at a time, for
+// processing binary ID dumps without loading them into memory.
+//
+// Each call of the returned function reads the next ID. Short reads from r
+// are retried until 8 bytes arrive. At the end of the stream it returns
+// io.EOF, or io.ErrUnexpectedEOF if the stream ends partway through an ID.
+// Wrap r in a bufio.Reader to avoid one read per ID.
+//
+// Parameters:
+//   - r: The reader to decode IDs from
+//
+// Returns: A function yielding successive IDs, and an error at the end of the stream
+func DecodeStream(r io.Reader) func() (uint64, error) {
+	var buf [idByteLength]byte
+	return func() (uint64, error) {
+		if _, err := io.ReadFull(r, buf[:]); err != nil {
+			return 0, err
+		}
+		return binary.BigEndian.Uint64(buf[:]), nil
+	}
+}
+
+// Read fills p with consecutive uint64 IDs encoded as 8 big-endian bytes,
+// implementing io.Reader so IDs can be piped into writers or wrapped in a
+// bufio.Reader. When p ends partway through an ID, the remaining bytes of
+// that ID are returned first by the next Read. Read never fails.
+//
+// Parameters:
+//   - p: The buffer to fill
+//
+// Returns:
+//   - int: The number of bytes written, always len(p)
+//   - error: Always nil
+func (g *IDGenerator) Read(p []byte) (int, error) {
+	g.readMu.Lock()
+	defer g.readMu.Unlock()
+
+	n := 0
+	for n < len(p) {
+		if g.readPos == idByteLength {
+			binary.BigEndian.PutUint64(g.readBuf[:], g.GenerateUint64ID())
+			g.readPos = 0
+		}
+
+		copied := copy(p[n:], g.readBuf[g.readPos:])
+		g.readPos += copied
+		n += copied
+	}
+
+	return n, nil
+}
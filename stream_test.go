@@ -0,0 +1,99 @@
+package tsuniqid
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"testing"
+	"testing/iotest"
+)
+
+// TestDecodeStream tests that IDs written in big-endian order are decoded in
+// sequence, even from a reader returning one byte at a time.
+func TestDecodeStream(t *testing.T) {
+	gen := NewGenerator()
+	ids := []uint64{0, 1, ^uint64(0)}
+	for i := 0; i < 100; i++ {
+		ids = append(ids, gen.GenerateUint64ID())
+	}
+
+	var buf bytes.Buffer
+	for _, id := range ids {
+		if err := binary.Write(&buf, binary.BigEndian, id); err != nil {
+			t.Fatalf("Failed to write ID: %v", err)
+		}
+	}
+
+	next := DecodeStream(iotest.OneByteReader(bytes.NewReader(buf.Bytes())))
+	for i, expected := range ids {
+		id, err := next()
+		if err != nil {
+			t.Fatalf("Unexpected error at ID %d: %v", i, err)
+		}
+		if id != expected {
+			t.Errorf("ID %d mismatch: got %d, expected %d", i, id, expected)
+		}
+	}
+
+	if _, err := next(); err != io.EOF {
+		t.Errorf("Expected io.EOF, got %v", err)
+	}
+
+	// A truncated trailing ID is reported
+	next = DecodeStream(bytes.NewReader(buf.Bytes()[:12]))
+	if id, err := next(); err != nil || id != ids[0] {
+		t.Errorf("Expected first ID, got %d, %v", id, err)
+	}
+	if _, err := next(); !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("Expected io.ErrUnexpectedEOF, got %v", err)
+	}
+}
+
+// TestIDGenerator_Read tests that reads of any size produce a stream of
+// whole, consecutive IDs that DecodeStream can decode.
+func TestIDGenerator_Read(t *testing.T) {
+	gen := NewGenerator(WithMonotonic())
+
+	// Read in odd chunk sizes so IDs straddle Read calls
+	var buf bytes.Buffer
+	for _, size := range []int{3, 5, 1, 8, 13, 0, 7, 16, 11} {
+		chunk := make([]byte, size)
+		if n, err := gen.Read(chunk); n != size || err != nil {
+			t.Fatalf("Read(%d) = %d, %v", size, n, err)
+		}
+		buf.Write(chunk)
+	}
+
+	// The total is a whole number of IDs, since 64 bytes were read
+	next := DecodeStream(&buf)
+	var prev uint64
+	count := 0
+	for {
+		id, err := next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if id <= prev {
+			t.Errorf("ID %d not greater than previous %d", id, prev)
+		}
+		if machineID := (id >> MachineIDShift) & MaxMachineID; machineID != gen.machineID {
+			t.Errorf("ID %x has machine ID %d, expected %d", id, machineID, gen.machineID)
+		}
+		prev = id
+		count++
+	}
+	if count != 8 {
+		t.Errorf("Expected 8 IDs, got %d", count)
+	}
+
+	// Readers compose with the standard library
+	var ids [4 * idByteLength]byte
+	if _, err := io.ReadFull(bufio.NewReader(gen), ids[:]); err != nil {
+		t.Errorf("Unexpected error reading through bufio: %v", err)
+	}
+}
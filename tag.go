@@ -0,0 +1,63 @@
+// Package tsuniqid - Deterministic visual tags for displaying IDs
+package tsuniqid
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash/fnv"
+)
+
+// tagEmojis is the palette used by EmojiTag. The emojis are chosen to be
+// visually distinct from each other at small sizes.
+var tagEmojis = []string{
+	"🍎", "🍌", "🍇", "🍉", "🍒", "🍋", "🥝", "🥕",
+	"🌽", "🍄", "🌵", "🌻", "🌙", "⭐", "🔥", "💧",
+	"🐶", "🐱", "🐭", "🐰", "🦊", "🐻", "🐼", "🐨",
+	"🐯", "🦁", "🐮", "🐷", "🐸", "🐵", "🐔", "🐧",
+	"🐢", "🐍", "🐙", "🦀", "🐳", "🐝", "🦋", "🐞",
+	"🚗", "🚲", "🚀", "⛵", "🎈", "🎁", "🎸", "🎲",
+	"⚽", "🏀", "🎯", "🔔", "🔑", "💡", "📌", "✂️",
+	"⚓", "🌈", "❄️", "☂️", "🍩", "🍪", "🧀", "🍕",
+}
+
+// hashID hashes the big-endian bytes of an ID with 64-bit FNV-1a, spreading
+// IDs that differ only in their low bits across the whole hash space.
+//
+// Parameters:
+//   - id: The ID to hash
+//
+// Returns: The 64-bit hash of the ID
+func hashID(id uint64) uint64 {
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], id)
+
+	h := fnv.New64a()
+	h.Write(buf[:])
+	return h.Sum64()
+}
+
+// ColorTag derives a stable color from an ID for display in dashboards and
+// logs, helping humans spot repeated IDs at a glance. The same ID always maps
+// to the same color; different IDs usually, but not always, map to different
+// colors.
+//
+// Parameters:
+//   - id: The ID to derive a color from
+//
+// Returns: A hex color in the form "#rrggbb"
+func ColorTag(id uint64) string {
+	return fmt.Sprintf("#%06x", hashID(id)&0xffffff)
+}
+
+// EmojiTag derives a stable emoji from an ID, like ColorTag but for contexts
+// where colors are unavailable, such as plain-text logs. With only 64 emojis,
+// collisions between different IDs are common, so the tag is a visual hint
+// rather than an identifier.
+//
+// Parameters:
+//   - id: The ID to derive an emoji from
+//
+// Returns: An emoji string
+func EmojiTag(id uint64) string {
+	return tagEmojis[hashID(id)%uint64(len(tagEmojis))]
+}
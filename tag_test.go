@@ -0,0 +1,49 @@
+package tsuniqid
+
+import (
+	"regexp"
+	"testing"
+)
+
+// TestColorTag tests that color tags are well-formed, stable for the same ID
+// and usually different for different IDs.
+func TestColorTag(t *testing.T) {
+	gen := NewGenerator()
+	colorPattern := regexp.MustCompile(`^#[0-9a-f]{6}$`)
+
+	colors := make(map[string]bool)
+	const count = 1000
+	for i := 0; i < count; i++ {
+		id := gen.GenerateUint64ID()
+
+		color := ColorTag(id)
+		if !colorPattern.MatchString(color) {
+			t.Errorf("Malformed color tag for %d: %s", id, color)
+		}
+		if again := ColorTag(id); again != color {
+			t.Errorf("Color tag for %d not stable: %s then %s", id, color, again)
+		}
+		colors[color] = true
+	}
+
+	// Consecutive IDs must spread across the color space
+	if len(colors) < count*99/100 {
+		t.Errorf("Too many color collisions: %d distinct colors for %d IDs", len(colors), count)
+	}
+}
+
+// TestEmojiTag tests that emoji tags are stable and use the whole palette.
+func TestEmojiTag(t *testing.T) {
+	emojis := make(map[string]bool)
+	for id := uint64(0); id < 10000; id++ {
+		emoji := EmojiTag(id)
+		if again := EmojiTag(id); again != emoji {
+			t.Errorf("Emoji tag for %d not stable: %s then %s", id, emoji, again)
+		}
+		emojis[emoji] = true
+	}
+
+	if len(emojis) != len(tagEmojis) {
+		t.Errorf("Expected all %d emojis to be used, got %d", len(tagEmojis), len(emojis))
+	}
+}
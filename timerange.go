@@ -0,0 +1,94 @@
+// Package tsuniqid - Time range helpers for ID-keyed queries
+package tsuniqid
+
+import (
+	"time"
+)
+
+// IDRangeForTime computes the smallest and largest uint64 IDs whose
+// timestamps fall within [start, end], for use in BETWEEN queries on
+// ID-keyed tables.
+//
+// Because machine and instance IDs occupy the highest bits, the bounds must
+// span every machine: minID uses machine/instance 0 at the start timestamp
+// and maxID uses the maximum machine/instance at the end timestamp. Every ID
+// generated within the range is guaranteed to fall inside the bounds, but on
+// a multi-machine fleet the bounds also admit IDs outside the time range, so
+// results should be filtered by timestamp when exactness matters.
+//
+// Parameters:
+//   - start: The beginning of the time range (inclusive)
+//   - end: The end of the time range (inclusive)
+//
+// Returns:
+//   - minID: The smallest ID that can carry a timestamp in the range
+//   - maxID: The largest ID that can carry a timestamp in the range
+func IDRangeForTime(start, end time.Time) (minID, maxID uint64) {
+	return idRangeForTime(start, end, 0, time.Millisecond, DefaultLayout)
+}
+
+// IDRangeForTime computes the ID bounds for [start, end] like the package
+// function IDRangeForTime, using this generator's epoch, resolution and
+// layout.
+//
+// Parameters:
+//   - start: The beginning of the time range (inclusive)
+//   - end: The end of the time range (inclusive)
+//
+// Returns:
+//   - minID: The smallest ID that can carry a timestamp in the range
+//   - maxID: The largest ID that can carry a timestamp in the range
+func (g *IDGenerator) IDRangeForTime(start, end time.Time) (minID, maxID uint64) {
+	minID, maxID = idRangeForTime(start, end, g.epoch, g.resolution, g.layout)
+	if g.snowflake {
+		return toSnowflake(minID), toSnowflake(maxID)
+	}
+	return minID, maxID
+}
+
+// idRangeForTime computes the ID bounds for [start, end] relative to an epoch.
+//
+// Parameters:
+//   - start: The beginning of the time range (inclusive)
+//   - end: The end of the time range (inclusive)
+//   - epoch: The epoch in Unix milliseconds
+//   - resolution: The unit of the timestamp field
+//   - layout: The bit layout of the IDs
+//
+// Returns:
+//   - minID: The smallest ID that can carry a timestamp in the range
+//   - maxID: The largest ID that can carry a timestamp in the range
+func idRangeForTime(start, end time.Time, epoch int64, resolution time.Duration, layout Layout) (minID, maxID uint64) {
+	if end.Before(start) {
+		start, end = end, start
+	}
+
+	minID = timestampField(start, epoch, resolution, layout.maxTimestamp()) << layout.timestampShift()
+
+	maxID = layout.identityPrefix(layout.maxMachineID(), layout.maxInstanceID()) |
+		(timestampField(end, epoch, resolution, layout.maxTimestamp()) << layout.timestampShift()) |
+		bitMask(layout.timestampShift())
+
+	return minID, maxID
+}
+
+// timestampField converts a time to the value stored in the timestamp field,
+// clamped to the range the field can represent.
+//
+// Parameters:
+//   - t: The time to convert
+//   - epoch: The epoch in Unix milliseconds
+//   - resolution: The unit of the timestamp field
+//   - maxTimestamp: The largest value the timestamp field can hold
+//
+// Returns: The units since the epoch, clamped to [0, maxTimestamp]
+func timestampField(t time.Time, epoch int64, resolution time.Duration, maxTimestamp uint64) uint64 {
+	units := unitsSince(t, epoch, resolution)
+	if units < 0 {
+		return 0
+	}
+	if uint64(units) > maxTimestamp {
+		return maxTimestamp
+	}
+	return uint64(units)
+}
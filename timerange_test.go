@@ -0,0 +1,81 @@
+package tsuniqid
+
+import (
+	"testing"
+	"time"
+)
+
+// TestIDRangeForTime tests that IDs generated within a time range fall
+// inside the computed bounds, across generators with different machine IDs.
+func TestIDRangeForTime(t *testing.T) {
+	start := time.Now()
+
+	generators := []*IDGenerator{NewGenerator(), NewGenerator(WithMachineID(0)), NewGenerator(WithMachineID(MaxMachineID))}
+
+	var ids []uint64
+	for i := 0; i < 1000; i++ {
+		for _, gen := range generators {
+			ids = append(ids, gen.GenerateUint64ID())
+		}
+	}
+
+	end := time.Now()
+	minID, maxID := IDRangeForTime(start, end)
+
+	for _, id := range ids {
+		if id < minID || id > maxID {
+			t.Errorf("ID %d outside range [%d, %d]", id, minID, maxID)
+		}
+	}
+
+	// IDs from before the range with machine ID 0 must fall below the lower bound
+	before := (timestampField(start.Add(-time.Second), 0, time.Millisecond, MaxTimestamp) << TimestampShift) | MaxCounter
+	if before >= minID {
+		t.Errorf("ID %d from before the range is not below minID %d", before, minID)
+	}
+
+	// Reversed arguments describe the same range
+	if revMin, revMax := IDRangeForTime(end, start); revMin != minID || revMax != maxID {
+		t.Errorf("Reversed range mismatch: [%d, %d] vs [%d, %d]", revMin, revMax, minID, maxID)
+	}
+}
+
+// TestIDGenerator_IDRangeForTime tests range bounds for a generator with a
+// custom epoch.
+func TestIDGenerator_IDRangeForTime(t *testing.T) {
+	gen := NewGenerator(WithEpoch(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)))
+
+	start := time.Now()
+	id := gen.GenerateUint64ID()
+	end := time.Now()
+
+	minID, maxID := gen.IDRangeForTime(start, end)
+	if id < minID || id > maxID {
+		t.Errorf("ID %d outside range [%d, %d]", id, minID, maxID)
+	}
+
+	// Custom-epoch timestamps are smaller than Unix-epoch ones
+	if unixMin, _ := IDRangeForTime(start, end); minID >= unixMin {
+		t.Errorf("Custom-epoch minID %d not below Unix-epoch minID %d", minID, unixMin)
+	}
+}
+
+// TestIDGenerator_IDRangeForTime_Watermark tests that the upper bound covers
+// the counter and watermark fields below the timestamp.
+func TestIDGenerator_IDRangeForTime_Watermark(t *testing.T) {
+	clock := newFakeClock(time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC), 0)
+	gen, err := NewGeneratorWithLayout(WatermarkLayout, WithClock(clock.Now), WithMonotonic(),
+		WithMachineID(WatermarkLayout.maxMachineID()), WithInstanceID(WatermarkLayout.maxInstanceID()))
+	if err != nil {
+		t.Fatalf("NewGeneratorWithLayout failed: %v", err)
+	}
+
+	// Every ID shares the end timestamp, so only the low fields vary
+	now := clock.Now()
+	minID, maxID := gen.IDRangeForTime(now, now)
+	for i := 0; i < 50; i++ {
+		if id := gen.GenerateUint64ID(); id < minID || id > maxID {
+			t.Errorf("ID %x outside range [%x, %x]", id, minID, maxID)
+		}
+	}
+}
@@ -0,0 +1,179 @@
+// Package tsuniqid - IDs that carry what is needed to decode them
+package tsuniqid
+
+import (
+	"time"
+)
+
+// idFormat captures the generator settings needed to decode its IDs, so
+// IDs can be decoded after the generator is gone.
+type idFormat struct {
+	layout     Layout
+	epoch      int64
+	interleave bool
+	parity     bool
+	spreadBits uint
+	resolution time.Duration
+	snowflake  bool
+}
+
+// format captures the generator's decoding settings.
+//
+// Returns: The generator's ID format
+func (g *IDGenerator) format() idFormat {
+	return idFormat{
+		layout:     g.layout,
+		epoch:      g.epoch,
+		interleave: g.interleave,
+		parity:     g.parity,
+		spreadBits: g.spreadBits,
+		resolution: g.resolution,
+		snowflake:  g.snowflake,
+	}
+}
+
+// decode decomposes an ID in this format, undoing the Snowflake bit order,
+// interleaving and insert spreading first.
+//
+// Parameters:
+//   - id: The identifier to decompose
+//
+// Returns: The decoded components
+func (f idFormat) decode(id uint64) IDComponents {
+	if f.snowflake {
+		id = fromSnowflake(id)
+	}
+	if f.interleave {
+		id = DeinterleaveBits(id)
+	}
+	if f.spreadBits > 0 {
+		id = f.spread(id)
+	}
+
+	machineID, instanceID, timestamp, counter := f.layout.ParseUint64ID(id)
+	return IDComponents{
+		MachineID:  machineID,
+		InstanceID: instanceID,
+		Timestamp:  timestamp,
+		Counter:    counter,
+		Watermark:  f.layout.WatermarkOf(id),
+		Epoch:      time.UnixMilli(f.epoch),
+		Resolution: f.resolution,
+	}
+}
+
+// spread XORs the top spreadBits of an ID, below any reserved bits, with a
+// hash of the timestamp and lower fields. The parity bit is excluded from
+// the hash, since it is set after spreading. The hashed bits are left
+// unchanged, so spreading an ID twice restores it.
+//
+// Parameters:
+//   - id: The identifier to spread
+//
+// Returns: The spread identifier
+func (f idFormat) spread(id uint64) uint64 {
+	low := id & bitMask(f.layout.instanceShift())
+	if f.parity {
+		low &^= 1
+	}
+
+	// The finalizer behind InterleaveBits avalanches into the top bits even
+	// when only the lowest counter bits change
+	bucket := InterleaveBits(low) >> (64 - f.spreadBits)
+	return id ^ (bucket << f.spreadShift())
+}
+
+// spreadShift returns the number of bits to shift the spread bucket.
+//
+// Returns: The shift of the highest non-reserved bits
+func (f idFormat) spreadShift() uint {
+	return 64 - f.layout.ReservedBits - f.spreadBits
+}
+
+// TypedID is an ID returned by GenerateID that remembers the format of the
+// generator that created it, so it can be decoded and ordered by time
+// without access to that generator.
+//
+// It serializes exactly like the embedded ID, through the promoted JSON,
+// text and SQL methods. A TypedID restored by one of them has no format and
+// decodes with the default layout and the Unix epoch.
+type TypedID struct {
+	ID
+	format *idFormat
+}
+
+// Uint64 returns the raw uint64 value of the ID.
+//
+// Returns: The ID as a uint64
+func (t TypedID) Uint64() uint64 {
+	return uint64(t.ID)
+}
+
+// Decode decomposes the ID using the layout and epoch of the generator that
+// created it, undoing bit interleaving and insert spreading.
+//
+// Returns: The decoded components
+func (t TypedID) Decode() IDComponents {
+	if t.format == nil {
+		return DecodeUint64(uint64(t.ID))
+	}
+	return t.format.decode(uint64(t.ID))
+}
+
+// Time returns the time at which the ID was generated.
+//
+// Returns: The generation time, with the precision of the generator's
+// resolution: milliseconds, or microseconds with WithTimestampResolution
+func (t TypedID) Time() time.Time {
+	return t.Decode().Time()
+}
+
+// Compare orders IDs by generation time, then by counter, then by raw value,
+// so IDs from generators with different epochs or layouts compare by when
+// they were created.
+//
+// Parameters:
+//   - other: The ID to compare with
+//
+// Returns: -1 if t comes before other, 1 if after, and 0 if they are equal
+func (t TypedID) Compare(other TypedID) int {
+	a, b := t.Decode(), other.Decode()
+	switch ta, tb := a.Time(), b.Time(); {
+	case ta.Before(tb):
+		return -1
+	case ta.After(tb):
+		return 1
+	}
+
+	switch {
+	case a.Counter < b.Counter:
+		return -1
+	case a.Counter > b.Counter:
+		return 1
+	case t.ID < other.ID:
+		return -1
+	case t.ID > other.ID:
+		return 1
+	}
+	return 0
+}
+
+// Before reports whether t was generated before other, as ordered by Compare.
+//
+// Parameters:
+//   - other: The ID to compare with
+//
+// Returns: True if t comes before other
+func (t TypedID) Before(other TypedID) bool {
+	return t.Compare(other) < 0
+}
+
+// After reports whether t was generated after other, as ordered by Compare.
+//
+// Parameters:
+//   - other: The ID to compare with
+//
+// Returns: True if t comes after other
+func (t TypedID) After(other TypedID) bool {
+	return t.Compare(other) > 0
+}
@@ -0,0 +1,65 @@
+package tsuniqid
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// TestTypedID tests that typed IDs print as hex, order by time and decode
+// with their generator's format after the generator is gone.
+func TestTypedID(t *testing.T) {
+	epoch := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := newFakeClock(time.Now(), time.Millisecond)
+	layout := Layout{MachineBits: 6, InstanceBits: 2, TimestampBits: 40, CounterBits: 16}
+
+	gen, err := NewGeneratorWithLayout(layout, WithEpoch(epoch), WithClock(clock.Now),
+		WithMachineID(0x2a), WithBitInterleaving())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	first, second := gen.GenerateID(), gen.GenerateID()
+	expected := gen.Decode(first.Uint64())
+	gen = nil
+
+	if s := first.String(); s != fmt.Sprintf("%016x", first.Uint64()) {
+		t.Errorf("String mismatch: got %s", s)
+	}
+	if s := fmt.Sprint(first); s != first.String() {
+		t.Errorf("fmt does not use String: got %s", s)
+	}
+
+	if got := first.Decode(); got != expected || got.MachineID != 0x2a {
+		t.Errorf("Decode mismatch: got %+v, expected %+v", got, expected)
+	}
+	if !first.Time().Equal(expected.Time()) {
+		t.Errorf("Time mismatch: got %v, expected %v", first.Time(), expected.Time())
+	}
+
+	if !first.Before(second) || first.After(second) || !second.After(first) {
+		t.Errorf("Expected %s before %s", first, second)
+	}
+	if first.Compare(first) != 0 {
+		t.Errorf("Expected an ID to compare equal to itself")
+	}
+
+	// IDs from the default generator order by time against the custom epoch
+	later := NewGenerator(WithClock(newFakeClock(time.Now().Add(time.Hour), 0).Now)).GenerateID()
+	if !second.Before(later) {
+		t.Errorf("Expected %v before %v", second.Time(), later.Time())
+	}
+
+	// Serialization goes through the embedded ID
+	data, err := json.Marshal(first)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	var restored TypedID
+	if err := json.Unmarshal(data, &restored); err != nil || restored.ID != first.ID {
+		t.Errorf("JSON round-trip mismatch: got %v, %v", restored, err)
+	}
+	if restored.Decode() != DecodeUint64(first.Uint64()) {
+		t.Errorf("Restored ID does not decode with the default layout")
+	}
+}
@@ -0,0 +1,123 @@
+// Package tsuniqid - ULID-compatible identifiers
+package tsuniqid
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ULID format constants.
+//
+// A ULID is 128 bits: a 48-bit big-endian Unix timestamp in milliseconds
+// followed by 80 random bits, encoded as 26 characters of Crockford base32.
+// Because the timestamp comes first, ULIDs sort lexicographically by time.
+const (
+	// ULIDLength is the length of a ULID string
+	ULIDLength = 26
+
+	// ULIDCharSet is the Crockford base32 alphabet used by ULIDs
+	ULIDCharSet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+	// ulidTimestampLength is the number of characters encoding the timestamp
+	ulidTimestampLength = 10
+
+	// ulidTimestampBytes is the length of the ULID timestamp in bytes
+	ulidTimestampBytes = 6
+
+	// ulidRandomBytes is the length of the ULID randomness in bytes
+	ulidRandomBytes = 10
+)
+
+// GenerateULID creates an identifier in the 26-character ULID format, for
+// interoperating with systems that consume ULIDs. The timestamp comes from
+// the generator's clock, ignoring any custom epoch, and the randomness from
+// crypto/rand. If the system entropy source fails, the randomness falls back
+// to the generator's math/rand source.
+//
+// Returns: A 26-character Crockford base32 ULID
+func (g *IDGenerator) GenerateULID() string {
+	var raw [ulidTimestampBytes + ulidRandomBytes]byte
+
+	var timestamp [8]byte
+	binary.BigEndian.PutUint64(timestamp[:], uint64(g.clock().UnixMilli()))
+	copy(raw[:ulidTimestampBytes], timestamp[8-ulidTimestampBytes:])
+
+	random := raw[ulidTimestampBytes:]
+	if _, err := cryptoRead(random); err != nil {
+		if !g.singleThreaded {
+			g.mu.Lock()
+			defer g.mu.Unlock()
+		}
+		g.rng.Read(random)
+	}
+
+	return encodeULID(raw)
+}
+
+// ParseULIDTime extracts the timestamp from a ULID string. Lowercase
+// characters are accepted, as the ULID specification is case-insensitive.
+//
+// Parameters:
+//   - s: A 26-character ULID
+//
+// Returns:
+//   - time.Time: The time embedded in the ULID, with millisecond precision
+//   - error: An error if s is not a valid ULID
+func ParseULIDTime(s string) (time.Time, error) {
+	if len(s) != ULIDLength {
+		return time.Time{}, fmt.Errorf("invalid ULID length %d, expected %d", len(s), ULIDLength)
+	}
+
+	var timestamp uint64
+	for i := 0; i < len(s); i++ {
+		digit := strings.IndexByte(ULIDCharSet, upperASCII(s[i]))
+		if digit < 0 {
+			return time.Time{}, fmt.Errorf("invalid base32 character %q in ULID %s", s[i], s)
+		}
+		if i < ulidTimestampLength {
+			timestamp = timestamp<<5 | uint64(digit)
+		}
+	}
+
+	// 26 characters hold 130 bits, so the first character must not exceed 7
+	if s[0] > '7' {
+		return time.Time{}, fmt.Errorf("ULID %s overflows 128 bits", s)
+	}
+
+	return time.UnixMilli(int64(timestamp)), nil
+}
+
+// encodeULID encodes raw ULID bytes as Crockford base32.
+//
+// Parameters:
+//   - raw: The 16 ULID bytes
+//
+// Returns: The 26-character base32 representation
+func encodeULID(raw [ulidTimestampBytes + ulidRandomBytes]byte) string {
+	hi := binary.BigEndian.Uint64(raw[:8])
+	lo := binary.BigEndian.Uint64(raw[8:])
+
+	result := make([]byte, ULIDLength)
+	for i := ULIDLength - 1; i >= 0; i-- {
+		result[i] = ULIDCharSet[lo&0x1f]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+
+	return string(result)
+}
+
+// upperASCII converts an ASCII lowercase letter to uppercase.
+//
+// Parameters:
+//   - c: The character to convert
+//
+// Returns: The uppercase character, or c unchanged
+func upperASCII(c byte) byte {
+	if c >= 'a' && c <= 'z' {
+		return c - 'a' + 'A'
+	}
+	return c
+}
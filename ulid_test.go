@@ -0,0 +1,62 @@
+package tsuniqid
+
+import (
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestIDGenerator_GenerateULID tests that ULIDs have the standard length and
+// alphabet, round-trip their timestamp and sort by time.
+func TestIDGenerator_GenerateULID(t *testing.T) {
+	start := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	clock := newFakeClock(start, time.Millisecond)
+	gen := NewGenerator(WithClock(clock.Now))
+
+	ids := make([]string, 1000)
+	for i := range ids {
+		id := gen.GenerateULID()
+		ids[i] = id
+
+		if len(id) != ULIDLength {
+			t.Fatalf("ULID has wrong length: %s (length: %d)", id, len(id))
+		}
+		for _, c := range id {
+			if !strings.ContainsRune(ULIDCharSet, c) {
+				t.Errorf("ULID %s contains invalid character %q", id, c)
+			}
+		}
+
+		ts, err := ParseULIDTime(id)
+		if err != nil {
+			t.Errorf("Failed to parse %s: %v", id, err)
+		} else if want := start.Add(time.Duration(i) * time.Millisecond); !ts.Equal(want) {
+			t.Errorf("Timestamp mismatch for %s: got %v, expected %v", id, ts, want)
+		}
+	}
+
+	if !sort.StringsAreSorted(ids) {
+		t.Errorf("ULIDs with increasing timestamps are not lexicographically sorted")
+	}
+}
+
+// TestParseULIDTime tests decoding of known and malformed ULIDs.
+func TestParseULIDTime(t *testing.T) {
+	// Reference ULID from the ULID specification
+	for _, s := range []string{"01ARZ3NDEKTSV4RRFFQ69G5FAV", "01arz3ndektsv4rrffq69g5fav"} {
+		ts, err := ParseULIDTime(s)
+		if err != nil {
+			t.Fatalf("Failed to parse reference ULID %s: %v", s, err)
+		}
+		if want := time.UnixMilli(1469922850259); !ts.Equal(want) {
+			t.Errorf("Reference ULID time mismatch: got %v, expected %v", ts, want)
+		}
+	}
+
+	for _, s := range []string{"", "01ARZ3NDEK", "01ARZ3NDEKTSV4RRFFQ69G5FAU", "81ARZ3NDEKTSV4RRFFQ69G5FAV"} {
+		if _, err := ParseULIDTime(s); err == nil {
+			t.Errorf("Expected error parsing %q", s)
+		}
+	}
+}
@@ -11,9 +11,11 @@ package tsuniqid
 import (
 	"crypto/sha1"
 	"encoding/binary"
+	"errors"
 	"fmt"
+	"log/slog"
 	"math/rand"
-	"os"
+	"runtime"
 	"strconv"
 	"sync"
 	"sync/atomic"
@@ -34,9 +36,15 @@ const (
 	// MaxTimestamp represents the maximum timestamp value (42 bits)
 	MaxTimestamp = 0x3ffffffffff
 
-	// RandomSuffixLength is the length of random suffix for string IDs
+	// RandomSuffixLength is the default length of random suffix for string IDs
 	RandomSuffixLength = 8
 
+	// MaxUsefulSuffixLength is the suffix length above which WithSuffixLength
+	// logs a warning. 25 characters from CharSet carry about 129 bits of
+	// entropy; the uint64 part already makes IDs unique, so longer suffixes
+	// add size without making IDs meaningfully harder to guess or collide.
+	MaxUsefulSuffixLength = 25
+
 	// CharSet contains characters used for random string generation
 	CharSet = "0123456789abcdefghijklmnopqrstuvwxyz"
 
@@ -53,15 +61,57 @@ const (
 // globalInstanceCounter is used to assign unique instance IDs to each generator
 var globalInstanceCounter uint64
 
-// Generator is the default global generator instance
-var Generator = NewGenerator()
+// Generator is the default global generator instance. It is created at
+// import time, but derives its machine and instance IDs on first use, so
+// importing the package does not touch the network or hostname.
+var Generator = newDefaultGenerator()
+
+// machineIDMu protects the machine ID cache below
+var machineIDMu sync.Mutex
+
+// machineIDCached records that machineIDCache holds a computed identifier
+var machineIDCached bool
+
+// machineIDCache is the unmasked machine identifier of this host
+var machineIDCache uint64
+
+// machineIDRandom records that machineIDCache is random because neither the
+// hostname nor the IP was available
+var machineIDRandom bool
+
+// DefaultGenerator returns the default global generator, Generator.
+//
+// Returns: The default generator
+func DefaultGenerator() *IDGenerator {
+	return Generator
+}
+
+// newDefaultGenerator creates the default generator, deferring the machine
+// ID derivation to its first use.
+//
+// Returns: The default generator
+func newDefaultGenerator() *IDGenerator {
+	g, _ := newGenerator(DefaultLayout, []Option{withDeferredIdentity()})
+	return g
+}
+
+// withDeferredIdentity makes the generator derive its machine and instance
+// IDs on first use instead of at construction.
+//
+// Returns: An Option that defers the identity
+func withDeferredIdentity() Option {
+	return func(g *IDGenerator) error {
+		g.identityLazy = true
+		return nil
+	}
+}
 
 // UniqID generates a unique string ID using the default generator.
 // The string ID consists of a hex-encoded uint64 ID plus a random suffix.
 //
 // Returns: A unique string identifier
 func UniqID() string {
-	return Generator.GenerateStringID()
+	return DefaultGenerator().GenerateStringID()
 }
 
 // UniqUID generates a unique uint64 ID using the default generator.
@@ -69,45 +119,455 @@ func UniqID() string {
 //
 // Returns: A unique uint64 identifier
 func UniqUID() uint64 {
-	return Generator.GenerateUint64ID()
+	return DefaultGenerator().GenerateUint64ID()
+}
+
+// UniqBase62 generates a unique base62 string ID using the default generator.
+// The string ID consists of a base62-encoded uint64 ID plus a random suffix.
+//
+// Returns: A unique base62 string identifier
+func UniqBase62() string {
+	return DefaultGenerator().GenerateBase62ID()
 }
 
 // IDGenerator is responsible for generating unique identifiers.
 // It maintains machine ID, instance ID and an atomic counter to ensure uniqueness.
 type IDGenerator struct {
-	machineID  uint64     // 4-bit machine identifier
+	layout     Layout     // bit layout of uint64 IDs; set by NewGeneratorWithLayout
+	machineID  uint64     // 4-bit machine identifier in the default layout; atomic after construction
 	instanceID uint64     // 4-bit instance identifier for distinguishing multiple generators
+	prefix     uint64     // machine and instance IDs shifted into place; atomic after construction
 	counter    uint64     // atomic counter for uniqueness within the same millisecond
+	watermark  uint64     // atomic sequence number of generated IDs, for layouts with a watermark
 	rng        *rand.Rand // local random number generator for better performance
 	mu         sync.Mutex // mutex to protect rng from concurrent access
+
+	singleThreaded bool                  // skip locking mu; set by WithUnsafeSingleThreaded
+	bootNonce      uint64                // folded into instanceID; set by WithBootNonce
+	pidNonce       bool                  // fold the process ID into instanceID; set by WithPIDInstanceID
+	machineIDSet   bool                  // machineID was provided by WithMachineID
+	machineIDFunc  func() (uint64, bool) // derives the machine ID; set by WithMACMachineID, WithHostnameMachineID and WithRandomMachineID
+	machineRandom  bool                  // machineID is random because no host information was available
+	machineHash    uint64                // machineID before masking to the layout, for XIDs; atomic after construction
+	machineSource  string                // how machineIDFunc derives the machine ID, for the creation log
+	instanceIDSet  bool                  // instanceID was provided by WithInstanceID
+	instanceUnheld bool                  // instanceID is not recorded as taken; set by SelfTest
+	identityLazy   bool                  // machine and instance IDs are derived on first use; set for Generator
+	identityOnce   sync.Once             // derives the machine and instance IDs once
+	instanceReused bool                  // every instance ID was taken, so instanceID is shared
+	instanceHeld   int32                 // atomic; 1 while the machine/instance pair is recorded as taken, until Close
+	slugGroupSize  int                   // characters per slug group; set by WithSlugGroupSize
+	suffixLength   int                   // random suffix length for string IDs; set by WithSuffixLength
+	charSet        string                // characters of random suffixes; set by WithCharSet
+	epoch          int64                 // custom epoch in Unix milliseconds; set by WithEpoch
+	resolution     time.Duration         // unit of the timestamp field; set by WithTimestampResolution
+	createdAt      time.Time             // construction time; read from clock with WithRelativeTimestamp
+
+	clock       func() time.Time // time source; set by WithClock
+	customClock bool             // clock was set by WithClock, so waits poll it instead of sleeping
+	logger      *slog.Logger     // destination for warnings; set by WithLogger
+
+	cachedClockInterval time.Duration    // refresh interval of the cached clock; set by WithCachedClock
+	clockSource         func() time.Time // clock read by the cached clock goroutine
+	cachedNow           int64            // cached Unix time in nanoseconds, 0 once closed; accessed atomically
+	clockStop           chan struct{}    // closed by Close to stop the cached clock goroutine
+	clockDone           chan struct{}    // closed when the cached clock goroutine has stopped
+	closeOnce           sync.Once        // makes Close idempotent
+
+	relativeTimestamp bool   // epoch is createdAt; set by WithRelativeTimestamp
+	overflowWarned    uint32 // relative timestamp overflow was logged, accessed atomically
+
+	monotonic     bool       // never emit a smaller ID; set by WithMonotonic
+	overflowWait  bool       // wait for the next millisecond on overflow; set by WithOverflowWait
+	seqMu         sync.Mutex // protects lastTimestamp and sequence
+	lastTimestamp uint64     // timestamp of the last ID in sequenced modes
+	sequence      uint64     // counter within lastTimestamp in sequenced modes
+
+	cryptoSuffix bool   // draw suffixes from crypto/rand; set by WithCryptoRandomSuffix
+	cryptoBuf    []byte // buffered crypto/rand bytes, protected by mu
+	cryptoPos    int    // next unread position in cryptoBuf
+
+	parity      bool        // reserve bit 0 as an even parity bit; set by WithParity
+	environment Environment // environment tag in bits 13-12; set by WithEnvironment
+
+	serviceCode    uint64 // service code in bits 11-8; set by WithServiceCode
+	serviceCodeSet bool   // whether WithServiceCode was used
+
+	checkedSuffix bool // end string IDs in a check character; set by WithCheckedSuffix
+
+	filenameSafe bool // string IDs must be valid filenames; set by WithFilenameSafe
+	interleave   bool // mix the bits of uint64 IDs; set by WithBitInterleaving
+	spreadBits   uint // top bits varied by insert bucket; set by WithInsertSpreading
+	snowflake    bool // emit IDs in Snowflake bit order; set by NewSnowflakeGenerator
+
+	sharedFormat *idFormat // decoding settings shared by TypedIDs from GenerateID
+
+	id128Once    sync.Once            // draws id128Node and id128Counter on first use
+	id128Node    [id128NodeBytes]byte // random node value of Generate128
+	id128Counter uint32               // last counter value of Generate128, accessed atomically
+
+	shards      []shardCounter // per-shard counters; set by WithShardedCounter
+	shardBits   uint           // high counter bits holding the shard index
+	shardTokens sync.Pool      // per-processor shardTokens naming the shard to draw from
+	shardNext   uint64         // next shard handed to a new token, accessed atomically
+
+	counterStart uint64 // initial value of counter; set by WithCounterStart
+
+	readMu  sync.Mutex         // protects the Read state below
+	readBuf [idByteLength]byte // the last ID encoded by Read
+	readPos int                // next unread position in readBuf
+
+	windowTimestamp uint64 // latest timestamp of the free-running counter, accessed atomically
+	windowBase      uint64 // counter value before windowTimestamp began, accessed atomically
+	batchFloor      uint64 // first timestamp after those claimed by spilling batches, accessed atomically
+
+	overflowTimestamp uint64 // last timestamp reported by observeWindow plus one, accessed atomically
+	windowTracking    int32  // nonzero once remainingCapacity was called, accessed atomically
+
+	rollbackError bool   // GenerateUint64IDChecked reports rollback; set by WithClockRollbackError
+	lastChecked   uint64 // last timestamp issued by GenerateUint64IDChecked, accessed atomically
+
+	observer Observer // receives generation events; set by WithObserver
+
+	adaptMu     sync.Mutex // protects the adaptive state below
+	adaptLast   uint64     // timestamp of the last non-burst adaptive ID
+	adaptSeq    uint64     // counter within adaptLast, or within adaptWindow in burst mode
+	adaptBurst  bool       // GenerateAdaptive is in burst mode
+	adaptWindow uint64     // burst window number (timestamp >> AdaptiveWindowBits)
 }
 
 // NewGenerator creates a new IDGenerator instance with initialized machine ID and unique instance ID.
 //
+// Options that fail validation are still applied on a best-effort basis
+// (for example, out-of-range IDs are masked). Use NewGeneratorWithError to
+// reject invalid options instead.
+//
+// Parameters:
+//   - opts: Optional settings applied to the generator
+//
 // Returns: A new IDGenerator instance
-func NewGenerator() *IDGenerator {
+func NewGenerator(opts ...Option) *IDGenerator {
+	g, _ := newGenerator(DefaultLayout, opts)
+	return g
+}
+
+// NewGeneratorWithError creates a new IDGenerator instance like NewGenerator,
+// but returns an error if any option fails validation.
+//
+// Parameters:
+//   - opts: Optional settings applied to the generator
+//
+// Returns:
+//   - *IDGenerator: A new IDGenerator instance, or nil on error
+//   - error: The first option validation error, if any
+func NewGeneratorWithError(opts ...Option) (*IDGenerator, error) {
+	g, err := newGenerator(DefaultLayout, opts)
+	if err != nil {
+		g.Close() // release the instance ID of the discarded generator
+		return nil, err
+	}
+	return g, nil
+}
+
+// ErrMachineIDUnavailable is returned by NewGeneratorE when neither the
+// hostname nor the local IP could be read, so the machine ID is random.
+var ErrMachineIDUnavailable = errors.New("hostname and IP unavailable; machine ID is random")
+
+// ErrInstanceIDExhausted is returned by NewGeneratorE when every instance ID
+// on the generator's machine ID was already taken, so the generator shares
+// its instance ID with another and their IDs may collide.
+var ErrInstanceIDExhausted = errors.New("instance IDs exhausted; instance ID is reused")
+
+// NewGeneratorE creates a new IDGenerator instance like NewGeneratorWithError,
+// but also reports when the machine ID could not be derived from the host.
+// NewGenerator silently falls back to a random machine ID in that case,
+// which hides environment problems and makes collisions between machines
+// more likely; strict callers can use this constructor to decide whether to
+// proceed. WithMachineID avoids the lookup altogether.
+//
+// It likewise reports when more than MaxInstanceID+1 generators have been
+// created on the machine ID, so the new one reuses an instance ID; WithLogger
+// logs a warning in that case for the other constructors. Use
+// AvailableInstanceIDs to check for free instance IDs beforehand, and Close
+// generators that are no longer needed to free theirs. When both
+// problems occur, the error wraps both sentinels.
+//
+// Parameters:
+//   - opts: Optional settings applied to the generator
+//
+// Returns:
+//   - *IDGenerator: A new IDGenerator instance, or nil if an option is invalid
+//   - error: The first option validation error, or ErrMachineIDUnavailable
+//     and ErrInstanceIDExhausted together with a usable generator
+func NewGeneratorE(opts ...Option) (*IDGenerator, error) {
+	g, err := NewGeneratorWithError(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	var errs []error
+	if g.machineRandom {
+		errs = append(errs, ErrMachineIDUnavailable)
+	}
+	if g.instanceReused {
+		errs = append(errs, ErrInstanceIDExhausted)
+	}
+	return g, errors.Join(errs...)
+}
+
+// NewGeneratorWithLayout creates a new IDGenerator instance whose uint64 IDs
+// use a custom bit layout instead of DefaultLayout. Like
+// NewGeneratorWithError, it returns an error if the layout or any option is
+// invalid.
+//
+// IDs from such a generator must be decoded with Layout.ParseUint64ID or the
+// generator's Decode method; package-level helpers such as ParseUint64ID
+// assume the default layout, and GenerateChild and GenerateAdaptive return
+// ErrChildUnsupported and ErrAdaptiveUnsupported.
+//
+// Parameters:
+//   - l: The bit layout of generated IDs
+//   - opts: Optional settings applied to the generator
+//
+// Returns:
+//   - *IDGenerator: A new IDGenerator instance, or nil on error
+//   - error: The layout or first option validation error, if any
+func NewGeneratorWithLayout(l Layout, opts ...Option) (*IDGenerator, error) {
+	if err := l.Validate(); err != nil {
+		return nil, err
+	}
+
+	g, err := newGenerator(l, opts)
+	if err != nil {
+		g.Close() // release the instance ID of the discarded generator
+		return nil, err
+	}
+	return g, nil
+}
+
+// newGenerator builds a generator and applies options, collecting the first
+// validation error while still applying every option.
+//
+// Parameters:
+//   - layout: The bit layout of generated IDs
+//   - opts: Optional settings applied to the generator
+//
+// Returns:
+//   - *IDGenerator: The configured generator
+//   - error: The first option validation error, if any
+func newGenerator(layout Layout, opts []Option) (*IDGenerator, error) {
 	// Initialize with current time as seed for better randomness
 	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
 
-	// Assign a unique instance ID to this generator
-	instanceID := atomic.AddUint64(&globalInstanceCounter, 1) & MaxInstanceID
+	g := &IDGenerator{
+		layout:        layout,
+		counter:       0,
+		rng:           rng,
+		slugGroupSize: DefaultSlugGroupSize,
+		suffixLength:  RandomSuffixLength,
+		charSet:       CharSet,
+		machineIDFunc: generateMachineID,
+		machineSource: "host",
+		clock:         time.Now,
+		resolution:    time.Millisecond,
+		readPos:       idByteLength,
+	}
+
+	var firstErr error
+	for _, opt := range opts {
+		if err := opt(g); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	// Check that the options are compatible with each other
+	if err := g.validateOptions(); err != nil && firstErr == nil {
+		firstErr = err
+	}
+
+	// The counter width is final once every option has been applied
+	g.counterStart &= g.maxCounter()
+	g.counter = g.counterStart
+
+	// Relative timestamps count from construction, once the clock is known.
+	// Other generators leave their clock untouched until the first ID.
+	g.createdAt = time.Now()
+	if g.relativeTimestamp {
+		g.createdAt = g.clock()
+		g.epoch = g.createdAt.UnixMilli()
+	}
+
+	// A check character is computed over CharSet and replaces the last suffix
+	// character, so drop it rather than emit an invalid last character when
+	// NewGenerator ignores the validation error above
+	if g.checkedSuffix && (g.suffixLength < 1 || g.charSet != CharSet) {
+		g.checkedSuffix = false
+		g.warn("checked suffix disabled; it needs a suffix from the default character set",
+			"suffix_length", g.suffixLength, "char_set", g.charSet)
+	}
+
+	// Options may be applied in any order, so only warn once the logger is known
+	if g.suffixLength > MaxUsefulSuffixLength {
+		g.warn("random suffix length exceeds useful entropy",
+			"length", g.suffixLength, "max_useful", MaxUsefulSuffixLength)
+	}
+
+	// The default generator leaves the host lookups until its first use
+	if !g.identityLazy {
+		g.identityOnce.Do(g.initIdentity)
+	}
+
+	// Share one copy of the decoding settings among all TypedIDs
+	format := g.format()
+	g.sharedFormat = &format
+
+	// NewGenerator returns the generator despite option errors, so start the
+	// goroutine regardless; the constructors that discard it call Close
+	if g.cachedClockInterval > 0 {
+		g.startCachedClock()
+	}
+
+	return g, firstErr
+}
+
+// initIdentity derives the machine ID, assigns the instance ID and logs
+// them. It runs once, from newGenerator or, for the default generator, from
+// ensureIdentity on first use.
+func (g *IDGenerator) initIdentity() {
+	// Derive the machine ID from the host unless one was provided
+	if !g.machineIDSet {
+		g.machineID, g.machineRandom = g.machineIDFunc()
+	}
+	g.machineHash = g.machineID
+	g.machineID &= g.layout.maxMachineID() // Ensure within the field
+	g.instanceID &= g.layout.maxInstanceID()
+
+	// Assign a unique instance ID to this generator, skipping IDs already
+	// taken on this machine, unless one was provided
+	if g.instanceIDSet {
+		if !g.instanceUnheld {
+			claimInstanceID(g.layout, g.machineID, g.instanceID)
+			atomic.StoreInt32(&g.instanceHeld, 1)
+		}
+	} else {
+		nonce := g.bootNonce
+		if g.pidNonce {
+			nonce ^= pidNonce(osGetpid())
+		}
+		g.instanceID, g.instanceReused = assignInstanceID(g.layout, g.machineID, nonce)
+		atomic.StoreInt32(&g.instanceHeld, 1)
+		if g.instanceReused {
+			g.warn("instance IDs exhausted; reusing instance ID",
+				"machine_id", g.machineID, "instance_id", g.instanceID, "max_instance_id", g.layout.maxInstanceID())
+		}
+	}
+
+	// The identity bits never change, so shift them into place once
+	g.prefix = g.layout.identityPrefix(g.machineID, g.instanceID)
+
+	g.logIdentity()
+}
 
-	return &IDGenerator{
-		machineID:  generateMachineID() & MaxMachineID, // Ensure within 6-bit range
-		instanceID: instanceID,                         // Ensure within 2-bit range
-		counter:    0,
-		rng:        rng,
+// ensureIdentity derives the machine and instance IDs of a generator that
+// deferred them, before anything reads them.
+func (g *IDGenerator) ensureIdentity() {
+	if g.identityLazy {
+		g.identityOnce.Do(g.initIdentity)
 	}
 }
 
+// MachineID returns the machine ID embedded in this generator's IDs, for
+// startup diagnostics and for detecting machine ID collisions across a fleet.
+//
+// Returns: The machine ID, 4 bits wide in the default layout
+func (g *IDGenerator) MachineID() uint64 {
+	g.ensureIdentity()
+	return atomic.LoadUint64(&g.machineID)
+}
+
+// InstanceID returns the instance ID embedded in this generator's IDs.
+//
+// Returns: The instance ID, 4 bits wide in the default layout
+func (g *IDGenerator) InstanceID() uint64 {
+	g.ensureIdentity()
+	return g.instanceID
+}
+
+// String describes the generator's identity and counter for diagnostics,
+// e.g. "IDGenerator{machine:0xA instance:0x3 counter:12345}". The counter is
+// read atomically, so String is safe to call during generation.
+//
+// Returns: A human-readable description of the generator
+func (g *IDGenerator) String() string {
+	return fmt.Sprintf("IDGenerator{machine:0x%X instance:0x%X counter:%d}",
+		g.MachineID(), g.InstanceID(), g.counterValue())
+}
+
+// Reset restores the counter to its starting value (zero unless
+// WithCounterStart was used) and reseeds the random number generator with a
+// fresh source, discarding any buffered crypto/rand bytes.
+//
+// Reset is NOT safe to call concurrently with generation. It is intended for
+// test setup, and for cleanup in a forked child process so that it does not
+// repeat the parent's random suffixes.
+func (g *IDGenerator) Reset() {
+	atomic.StoreUint64(&g.counter, g.counterStart)
+	for i := range g.shards {
+		atomic.StoreUint64(&g.shards[i].n, 0)
+	}
+
+	g.mu.Lock()
+	g.rng = rand.New(rand.NewSource(time.Now().UnixNano()))
+	g.cryptoPos = len(g.cryptoBuf)
+	g.mu.Unlock()
+}
+
+// Close releases the generator's background resources, such as the
+// goroutine started by WithCachedClock, and waits for them to stop. It also
+// releases the generator's machine/instance ID pair, which is held from
+// creation until Close, so later generators on the same machine ID can take
+// the instance ID again. It is safe to call more than once.
+//
+// Using a generator after Close is undefined. Create a new generator
+// instead.
+//
+// Returns: Always nil; the error satisfies io.Closer
+func (g *IDGenerator) Close() error {
+	g.stopCachedClock()
+	if atomic.CompareAndSwapInt32(&g.instanceHeld, 1, 0) {
+		releaseInstanceID(g.layout, atomic.LoadUint64(&g.machineID), g.instanceID)
+	}
+	return nil
+}
+
 // GenerateStringID creates a unique string identifier.
 // Format: hex(uint64_id) + random_suffix
 //
 // Returns: A unique string identifier
 func (g *IDGenerator) GenerateStringID() string {
-	id := g.GenerateUint64ID()
-	suffix := g.generateRandomSuffix(RandomSuffixLength)
-	return fmt.Sprintf("%s%s", strconv.FormatUint(id, 16), suffix)
+	var buf [HexIDLength + RandomSuffixLength]byte
+	return string(g.AppendStringID(buf[:0]))
+}
+
+// AppendStringID appends a unique string identifier in the format of
+// GenerateStringID to dst, for hot paths such as logging that build IDs into
+// a reusable buffer. It does not allocate when dst has enough spare capacity.
+//
+// Parameters:
+//   - dst: The buffer to append to
+//
+// Returns: The extended buffer
+func (g *IDGenerator) AppendStringID(dst []byte) []byte {
+	start := len(dst)
+	dst = strconv.AppendUint(dst, g.GenerateUint64ID(), 16)
+	dst = g.appendRandomSuffixFrom(dst, g.charSet, g.suffixLength)
+
+	if g.checkedSuffix && g.suffixLength > 0 {
+		if check, ok := checkChar(string(dst[start : len(dst)-1])); ok {
+			dst[len(dst)-1] = check
+		}
+	}
+	return dst
 }
 
 // GenerateUint64ID creates a unique uint64 identifier.
@@ -115,64 +575,392 @@ func (g *IDGenerator) GenerateStringID() string {
 // Bit layout (64 bits total):
 // - Bits 63-60 (4 bits): Machine ID
 // - Bits 59-56 (4 bits): Instance ID
-// - Bits 55-14 (42 bits): Timestamp (milliseconds since Unix epoch)
+// - Bits 55-14 (42 bits): Timestamp (milliseconds since the generator's epoch)
 // - Bits 13-0 (14 bits): Counter
 //
+// Generators created by NewGeneratorWithLayout use their own layout instead.
+//
 // Returns: A unique uint64 identifier
 func (g *IDGenerator) GenerateUint64ID() uint64 {
+	if g.monotonic || g.overflowWait {
+		timestamp, counter := g.nextSequence()
+		return g.compose(timestamp, counter)
+	}
+
 	counter := g.nextCounter()
-	timestamp := uint64(time.Now().UnixMilli())
+	timestamp := g.freeRunningTimestamp()
+	g.trackWindow(timestamp, 1)
+
+	return g.compose(timestamp, counter)
+}
+
+// compose combines the generator's machine and instance IDs with a timestamp
+// and counter into a uint64 identifier.
+//
+// Parameters:
+//   - timestamp: The timestamp in milliseconds since the generator's epoch
+//   - counter: The counter value
+//
+// Returns: The combined identifier
+func (g *IDGenerator) compose(timestamp, counter uint64) uint64 {
+	g.ensureIdentity()
+
+	// Free the highest counter bits for the environment tag and service code
+	counterMask := g.layout.maxCounter() >> g.tagBits()
+	if g.parity {
+		// Free the lowest bit for the parity bit
+		counter <<= ParityCounterShift
+	}
 
 	// Combine components with bit shifting
-	id := (g.machineID << MachineIDShift) |
-		(g.instanceID << InstanceIDShift) |
-		((timestamp & MaxTimestamp) << TimestampShift) |
-		(counter & MaxCounter)
+	id := atomic.LoadUint64(&g.prefix) |
+		((timestamp & g.layout.maxTimestamp()) << g.layout.timestampShift()) |
+		(uint64(g.environment) << (g.layout.timestampShift() - environmentBits)) |
+		(g.serviceCode << (g.layout.timestampShift() - environmentBits - serviceCodeBits)) |
+		((counter & counterMask) << g.layout.counterShift())
+
+	if g.layout.WatermarkBits > 0 {
+		watermark := atomic.AddUint64(&g.watermark, 1) - 1
+		id |= watermark & g.layout.maxWatermark()
+	}
+
+	if g.spreadBits > 0 {
+		id = g.format().spread(id)
+	}
+
+	if g.interleave {
+		id = InterleaveBits(id)
+	}
+
+	if g.parity {
+		id |= parityOf(id)
+	}
 
+	// Reordering the fields keeps the number of set bits, so parity holds
+	if g.snowflake {
+		id = toSnowflake(id)
+	}
+
+	g.observeGenerate()
 	return id
 }
 
+// maxCounter returns the largest counter value that compose can embed
+// without truncation, given the bits reserved by the environment tag,
+// service code and parity bit.
+//
+// Returns: The maximum counter value
+func (g *IDGenerator) maxCounter() uint64 {
+	maxCounter := g.layout.maxCounter() >> g.tagBits()
+	if g.parity {
+		maxCounter >>= ParityCounterShift
+	}
+	return maxCounter
+}
+
+// timestamp returns the current time in units of the generator's resolution
+// (milliseconds by default) since its epoch.
+//
+// Returns: The current timestamp, or 0 if the clock is before the epoch
+func (g *IDGenerator) timestamp() uint64 {
+	return g.timestampAt(g.clock())
+}
+
+// timestampAt converts a time to units of the generator's resolution since
+// its epoch.
+//
+// Parameters:
+//   - t: The time to convert
+//
+// Returns: The timestamp, or 0 if t is before the epoch
+func (g *IDGenerator) timestampAt(t time.Time) uint64 {
+	units := unitsSince(t, g.epoch, g.resolution)
+	if units < 0 {
+		return 0
+	}
+	if g.relativeTimestamp {
+		g.checkRelativeOverflow(uint64(units))
+	}
+	return uint64(units)
+}
+
+// unitsSince returns the number of whole units of resolution between an
+// epoch and t.
+//
+// Parameters:
+//   - t: The time to convert
+//   - epoch: The epoch in Unix milliseconds
+//   - resolution: time.Millisecond or time.Microsecond
+//
+// Returns: The units since the epoch, negative if t is before it
+func unitsSince(t time.Time, epoch int64, resolution time.Duration) int64 {
+	if resolution == time.Microsecond {
+		return t.UnixMicro() - epoch*1000
+	}
+	return t.UnixMilli() - epoch
+}
+
 // nextCounter atomically increments and returns the next counter value.
 //
 // Returns: The next counter value
 func (g *IDGenerator) nextCounter() uint64 {
+	if g.shards != nil {
+		last, base, capacity := g.reserveCounters(1)
+		return base | last%capacity
+	}
+
 	return atomic.AddUint64(&g.counter, 1)
 }
 
-// generateRandomSuffix creates a random string of specified length.
+// nextSequence returns the next timestamp and counter for the sequenced
+// modes enabled by WithMonotonic and WithOverflowWait. The counter restarts
+// at zero each millisecond, and when it is exhausted within a millisecond
+// this blocks until the clock passes that millisecond, so no counter value
+// is ever reused. In monotonic mode, if the clock moves backward the last
+// timestamp is reused so IDs keep increasing.
+//
+// Returns:
+//   - timestamp: The timestamp for the next ID
+//   - counter: The counter for the next ID
+func (g *IDGenerator) nextSequence() (timestamp, counter uint64) {
+	g.seqMu.Lock()
+	defer g.seqMu.Unlock()
+
+	return g.nextSequenceLocked()
+}
+
+// nextSequenceLocked is nextSequence for callers that already hold seqMu.
+//
+// Returns:
+//   - timestamp: The timestamp for the next ID
+//   - counter: The counter for the next ID
+func (g *IDGenerator) nextSequenceLocked() (timestamp, counter uint64) {
+	for {
+		if timestamp, counter, ok := g.trySequenceLocked(); ok {
+			return timestamp, counter
+		}
+		g.observeOverflow()
+		g.waitAfter(g.lastTimestamp)
+	}
+}
+
+// trySequenceLocked takes the next timestamp and counter without waiting.
+// The caller must hold seqMu.
+//
+// Returns:
+//   - timestamp: The timestamp for the next ID
+//   - counter: The counter for the next ID
+//   - ok: False if the counter is exhausted for the current millisecond
+func (g *IDGenerator) trySequenceLocked() (timestamp, counter uint64, ok bool) {
+	timestamp = g.timestamp()
+	if g.monotonic && timestamp < g.lastTimestamp {
+		// The clock moved backward; stay on the last timestamp
+		timestamp = g.lastTimestamp
+	}
+
+	if timestamp != g.lastTimestamp {
+		g.lastTimestamp = timestamp
+		g.sequence = 0
+		return timestamp, 0, true
+	}
+
+	if g.sequence < g.maxCounter() {
+		g.sequence++
+		return timestamp, g.sequence, true
+	}
+
+	return 0, 0, false
+}
+
+// waitAfter blocks until the generator's clock is past the given timestamp.
+// It sleeps while the clock is behind by whole timestamp units and yields
+// while waiting for the current unit to end.
+//
+// Parameters:
+//   - timestamp: The timestamp the clock must pass
+func (g *IDGenerator) waitAfter(timestamp uint64) {
+	for {
+		now := g.timestamp()
+		if now > timestamp {
+			return
+		}
+
+		if gap := timestamp - now; gap > 0 {
+			time.Sleep(time.Duration(gap) * g.resolution)
+		} else {
+			runtime.Gosched()
+		}
+	}
+}
+
+// generateRandomSuffix creates a random string of specified length from the
+// generator's character set (see WithCharSet).
 // Uses a more efficient approach than crypto/rand for non-cryptographic purposes.
-// This method is thread-safe.
+// This method is thread-safe unless the generator was created with WithUnsafeSingleThreaded.
 //
 // Parameters:
 //   - length: The desired length of the random string
 //
 // Returns: A random string of the specified length
 func (g *IDGenerator) generateRandomSuffix(length int) string {
+	return g.generateRandomSuffixFrom(g.charSet, length)
+}
+
+// generateRandomSuffixFrom creates a random string of specified length from
+// the characters of charset, like generateRandomSuffix.
+//
+// Parameters:
+//   - charset: The characters to draw from
+//   - length: The desired length of the random string
+//
+// Returns: A random string of the specified length
+func (g *IDGenerator) generateRandomSuffixFrom(charset string, length int) string {
 	if length <= 0 {
 		return ""
 	}
 
-	result := make([]byte, length)
-	charSetLen := len(CharSet)
+	return string(g.appendRandomSuffixFrom(make([]byte, 0, length), charset, length))
+}
+
+// appendRandomSuffixFrom appends a random string of specified length from the
+// characters of charset to dst, like generateRandomSuffixFrom.
+//
+// Parameters:
+//   - dst: The buffer to append to
+//   - charset: The characters to draw from
+//   - length: The desired length of the random string
+//
+// Returns: The extended buffer
+func (g *IDGenerator) appendRandomSuffixFrom(dst []byte, charset string, length int) []byte {
+	charSetLen := len(charset)
 
 	// Lock to ensure thread-safe access to the random number generator
-	g.mu.Lock()
+	if !g.singleThreaded {
+		g.mu.Lock()
+	}
 	for i := 0; i < length; i++ {
-		result[i] = CharSet[g.rng.Intn(charSetLen)]
+		dst = append(dst, charset[g.randIntn(charSetLen)])
+	}
+	if !g.singleThreaded {
+		g.mu.Unlock()
 	}
-	g.mu.Unlock()
 
-	return string(result)
+	return dst
+}
+
+// randIntn returns a random integer in [0, n) for suffix generation, drawn
+// from crypto/rand when enabled and from the local rng otherwise.
+// The caller must hold mu unless the generator is single-threaded.
+//
+// Parameters:
+//   - n: The exclusive upper bound
+//
+// Returns: A random integer in [0, n)
+func (g *IDGenerator) randIntn(n int) int {
+	if g.cryptoSuffix {
+		if v, ok := g.cryptoIntn(n); ok {
+			return v
+		}
+	}
+	return g.rng.Intn(n)
+}
+
+// warn logs a warning through the logger set by WithLogger, if any.
+//
+// Parameters:
+//   - msg: The warning message
+//   - args: Alternating attribute keys and values, as for slog.Logger.Warn
+func (g *IDGenerator) warn(msg string, args ...any) {
+	if g.logger != nil {
+		g.logger.Warn(msg, args...)
+	}
+}
+
+// logIdentity logs the generator's machine and instance IDs, and the host
+// information they were derived from, through the logger set by WithLogger,
+// so fleet operators can audit which hosts map to which machine IDs. The
+// hostname and IP are not read for machine IDs that do not depend on them.
+func (g *IDGenerator) logIdentity() {
+	if g.logger == nil {
+		return
+	}
+
+	source := g.machineSource
+	if g.machineIDSet {
+		source = "explicit"
+	}
+
+	args := []any{"machine_id", g.machineID, "instance_id", g.instanceID, "machine_id_source", source}
+	if source == "host" || source == "mac" || source == "hostname" {
+		hostname, _ := osHostname()
+		var ip string
+		if localIP, err := getLocalIP(); err == nil {
+			ip = localIP.String()
+		}
+		args = append(args, "hostname", hostname, "ip", ip)
+	}
+	if g.machineRandom {
+		args = append(args, "random_fallback", true)
+	}
+
+	g.logger.Info("generator created", args...)
+}
+
+// generateMachineID returns the machine identifier derived from the hostname
+// and local IP by computeMachineID. It is computed once per process and
+// cached, so creating many generators does not repeat the system calls.
+//
+// Returns:
+//   - uint64: A machine-specific identifier
+//   - bool: True if neither the hostname nor the IP was available
+func generateMachineID() (uint64, bool) {
+	machineIDMu.Lock()
+	defer machineIDMu.Unlock()
+
+	if !machineIDCached {
+		machineIDCache, machineIDRandom = computeMachineID()
+		machineIDCached = true
+	}
+	return machineIDCache, machineIDRandom
 }
 
-// generateMachineID creates a unique machine identifier based on hostname and local IP.
+// refreshMachineID recomputes the machine identifier from the current
+// hostname and local IP, and caches it for generators created afterwards
+// unless it is random.
+//
+// Returns:
+//   - uint64: A machine-specific identifier
+//   - bool: True if neither the hostname nor the IP was available
+func refreshMachineID() (uint64, bool) {
+	machineIDMu.Lock()
+	defer machineIDMu.Unlock()
+
+	machineID, random := computeMachineID()
+	if !random {
+		machineIDCache, machineIDRandom, machineIDCached = machineID, false, true
+	}
+	return machineID, random
+}
+
+// resetMachineIDCache makes the next generateMachineID call recompute the
+// machine identifier.
+func resetMachineIDCache() {
+	machineIDMu.Lock()
+	machineIDCached = false
+	machineIDMu.Unlock()
+}
+
+// computeMachineID creates a unique machine identifier based on hostname and local IP.
 // If hostname or IP cannot be obtained, it falls back to random generation.
 //
-// Returns: A machine-specific identifier
-func generateMachineID() uint64 {
+// Returns:
+//   - uint64: A machine-specific identifier
+//   - bool: True if neither the hostname nor the IP was available
+func computeMachineID() (uint64, bool) {
 	// Get hostname
-	hostname, err := os.Hostname()
-	if err != nil || hostname == "" {
+	hostname, err := osHostname()
+	noHostname := err != nil || hostname == ""
+	if noHostname {
 		hostname = generateFallbackString(10)
 	}
 
@@ -186,7 +974,56 @@ func generateMachineID() uint64 {
 	}
 
 	// Create machine ID from hostname and IP
-	return hashToUint64(hostname + ipStr)
+	return hashToUint64(hostname + ipStr), noHostname && err != nil
+}
+
+// generateMACMachineID creates a machine identifier from the first
+// non-loopback hardware address, which is more stable across reboots and
+// DHCP changes than the hostname and IP. If no hardware address is available,
+// it falls back to generateMachineID.
+//
+// Returns:
+//   - uint64: A machine-specific identifier
+//   - bool: True if no host information was available
+func generateMACMachineID() (uint64, bool) {
+	mac, err := getMACAddress()
+	if err != nil {
+		return generateMachineID()
+	}
+
+	return hashToUint64(mac.String()), false
+}
+
+// generateHostnameMachineID creates a machine identifier from the hostname
+// alone, which stays stable on platforms that keep the hostname but change
+// the IP on every restart. If the hostname is unavailable, it falls back to
+// a random identifier.
+//
+// Returns:
+//   - uint64: A machine-specific identifier
+//   - bool: True if the hostname was unavailable
+func generateHostnameMachineID() (uint64, bool) {
+	hostname, err := osHostname()
+	if err != nil || hostname == "" {
+		return hashToUint64(generateFallbackString(10)), true
+	}
+
+	return hashToUint64(hostname), false
+}
+
+// generateRandomMachineID draws a machine identifier from crypto/rand without
+// consulting the hostname or network interfaces. If the system entropy
+// source fails, it falls back to a time-seeded math/rand source.
+//
+// Returns:
+//   - uint64: A random identifier
+//   - bool: Always false, since the randomness was requested
+func generateRandomMachineID() (uint64, bool) {
+	var buf [8]byte
+	if _, err := cryptoRead(buf[:]); err != nil {
+		return rand.New(rand.NewSource(time.Now().UnixNano())).Uint64(), false
+	}
+	return binary.BigEndian.Uint64(buf[:]), false
 }
 
 // hashToUint64 converts a string to uint64 using SHA1 hash.
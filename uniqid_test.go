@@ -1,8 +1,12 @@
 package tsuniqid
 
 import (
+	"fmt"
+	"runtime"
 	"strconv"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -209,6 +213,138 @@ func TestIDGenerator_MultipleInstances(t *testing.T) {
 	}
 }
 
+// TestIDGenerator_MachineIDAndInstanceID tests that the accessors report the
+// identity embedded in generated IDs.
+func TestIDGenerator_MachineIDAndInstanceID(t *testing.T) {
+	for _, gen := range []*IDGenerator{NewGenerator(), NewGenerator(WithMachineID(3), WithInstanceID(7))} {
+		machineID, instanceID, _, _ := ParseUint64ID(gen.GenerateUint64ID())
+
+		if gen.MachineID() != machineID {
+			t.Errorf("MachineID mismatch: got %d, expected %d", gen.MachineID(), machineID)
+		}
+		if gen.InstanceID() != instanceID {
+			t.Errorf("InstanceID mismatch: got %d, expected %d", gen.InstanceID(), instanceID)
+		}
+	}
+}
+
+// composeUnprefixed is the reference implementation of compose that shifts
+// the machine and instance IDs on every call.
+func composeUnprefixed(g *IDGenerator, timestamp, counter uint64) uint64 {
+	counterMask := uint64(MaxCounter)
+	if g.environment != 0 {
+		counterMask = MaxEnvironmentCounter
+	}
+	if g.parity {
+		counter <<= ParityCounterShift
+	}
+
+	id := (g.machineID << MachineIDShift) |
+		(g.instanceID << InstanceIDShift) |
+		((timestamp & MaxTimestamp) << TimestampShift) |
+		(uint64(g.environment) << EnvironmentShift) |
+		(counter & counterMask)
+
+	if g.parity {
+		id |= parityOf(id)
+	}
+
+	return id
+}
+
+// TestIDGenerator_ComposePrefix tests that composing with the precomputed
+// prefix matches shifting the machine and instance IDs on every call.
+func TestIDGenerator_ComposePrefix(t *testing.T) {
+	for machineID := uint64(0); machineID <= MaxMachineID; machineID++ {
+		for _, extra := range []Option{WithBootNonce(0), WithParity(), WithEnvironment(EnvironmentDevelopment)} {
+			gen := NewGenerator(WithMachineID(machineID), WithInstanceID(MaxInstanceID-machineID), extra)
+
+			for _, counter := range []uint64{0, 1, MaxCounter, MaxCounter + 1} {
+				timestamp := uint64(time.Now().UnixMilli())
+				if got, want := gen.compose(timestamp, counter), composeUnprefixed(gen, timestamp, counter); got != want {
+					t.Errorf("Compose mismatch: got %x, expected %x", got, want)
+				}
+			}
+		}
+	}
+}
+
+// TestIDGenerator_String tests the diagnostic description of a generator.
+func TestIDGenerator_String(t *testing.T) {
+	gen := NewGenerator(WithMachineID(0xa), WithInstanceID(0x3))
+	for i := 0; i < 5; i++ {
+		gen.GenerateUint64ID()
+	}
+
+	if got, want := gen.String(), "IDGenerator{machine:0xA instance:0x3 counter:5}"; got != want {
+		t.Errorf("String mismatch: got %s, expected %s", got, want)
+	}
+	if got := fmt.Sprint(gen); got != gen.String() {
+		t.Errorf("Generator does not implement fmt.Stringer: %s", got)
+	}
+}
+
+// TestIDGenerator_Reset tests that Reset zeroes the counter and reseeds the
+// random suffix source.
+func TestIDGenerator_Reset(t *testing.T) {
+	gen := NewGenerator(WithCryptoRandomSuffix())
+	for i := 0; i < 100; i++ {
+		gen.GenerateStringID()
+	}
+
+	oldRNG := gen.rng
+	gen.Reset()
+
+	if gen.counter != 0 {
+		t.Errorf("Counter not reset: %d", gen.counter)
+	}
+	if gen.rng == oldRNG {
+		t.Errorf("RNG not reseeded")
+	}
+	if gen.cryptoPos != len(gen.cryptoBuf) {
+		t.Errorf("Crypto buffer not discarded: position %d", gen.cryptoPos)
+	}
+
+	if _, _, _, counter := ParseUint64ID(gen.GenerateUint64ID()); counter != 1 {
+		t.Errorf("Expected counter 1 after reset, got %d", counter)
+	}
+}
+
+// TestIDGenerator_Close tests that Close stops the cached clock goroutines
+// without leaking them and is safe to repeat.
+func TestIDGenerator_Close(t *testing.T) {
+	before := runtime.NumGoroutine()
+
+	gens := make([]*IDGenerator, 10)
+	for i := range gens {
+		gens[i] = NewGenerator(WithCachedClock(time.Millisecond))
+	}
+	if running := runtime.NumGoroutine(); running < before+len(gens) {
+		t.Fatalf("Expected at least %d goroutines with cached clocks, got %d", before+len(gens), running)
+	}
+
+	for _, gen := range gens {
+		for i := 0; i < 2; i++ {
+			if err := gen.Close(); err != nil {
+				t.Errorf("Unexpected error from Close: %v", err)
+			}
+		}
+	}
+
+	// Exited goroutines may take a moment to leave the count
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() > before {
+		if time.Now().After(deadline) {
+			t.Fatalf("Goroutines leaked: %d before, %d after Close", before, runtime.NumGoroutine())
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	if err := NewGenerator().Close(); err != nil {
+		t.Errorf("Unexpected error closing a generator without background resources: %v", err)
+	}
+}
+
 // TestIDGenerator_CounterIncrement tests that the counter increments properly
 // and handles overflow correctly.
 func TestIDGenerator_CounterIncrement(t *testing.T) {
@@ -258,6 +394,62 @@ func TestIDGenerator_RandomSuffixVariety(t *testing.T) {
 	t.Logf("Generated %d unique suffixes out of 10000 IDs", uniqueSuffixes)
 }
 
+// TestIDGenerator_Monotonic tests that monotonic generators emit strictly
+// increasing IDs across counter exhaustion and clock rollback.
+func TestIDGenerator_Monotonic(t *testing.T) {
+	start := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	// 50ns per call exhausts the counter before each millisecond ends
+	clock := newFakeClock(start, 50*time.Nanosecond)
+	gen := NewGenerator(WithClock(clock.Now), WithMonotonic())
+
+	var last uint64
+	check := func(phase string) {
+		for i := 0; i < 3*(MaxCounter+1); i++ {
+			id := gen.GenerateUint64ID()
+			if id <= last {
+				t.Fatalf("%s: ID %d not greater than previous %d", phase, id, last)
+			}
+			last = id
+		}
+	}
+
+	check("Counter exhaustion")
+
+	// Roll the clock back and keep generating while it catches up
+	clock.Set(clock.Now().Add(-5*time.Millisecond), time.Millisecond)
+	check("Clock rollback")
+}
+
+// TestIDGenerator_OverflowWait tests that exhausting the counter within a
+// millisecond waits for the next millisecond instead of wrapping.
+func TestIDGenerator_OverflowWait(t *testing.T) {
+	start := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	// 50ns per call exhausts the counter before each millisecond ends
+	clock := newFakeClock(start, 50*time.Nanosecond)
+	gen := NewGenerator(WithClock(clock.Now), WithOverflowWait())
+
+	seen := make(map[uint64]bool)
+	perMillisecond := make(map[uint64]int)
+	for i := 0; i < 3*(MaxCounter+1); i++ {
+		id := gen.GenerateUint64ID()
+
+		if seen[id] {
+			t.Fatalf("Duplicate ID after %d IDs: %d", i, id)
+		}
+		seen[id] = true
+
+		perMillisecond[DecodeUint64(id).Timestamp]++
+	}
+
+	for timestamp, count := range perMillisecond {
+		if count > MaxCounter+1 {
+			t.Errorf("Millisecond %d issued %d IDs, exceeding capacity %d", timestamp, count, MaxCounter+1)
+		}
+	}
+}
+
 // BenchmarkUniqID benchmarks the performance of string ID generation.
 func BenchmarkUniqID(b *testing.B) {
 	b.ResetTimer()
@@ -282,6 +474,7 @@ func BenchmarkUniqUID(b *testing.B) {
 // string ID generation using a specific generator instance.
 func BenchmarkIDGenerator_GenerateStringID(b *testing.B) {
 	gen := NewGenerator()
+	b.ReportAllocs()
 	b.ResetTimer()
 	b.RunParallel(func(pb *testing.PB) {
 		for pb.Next() {
@@ -301,3 +494,130 @@ func BenchmarkIDGenerator_GenerateUint64ID(b *testing.B) {
 		}
 	})
 }
+
+// BenchmarkIDGenerator_Compose benchmarks composing IDs with the precomputed
+// identity prefix.
+func BenchmarkIDGenerator_Compose(b *testing.B) {
+	gen := NewGenerator()
+	var sink uint64
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		sink += gen.compose(uint64(i), uint64(i))
+	}
+	_ = sink
+}
+
+// BenchmarkIDGenerator_ComposeUnprefixed benchmarks composing IDs by shifting
+// the machine and instance IDs on every call.
+func BenchmarkIDGenerator_ComposeUnprefixed(b *testing.B) {
+	gen := NewGenerator()
+	var sink uint64
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		sink += composeUnprefixed(gen, uint64(i), uint64(i))
+	}
+	_ = sink
+}
+
+// TestDefaultGenerator tests that the default generator is created once and
+// shared by the package functions.
+func TestDefaultGenerator(t *testing.T) {
+	gen := DefaultGenerator()
+	if gen == nil {
+		t.Fatalf("DefaultGenerator returned nil")
+	}
+	if DefaultGenerator() != gen || Generator != gen {
+		t.Errorf("DefaultGenerator did not return the shared generator")
+	}
+
+	before := atomic.LoadUint64(&gen.counter)
+	UniqUID()
+	if atomic.LoadUint64(&gen.counter) == before {
+		t.Errorf("UniqUID did not use the default generator")
+	}
+}
+
+// TestDefaultGenerator_DeferredIdentity tests that the default generator is
+// usable as a variable but only derives its machine ID on first use.
+func TestDefaultGenerator_DeferredIdentity(t *testing.T) {
+	if Generator == nil || Generator.GenerateUint64ID() == 0 {
+		t.Fatalf("Generator is not usable directly")
+	}
+
+	gen, _ := newGenerator(DefaultLayout, []Option{withDeferredIdentity()})
+	calls := 0
+	gen.machineIDFunc = func() (uint64, bool) {
+		calls++
+		return 0x7, false
+	}
+	if calls != 0 {
+		t.Fatalf("Machine ID derived at construction")
+	}
+
+	id := gen.GenerateUint64ID()
+	gen.GenerateUint64ID()
+	if calls != 1 {
+		t.Errorf("Expected one machine ID derivation, got %d", calls)
+	}
+	if machineID, _, _, _ := ParseUint64ID(id); machineID != 0x7 || gen.MachineID() != 0x7 {
+		t.Errorf("Machine ID mismatch: ID has %d, generator reports %d", machineID, gen.MachineID())
+	}
+}
+
+// TestIDGenerator_AppendStringID tests that appended IDs match the
+// GenerateStringID format and that a buffer with spare capacity is reused
+// without allocating.
+func TestIDGenerator_AppendStringID(t *testing.T) {
+	gen := NewGenerator()
+
+	buf := []byte("id=")
+	buf = gen.AppendStringID(buf)
+	if !strings.HasPrefix(string(buf), "id=") || !ValidStringID(string(buf[3:])) {
+		t.Errorf("Appended ID is malformed: %s", buf)
+	}
+
+	checked := NewGenerator(WithCheckedSuffix())
+	if id := checked.AppendStringID([]byte("prefix")); !VerifyCheckedSuffix(string(id[len("prefix"):])) {
+		t.Errorf("Appended ID has an invalid check character: %s", id)
+	}
+
+	buf = make([]byte, 0, 64)
+	allocs := testing.AllocsPerRun(100, func() {
+		buf = gen.AppendStringID(buf[:0])
+	})
+	if allocs != 0 {
+		t.Errorf("Expected no allocations with spare capacity, got %.1f", allocs)
+	}
+}
+
+// BenchmarkIDGenerator_AppendStringID benchmarks appending string IDs into a
+// reused buffer, which avoids the allocations of GenerateStringID.
+func BenchmarkIDGenerator_AppendStringID(b *testing.B) {
+	gen := NewGenerator()
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		buf := make([]byte, 0, 64)
+		for pb.Next() {
+			buf = gen.AppendStringID(buf[:0])
+		}
+	})
+}
+
+// BenchmarkNewGenerator benchmarks generator creation with the cached
+// machine ID against recomputing it from the hostname and IP every time.
+func BenchmarkNewGenerator(b *testing.B) {
+	b.Run("cached", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			NewGenerator()
+		}
+	})
+
+	b.Run("uncached", func(b *testing.B) {
+		defer resetMachineIDCache()
+		for i := 0; i < b.N; i++ {
+			resetMachineIDCache()
+			NewGenerator()
+		}
+	})
+}
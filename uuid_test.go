@@ -0,0 +1,76 @@
+package tsuniqid
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestIDGenerator_GenerateUUIDv7 tests the version, variant, timestamp and
+// canonical form of UUIDv7s.
+func TestIDGenerator_GenerateUUIDv7(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	gen := NewGenerator(WithClock(func() time.Time { return now }))
+
+	uuid := gen.GenerateUUIDv7Bytes()
+	if version := uuid[6] >> 4; version != 7 {
+		t.Errorf("Expected version 7, got %d", version)
+	}
+	if variant := uuid[8] >> 6; variant != 0b10 {
+		t.Errorf("Expected variant 0b10, got %b", variant)
+	}
+
+	var timestamp uint64
+	for _, b := range uuid[:6] {
+		timestamp = timestamp<<8 | uint64(b)
+	}
+	if timestamp != uint64(now.UnixMilli()) {
+		t.Errorf("Expected timestamp %d, got %d", now.UnixMilli(), timestamp)
+	}
+
+	s := gen.GenerateUUIDv7()
+	if len(s) != UUIDLength || s[14] != '7' || strings.Count(s, "-") != 4 {
+		t.Fatalf("Malformed UUID %s", s)
+	}
+	for i, group := range strings.Split(s, "-") {
+		if want := []int{8, 4, 4, 4, 12}[i]; len(group) != want {
+			t.Errorf("Group %d of %s has length %d, expected %d", i, s, len(group), want)
+		}
+		if _, err := hex.DecodeString(group); err != nil || strings.ToLower(group) != group {
+			t.Errorf("Group %d of %s is not lowercase hex", i, s)
+		}
+	}
+	if !strings.HasPrefix(s, formatUUID(uuid)[:13]) {
+		t.Errorf("UUIDs in the same millisecond have different timestamps: %s, %s", s, formatUUID(uuid))
+	}
+
+	// UUIDs from later milliseconds sort after earlier ones
+	now = now.Add(time.Millisecond)
+	if later := gen.GenerateUUIDv7(); later <= s {
+		t.Errorf("Later UUID %s does not sort after %s", later, s)
+	}
+}
+
+// TestIDGenerator_GenerateUUIDv7Unique tests that the random tail separates
+// UUIDs generated in the same millisecond.
+func TestIDGenerator_GenerateUUIDv7Unique(t *testing.T) {
+	now := time.Now()
+	gen := NewGenerator(WithClock(func() time.Time { return now }))
+
+	seen := make(map[string]struct{}, 10000)
+	for i := 0; i < 10000; i++ {
+		uuid := gen.GenerateUUIDv7()
+		if _, ok := seen[uuid]; ok {
+			t.Fatalf("Duplicate UUID %s", uuid)
+		}
+		seen[uuid] = struct{}{}
+	}
+
+	// Seeded generators produce the same random tail
+	a, b := NewGenerator(WithSeed(7), WithClock(func() time.Time { return now })),
+		NewGenerator(WithSeed(7), WithClock(func() time.Time { return now }))
+	if ua, ub := a.GenerateUUIDv7(), b.GenerateUUIDv7(); ua != ub {
+		t.Errorf("Seeded generators produced %s and %s", ua, ub)
+	}
+}
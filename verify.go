@@ -0,0 +1,193 @@
+// Package tsuniqid - Streaming uniqueness checks for soak tests
+package tsuniqid
+
+import (
+	"math"
+	"math/bits"
+)
+
+// verifyExactLimit is the number of distinct IDs VerifyUniqueness tracks
+// exactly in a map before switching to bloom filters; a variable so tests can
+// exercise the filters without generating millions of IDs
+var verifyExactLimit = 1 << 20
+
+// verifyFalsePositiveRate bounds the chance that an ID seen for the first time
+// after the switch is counted as a duplicate
+const verifyFalsePositiveRate = 1e-6
+
+// verifyHashOffset decorrelates the second bloom filter hash from the first
+const verifyHashOffset = 0x9e3779b97f4a7c15
+
+// VerifyUniqueness counts the IDs read from ids that repeat an earlier ID,
+// until ids is closed. It is meant for soak tests that check far more IDs
+// than fit in a map, whether from this package's generators or the caller's.
+//
+// The first 2^20 distinct IDs are tracked exactly in a map, so shorter
+// streams get an exact count. Beyond that, IDs are added to a chain of bloom
+// filters, each twice the size of the last, which take about 4 bytes per ID.
+// An ID the filters report as seen is a candidate: it is counted as a
+// duplicate and kept exactly, so every later repeat of a candidate is
+// rechecked against the exact set rather than the filters. The count never
+// misses a duplicate, but may include an ID seen for the first time with a
+// probability below one in a million.
+//
+// Parameters:
+//   - ids: The IDs to check; VerifyUniqueness returns once it is closed
+//
+// Returns: The number of IDs that repeated an earlier ID
+func VerifyUniqueness(ids <-chan uint64) (duplicates int) {
+	exact := make(map[uint64]struct{})
+	for id := range ids {
+		if _, ok := exact[id]; ok {
+			duplicates++
+			continue
+		}
+		exact[id] = struct{}{}
+
+		if len(exact) >= verifyExactLimit {
+			break
+		}
+	}
+	if len(exact) < verifyExactLimit {
+		return duplicates
+	}
+
+	// Move the exact IDs into the filters; from here on only candidates
+	// are kept exactly
+	filters := &bloomChain{}
+	for id := range exact {
+		filters.add(id)
+	}
+	candidates := make(map[uint64]struct{})
+
+	for id := range ids {
+		if _, ok := candidates[id]; ok {
+			duplicates++
+			continue
+		}
+		if filters.contains(id) {
+			candidates[id] = struct{}{}
+			duplicates++
+			continue
+		}
+		filters.add(id)
+	}
+	return duplicates
+}
+
+// bloomChain is a scalable bloom filter: a chain of filters, each with twice
+// the capacity and half the false-positive rate of the one before, so the
+// combined rate stays below verifyFalsePositiveRate however many IDs are
+// added.
+type bloomChain struct {
+	filters []*bloomFilter
+}
+
+// add records an ID in the newest filter, starting a new one when it is full.
+//
+// Parameters:
+//   - id: The ID to record
+func (c *bloomChain) add(id uint64) {
+	n := len(c.filters)
+	if n == 0 || c.filters[n-1].full() {
+		capacity := uint64(verifyExactLimit) << n
+		rate := verifyFalsePositiveRate / float64(uint64(2)<<n)
+		c.filters = append(c.filters, newBloomFilter(capacity, rate))
+	}
+	c.filters[len(c.filters)-1].add(id)
+}
+
+// contains reports whether any filter may have recorded an ID.
+//
+// Parameters:
+//   - id: The ID to look up
+//
+// Returns: False if the ID was certainly never added
+func (c *bloomChain) contains(id uint64) bool {
+	for _, f := range c.filters {
+		if f.contains(id) {
+			return true
+		}
+	}
+	return false
+}
+
+// bloomFilter is a fixed-size bloom filter over uint64 IDs, indexed by double
+// hashing.
+type bloomFilter struct {
+	bits     []uint64
+	size     uint64 // number of bits
+	hashes   int    // number of bit positions per ID
+	count    uint64 // number of IDs added
+	capacity uint64 // number of IDs the filter is sized for
+}
+
+// newBloomFilter sizes a filter for a capacity and false-positive rate.
+//
+// Parameters:
+//   - capacity: The number of IDs the filter holds at that rate
+//   - rate: The false-positive rate once the filter is full
+//
+// Returns: The empty filter
+func newBloomFilter(capacity uint64, rate float64) *bloomFilter {
+	bitsPerID := -math.Log(rate) / (math.Ln2 * math.Ln2)
+	size := uint64(math.Ceil(bitsPerID * float64(capacity)))
+	size = (size + 63) &^ 63
+
+	return &bloomFilter{
+		bits:     make([]uint64, size/64),
+		size:     size,
+		hashes:   int(math.Ceil(bitsPerID * math.Ln2)),
+		capacity: capacity,
+	}
+}
+
+// full reports whether the filter holds its capacity.
+//
+// Returns: True once capacity IDs were added
+func (f *bloomFilter) full() bool {
+	return f.count >= f.capacity
+}
+
+// add sets the bit positions of an ID.
+//
+// Parameters:
+//   - id: The ID to add
+func (f *bloomFilter) add(id uint64) {
+	h1, h2 := bloomHashes(id)
+	for i := 0; i < f.hashes; i++ {
+		pos := (h1 + uint64(i)*h2) % f.size
+		f.bits[pos/64] |= 1 << (pos % 64)
+	}
+	f.count++
+}
+
+// contains reports whether every bit position of an ID is set.
+//
+// Parameters:
+//   - id: The ID to look up
+//
+// Returns: False if the ID was certainly never added
+func (f *bloomFilter) contains(id uint64) bool {
+	h1, h2 := bloomHashes(id)
+	for i := 0; i < f.hashes; i++ {
+		pos := (h1 + uint64(i)*h2) % f.size
+		if f.bits[pos/64]&(1<<(pos%64)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// bloomHashes derives the two hashes of double hashing from an ID with the
+// mixer of InterleaveBits, which spreads sequential IDs over every bit.
+//
+// Parameters:
+//   - id: The ID to hash
+//
+// Returns: The base hash and the odd step between bit positions
+func bloomHashes(id uint64) (h1, h2 uint64) {
+	h1 = InterleaveBits(id)
+	h2 = bits.RotateLeft64(InterleaveBits(id^verifyHashOffset), 17) | 1
+	return h1, h2
+}
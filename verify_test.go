@@ -0,0 +1,90 @@
+package tsuniqid
+
+import (
+	"math"
+	"testing"
+)
+
+// TestVerifyUniqueness tests duplicate counting both in the exact map and in
+// the bloom filters beyond it.
+func TestVerifyUniqueness(t *testing.T) {
+	defer func(limit int) { verifyExactLimit = limit }(verifyExactLimit)
+	verifyExactLimit = 1000
+
+	testCases := []struct {
+		name   string
+		count  int
+		repeat []int
+		want   int
+	}{
+		{name: "Empty", count: 0, want: 0},
+		{name: "Exact unique", count: 500, want: 0},
+		{name: "Exact duplicates", count: 500, repeat: []int{3, 3, 499}, want: 3},
+		{name: "Exact limit", count: 1000, repeat: []int{0}, want: 1},
+		{name: "Filtered unique", count: 20000, want: 0},
+		{name: "Filtered duplicates", count: 20000, repeat: []int{5, 1500, 19999, 19999}, want: 4},
+	}
+
+	// IDs laid out like a generator's, but fixed so the filters'
+	// false positives are reproducible
+	base := DefaultLayout.identityPrefix(1, 1) | 1_700_000_000_000<<TimestampShift
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ids := make([]uint64, tc.count)
+			for i := range ids {
+				ids[i] = base + uint64(i)*37
+			}
+			if got := VerifyUniqueness(stream(ids, tc.repeat)); got != tc.want {
+				t.Errorf("Got %d duplicates, expected %d", got, tc.want)
+			}
+		})
+	}
+}
+
+// TestVerifyUniqueness_Large tests a stream spanning many bloom filters, with
+// duplicates of early and late IDs and repeated candidates, and checks that
+// false positives stay within the documented rate.
+func TestVerifyUniqueness_Large(t *testing.T) {
+	defer func(limit int) { verifyExactLimit = limit }(verifyExactLimit)
+	verifyExactLimit = 1 << 12
+
+	// Every multiple of 1000 is sent three times, far apart, and the largest
+	// values straddle the full range of uint64
+	const count = 1 << 20
+	ch := make(chan uint64, 1024)
+	go func() {
+		defer close(ch)
+		for i := uint64(0); i < count; i++ {
+			ch <- i
+			ch <- ^i
+		}
+		for repeat := 0; repeat < 2; repeat++ {
+			for i := uint64(0); i < count; i += 1000 {
+				ch <- i
+			}
+		}
+	}()
+
+	// No duplicate may be missed, and false positives must stay near the
+	// documented rate over the 2*count distinct IDs
+	want := 2 * (count + 999) / 1000
+	tolerance := int(math.Ceil(4 * 2 * count * verifyFalsePositiveRate))
+	if got := VerifyUniqueness(ch); got < want || got > want+tolerance {
+		t.Errorf("Got %d duplicates, expected %d plus at most %d false positives", got, want, tolerance)
+	}
+}
+
+// stream sends ids and then the IDs at the repeat indexes on a channel.
+func stream(ids []uint64, repeat []int) <-chan uint64 {
+	ch := make(chan uint64, 64)
+	go func() {
+		defer close(ch)
+		for _, id := range ids {
+			ch <- id
+		}
+		for _, i := range repeat {
+			ch <- ids[i]
+		}
+	}()
+	return ch
+}
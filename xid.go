@@ -0,0 +1,113 @@
+// Package tsuniqid - XID-compatible identifiers
+package tsuniqid
+
+import (
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// XID format constants.
+//
+// An XID is 12 bytes: a 4-byte big-endian Unix timestamp in seconds, a
+// 3-byte machine ID, a 2-byte process ID and a 3-byte counter. It is encoded
+// as 20 characters of lowercase base32hex without padding, so XIDs sort
+// lexicographically by time to the second.
+const (
+	// XIDLength is the length of an XID string
+	XIDLength = 20
+
+	// XIDCharSet is the base32hex alphabet used by XIDs
+	XIDCharSet = "0123456789abcdefghijklmnopqrstuv"
+
+	// xidBytes is the length of a raw XID
+	xidBytes = 12
+)
+
+// xidEncoding encodes raw XIDs; 96 bits fill 20 characters without padding
+var xidEncoding = base32.NewEncoding(XIDCharSet).WithPadding(base32.NoPadding)
+
+// xidCounter is shared by all generators in the process, as in the XID
+// format, so generators with the same machine ID never repeat each other's
+// counter. It starts at a random value on first use.
+var (
+	xidCounterOnce sync.Once
+	xidCounter     uint32
+)
+
+// GenerateXIDBytes creates an XID in its raw 12-byte form. The timestamp
+// comes from the generator's clock, ignoring any custom epoch. The machine
+// field holds the low 24 bits of the machine ID before it was masked to the
+// layout, so a derived machine ID keeps more of its host hash than in uint64
+// IDs. The counter is process-wide and only repeats after 2^24 XIDs from
+// the process in the same second.
+//
+// Returns: An XID as 12 bytes
+func (g *IDGenerator) GenerateXIDBytes() [12]byte {
+	xidCounterOnce.Do(func() {
+		var seed [4]byte
+		if _, err := cryptoRead(seed[:]); err == nil {
+			atomic.StoreUint32(&xidCounter, binary.BigEndian.Uint32(seed[:]))
+		}
+	})
+
+	var xid [xidBytes]byte
+	binary.BigEndian.PutUint32(xid[:4], uint32(g.clock().Unix()))
+
+	g.ensureIdentity()
+	var machine [8]byte
+	binary.BigEndian.PutUint64(machine[:], atomic.LoadUint64(&g.machineHash))
+	copy(xid[4:7], machine[5:])
+
+	binary.BigEndian.PutUint16(xid[7:9], uint16(osGetpid()))
+
+	var counter [4]byte
+	binary.BigEndian.PutUint32(counter[:], atomic.AddUint32(&xidCounter, 1))
+	copy(xid[9:], counter[1:])
+
+	return xid
+}
+
+// GenerateXID creates an identifier in the 20-character XID format, for
+// interoperating with systems that consume XIDs. See GenerateXIDBytes for
+// the field contents.
+//
+// Returns: A 20-character base32hex XID
+func (g *IDGenerator) GenerateXID() string {
+	xid := g.GenerateXIDBytes()
+	return xidEncoding.EncodeToString(xid[:])
+}
+
+// ParseXIDTime extracts the timestamp from an XID string.
+//
+// Parameters:
+//   - s: A 20-character XID
+//
+// Returns:
+//   - time.Time: The time embedded in the XID, with second precision
+//   - error: An error if s is not a valid XID
+func ParseXIDTime(s string) (time.Time, error) {
+	if len(s) != XIDLength {
+		return time.Time{}, fmt.Errorf("invalid XID length %d, expected %d", len(s), XIDLength)
+	}
+
+	var xid [xidBytes]byte
+	if _, err := xidEncoding.Decode(xid[:], []byte(s)); err != nil {
+		return time.Time{}, fmt.Errorf("invalid XID %s: %w", s, err)
+	}
+
+	return XIDTime(xid), nil
+}
+
+// XIDTime extracts the timestamp from a raw XID.
+//
+// Parameters:
+//   - xid: The 12 XID bytes
+//
+// Returns: The time embedded in the XID, with second precision
+func XIDTime(xid [12]byte) time.Time {
+	return time.Unix(int64(binary.BigEndian.Uint32(xid[:4])), 0)
+}
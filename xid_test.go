@@ -0,0 +1,85 @@
+package tsuniqid
+
+import (
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestIDGenerator_GenerateXID tests that XIDs have the standard length and
+// alphabet, carry the generator's fields, round-trip their timestamp and
+// sort by time.
+func TestIDGenerator_GenerateXID(t *testing.T) {
+	defer func(old func() int) { osGetpid = old }(osGetpid)
+	osGetpid = func() int { return 0x1234 }
+
+	start := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	clock := newFakeClock(start, time.Second)
+	gen := NewGenerator(WithMachineID(0xa), WithClock(clock.Now))
+
+	ids := make([]string, 1000)
+	seen := make(map[string]bool)
+	for i := range ids {
+		id := gen.GenerateXID()
+		ids[i] = id
+
+		if len(id) != XIDLength {
+			t.Fatalf("XID has wrong length: %s (length: %d)", id, len(id))
+		}
+		for _, c := range id {
+			if !strings.ContainsRune(XIDCharSet, c) {
+				t.Errorf("XID %s contains invalid character %q", id, c)
+			}
+		}
+		if seen[id] {
+			t.Fatalf("Duplicate XID: %s", id)
+		}
+		seen[id] = true
+
+		ts, err := ParseXIDTime(id)
+		if err != nil {
+			t.Errorf("Failed to parse %s: %v", id, err)
+		} else if want := start.Add(time.Duration(i) * time.Second); !ts.Equal(want) {
+			t.Errorf("Timestamp mismatch for %s: got %v, expected %v", id, ts, want)
+		}
+	}
+
+	if !sort.StringsAreSorted(ids) {
+		t.Errorf("XIDs with increasing timestamps are not lexicographically sorted")
+	}
+
+	raw := gen.GenerateXIDBytes()
+	if machine := raw[4:7]; machine[0] != 0 || machine[1] != 0 || machine[2] != 0xa {
+		t.Errorf("Machine bytes mismatch: got % x", machine)
+	}
+	if raw[7] != 0x12 || raw[8] != 0x34 {
+		t.Errorf("PID bytes mismatch: got % x", raw[7:9])
+	}
+}
+
+// TestParseXIDTime tests decoding of known and malformed XIDs.
+func TestParseXIDTime(t *testing.T) {
+	// Reference XID from the xid package's tests
+	ts, err := ParseXIDTime("9m4e2mr0ui3e8a215n4g")
+	if err != nil {
+		t.Fatalf("Failed to parse reference XID: %v", err)
+	}
+	if want := time.Unix(1300816219, 0); !ts.Equal(want) {
+		t.Errorf("Reference XID time mismatch: got %v, expected %v", ts, want)
+	}
+
+	raw := [12]byte{0x4d, 0x88, 0xe1, 0x5b, 0x60, 0xf4, 0x86, 0xe4, 0x28, 0x41, 0x2d, 0xc9}
+	if s := xidEncoding.EncodeToString(raw[:]); s != "9m4e2mr0ui3e8a215n4g" {
+		t.Errorf("Reference XID encoded as %s", s)
+	}
+	if !XIDTime(raw).Equal(ts) {
+		t.Errorf("XIDTime mismatch: got %v, expected %v", XIDTime(raw), ts)
+	}
+
+	for _, s := range []string{"", "9m4e2mr0ui", "9m4e2mr0ui3e8a215n4w", "9M4E2MR0UI3E8A215N4G"} {
+		if _, err := ParseXIDTime(s); err == nil {
+			t.Errorf("Expected error parsing %q", s)
+		}
+	}
+}